@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	_ "github.com/lib/pq"
+	"github.com/takumi3488/cookiejar-server/internal/config"
+	"github.com/takumi3488/cookiejar-server/internal/domain/entity"
+	"github.com/takumi3488/cookiejar-server/internal/usecase"
+)
+
+const usage = `Usage: admin <command> [flags]
+
+Commands:
+  create -name <name> -scope <scope>...  API キーを発行する（キーはこのときのみ表示されます）
+  list                                   API キーの一覧を表示する
+  revoke -id <id>                        API キーを無効化する
+
+Scopes:
+  <permission>:<host pattern> の形式で、-scope を繰り返して複数指定できます
+  permission は read / write / delete、host pattern は example.com（完全一致）・*.example.com（サブドメイン）・*（すべてのホスト）
+  例: -scope 'read:*.example.com' -scope 'write:api.example.com'
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	// データベース接続を初期化
+	dbClient, err := sql.Open("postgres", fmt.Sprintf(
+		"host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
+		os.Getenv("POSTGRES_HOST"),
+		os.Getenv("POSTGRES_PORT"),
+		os.Getenv("POSTGRES_USER"),
+		os.Getenv("POSTGRES_PASSWORD"),
+		os.Getenv("POSTGRES_DB"),
+	))
+	if err != nil {
+		panic(err)
+	}
+	defer func() {
+		if err := dbClient.Close(); err != nil {
+			log.Printf("Failed to close database connection: %v", err)
+		}
+	}()
+
+	// 依存性注入コンテナを初期化（Cookie を保存しないため公開サフィックスの検証と上限は不要）
+	container := config.NewContainer(dbClient, nil, usecase.CookieLimits{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := run(ctx, container.AuthUsecase, os.Args[1], os.Args[2:]); err != nil {
+		log.Printf("%s: %v", os.Args[1], err)
+		cancel()
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, auth usecase.AuthUsecase, command string, args []string) error {
+	switch command {
+	case "create":
+		fs := flag.NewFlagSet("create", flag.ExitOnError)
+		name := fs.String("name", "", "API キーの名前（用途や利用者を識別するため）")
+		var scopeValues stringsFlag
+		fs.Var(&scopeValues, "scope", "許可する操作とホストパターン（<permission>:<host pattern>、複数指定可）")
+		_ = fs.Parse(args)
+
+		scopes, err := entity.ParseScopes(scopeValues)
+		if err != nil {
+			return err
+		}
+		apiKey, key, err := auth.CreateAPIKey(ctx, *name, scopes)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Created API key %d (%s) with scopes %s. Store it now; it cannot be shown again.\n", apiKey.ID, apiKey.Name, strings.Join(entity.ScopeStrings(apiKey.Scopes), " "))
+		fmt.Println(key)
+		return nil
+
+	case "list":
+		apiKeys, err := auth.ListAPIKeys(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tNAME\tPREFIX\tSCOPES\tCREATED\tREVOKED")
+		for _, apiKey := range apiKeys {
+			revoked := "-"
+			if apiKey.IsRevoked() {
+				revoked = apiKey.RevokedAt.Format(time.RFC3339)
+			}
+			scopes := strings.Join(entity.ScopeStrings(apiKey.Scopes), ",")
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\n", apiKey.ID, apiKey.Name, apiKey.Prefix, scopes, apiKey.CreatedAt.Format(time.RFC3339), revoked)
+		}
+		return w.Flush()
+
+	case "revoke":
+		fs := flag.NewFlagSet("revoke", flag.ExitOnError)
+		id := fs.Int64("id", 0, "無効化する API キーの ID")
+		_ = fs.Parse(args)
+
+		if *id <= 0 {
+			return errors.New("-id is required")
+		}
+		if err := auth.RevokeAPIKey(ctx, *id); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Revoked API key %d\n", *id)
+		return nil
+	}
+
+	fmt.Fprint(os.Stderr, usage)
+	return fmt.Errorf("unknown command %q", command)
+}
+
+// stringsFlag は複数回指定できる文字列のフラグです
+type stringsFlag []string
+
+func (f *stringsFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *stringsFlag) Set(v string) error {
+	*f = append(*f, v)
+	return nil
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	_ "github.com/lib/pq"
+	"github.com/takumi3488/cookiejar-server/internal/config"
+	"github.com/takumi3488/cookiejar-server/internal/scheduler"
+	"github.com/takumi3488/cookiejar-server/internal/telemetry"
+	"github.com/takumi3488/cookiejar-server/internal/usecase"
+)
+
+func main() {
+	// run の defer でトレースを送信し、データベース接続を閉じてから終了する
+	if err := run(); err != nil {
+		log.Printf("Failed to purge expired cookies: %v", err)
+		os.Exit(1)
+	}
+}
+
+// run は期限切れの Cookie を削除します（PURGE_INTERVAL=0 の場合は1回だけ実行し、失敗した場合はエラーを返す）
+func run() error {
+	// OpenTelemetry の初期化
+	tp, err := telemetry.InitTracer("cookiejar-purger")
+	if err != nil {
+		log.Fatalf("Failed to initialize tracer: %v", err)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := telemetry.Shutdown(ctx, tp); err != nil {
+			log.Printf("Failed to shutdown tracer: %v", err)
+		}
+	}()
+
+	// 設定を読み込み（PURGE_INTERVAL=0 の場合は1回だけ実行して終了）
+	purgeConfig, err := config.LoadPurgeConfig(time.Hour)
+	if err != nil {
+		log.Fatalf("Failed to load purge config: %v", err)
+	}
+
+	// データベース接続を初期化
+	dbClient, err := sql.Open("postgres", fmt.Sprintf(
+		"host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
+		os.Getenv("POSTGRES_HOST"),
+		os.Getenv("POSTGRES_PORT"),
+		os.Getenv("POSTGRES_USER"),
+		os.Getenv("POSTGRES_PASSWORD"),
+		os.Getenv("POSTGRES_DB"),
+	))
+	if err != nil {
+		panic(err)
+	}
+	defer func() {
+		if err := dbClient.Close(); err != nil {
+			log.Printf("Failed to close database connection: %v", err)
+		}
+	}()
+
+	// 依存性注入コンテナを初期化（Cookie を保存しないため公開サフィックスの検証と上限は不要）
+	container := config.NewContainer(dbClient, nil, usecase.CookieLimits{})
+	purgeUsecase := usecase.NewPurgeUsecase(container.CookieRepo, purgeConfig.BatchSize)
+
+	purge := func(ctx context.Context) error {
+		report, err := purgeUsecase.PurgeExpired(ctx)
+		if err != nil {
+			return err
+		}
+		log.Printf("Purged %d expired cookies in %d batches", report.PurgedCookies, report.Batches)
+		return nil
+	}
+
+	// SIGINT / SIGTERM で停止
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if purgeConfig.Interval == 0 {
+		return purge(ctx)
+	}
+
+	scheduler.Run(ctx, "purge-expired-cookies", purgeConfig.Interval, purge)
+	return nil
+}
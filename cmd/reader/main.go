@@ -3,17 +3,27 @@ package main
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"net"
+	"net/http"
+	"net/url"
 	"os"
+	"slices"
 	"strings"
 	"time"
 
 	_ "github.com/lib/pq"
 	pb "github.com/takumi3488/cookiejar-server/gen/cookiejar/v1"
 	"github.com/takumi3488/cookiejar-server/internal/config"
+	"github.com/takumi3488/cookiejar-server/internal/domain/entity"
+	"github.com/takumi3488/cookiejar-server/internal/infrastructure/jwt"
+	"github.com/takumi3488/cookiejar-server/internal/infrastructure/tlscert"
+	"github.com/takumi3488/cookiejar-server/internal/middleware"
+	"github.com/takumi3488/cookiejar-server/internal/scheduler"
 	"github.com/takumi3488/cookiejar-server/internal/telemetry"
+	"github.com/takumi3488/cookiejar-server/internal/usecase"
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc/filters"
 	"go.opentelemetry.io/otel"
@@ -22,10 +32,12 @@ import (
 	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/health"
 	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type cookieServiceServer struct {
@@ -37,27 +49,130 @@ func (s *cookieServiceServer) GetCookies(ctx context.Context, req *pb.GetCookies
 	// otelgrpc が生成したルート span を取得（成功時に明示的に Ok を立て、trace レベルが UNSET にならないようにする）
 	span := trace.SpanFromContext(ctx)
 
-	// hostでCookieを取得
-	cookies, err := s.container.CookieUsecase.GetCookiesByHost(ctx, req.Host)
+	// jar の中から host で Cookie を取得
+	cookies, err := s.container.CookieUsecase.GetCookiesByHost(ctx, req.Jar, req.Host, req.TopLevelSite)
+	if err == nil && len(cookies) == 0 {
+		err = fmt.Errorf("no cookies match host %q", req.Host)
+	}
 	if err != nil {
 		// otelgrpc は NotFound 等を span status Error にマップしないため、明示的に Error を立てる
 		log.Printf("Failed to get cookies for host %s: %v", req.Host, err)
 		span.RecordError(err)
 		span.SetStatus(otelcodes.Error, "Failed to get cookies")
+		if errors.Is(err, usecase.ErrForbidden) {
+			return nil, status.Errorf(codes.PermissionDenied, "not allowed to read cookies for host: %s", req.Host)
+		}
+		if errors.Is(err, entity.ErrInvalidJarName) {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid jar: %s", req.Jar)
+		}
 		return nil, status.Errorf(codes.NotFound, "cookies not found for host: %s", req.Host)
 	}
 
-	// Cookieをhttp.Cookieに変換してからString形式に変換
+	span.SetStatus(otelcodes.Ok, "Successfully retrieved cookies")
+	return &pb.GetCookiesResponse{
+		Cookies:      formatCookies(cookies),
+		CookieHeader: entity.CookieHeader(cookies),
+		CookieList:   toProtoCookies(cookies),
+	}, nil
+}
+
+func (s *cookieServiceServer) GetCookiesForURL(ctx context.Context, req *pb.GetCookiesForURLRequest) (*pb.GetCookiesForURLResponse, error) {
+	span := trace.SpanFromContext(ctx)
+
+	// 絶対URLのみ受け付ける
+	requestURL, err := url.Parse(req.Url)
+	if err != nil || requestURL.Scheme == "" || requestURL.Hostname() == "" {
+		if err == nil {
+			err = fmt.Errorf("url must be absolute: %q", req.Url)
+		}
+		log.Printf("Invalid url %q: %v", req.Url, err)
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, "Invalid url")
+		return nil, status.Errorf(codes.InvalidArgument, "invalid url: %s", req.Url)
+	}
+
+	// jar の中から URL のドメイン・パス・スキームに一致する Cookie を取得
+	cookies, err := s.container.CookieUsecase.GetCookiesForURL(ctx, req.Jar, requestURL, req.TopLevelSite)
+	if err == nil && len(cookies) == 0 {
+		err = fmt.Errorf("no cookies match url %q", requestURL.Redacted())
+	}
+	if err != nil {
+		log.Printf("Failed to get cookies for url %s: %v", requestURL.Redacted(), err)
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, "Failed to get cookies")
+		if errors.Is(err, usecase.ErrForbidden) {
+			return nil, status.Errorf(codes.PermissionDenied, "not allowed to read cookies for url: %s", requestURL.Redacted())
+		}
+		if errors.Is(err, entity.ErrInvalidJarName) {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid jar: %s", req.Jar)
+		}
+		return nil, status.Errorf(codes.NotFound, "cookies not found for url: %s", requestURL.Redacted())
+	}
+
+	span.SetStatus(otelcodes.Ok, "Successfully retrieved cookies")
+	return &pb.GetCookiesForURLResponse{
+		Cookies:      formatCookies(cookies),
+		CookieHeader: entity.CookieHeader(cookies),
+		CookieList:   toProtoCookies(cookies),
+	}, nil
+}
+
+// formatCookies はCookieをhttp.Cookieに変換してからString形式に変換し、"; "で結合します
+// Set-Cookie 形式の属性を含むため Cookie ヘッダーとしては使えません（互換性のために残しています。cookie_header を参照）
+func formatCookies(cookies []*entity.Cookie) string {
 	var cookieStrings []string
 	for _, cookie := range cookies {
 		httpCookie := cookie.ToHTTPCookie()
 		cookieStrings = append(cookieStrings, httpCookie.String())
 	}
+	return strings.Join(cookieStrings, "; ")
+}
 
-	span.SetStatus(otelcodes.Ok, "Successfully retrieved cookies")
-	return &pb.GetCookiesResponse{
-		Cookies: strings.Join(cookieStrings, "; "),
-	}, nil
+// toProtoCookies はCookieを cookie_header と同じ順序（RFC 6265 §5.4）で proto メッセージに変換します
+func toProtoCookies(cookies []*entity.Cookie) []*pb.Cookie {
+	sorted := slices.Clone(cookies)
+	entity.SortForCookieHeader(sorted)
+
+	result := make([]*pb.Cookie, len(sorted))
+	for i, cookie := range sorted {
+		result[i] = &pb.Cookie{
+			Name:           cookie.Name,
+			Value:          cookie.Value,
+			Domain:         cookie.CanonicalDomain(),
+			Path:           cookie.CanonicalPath(),
+			Expires:        toTimestamp(cookie.Expires),
+			Secure:         cookie.Secure,
+			HttpOnly:       cookie.HttpOnly,
+			SameSite:       toProtoSameSite(cookie.SameSite),
+			HostOnly:       cookie.HostOnly,
+			Partitioned:    cookie.Partitioned,
+			CreatedAt:      toTimestamp(cookie.CreatedAt),
+			LastAccessedAt: toTimestamp(cookie.LastAccessedAt),
+			PartitionKey:   cookie.PartitionKey,
+		}
+	}
+	return result
+}
+
+// toTimestamp はゼロ値の時刻を未設定（nil）として変換します
+func toTimestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+func toProtoSameSite(sameSite http.SameSite) pb.SameSite {
+	switch sameSite {
+	case http.SameSiteLaxMode:
+		return pb.SameSite_SAME_SITE_LAX
+	case http.SameSiteStrictMode:
+		return pb.SameSite_SAME_SITE_STRICT
+	case http.SameSiteNoneMode:
+		return pb.SameSite_SAME_SITE_NONE
+	default:
+		return pb.SameSite_SAME_SITE_UNSPECIFIED
+	}
 }
 
 func main() {
@@ -113,15 +228,80 @@ func main() {
 		}
 	}()
 
-	// 依存性注入コンテナを初期化
-	container := config.NewContainer(dbClient)
+	// API キー認証の設定を読み込む
+	authConfig, err := config.LoadAuthConfig()
+	if err != nil {
+		log.Fatalf("Failed to load auth config: %v", err)
+	}
+
+	// JWT 認証の設定を読み込む
+	jwtConfig, err := config.LoadJWTConfig()
+	if err != nil {
+		log.Fatalf("Failed to load JWT config: %v", err)
+	}
+	// 認証を無効化した場合に JWT_JWKS が黙って無視されないよう、起動を中止する
+	if jwtConfig.Enabled() && !authConfig.Enabled {
+		log.Fatalf("JWT_JWKS cannot be used with AUTH_ENABLED=false")
+	}
+
+	// TLS の設定を読み込む
+	tlsConfig, err := config.LoadTLSConfig()
+	if err != nil {
+		log.Fatalf("Failed to load TLS config: %v", err)
+	}
+
+	// 依存性注入コンテナを初期化（Cookie を保存しないため公開サフィックスの検証と上限は不要）
+	container := config.NewContainer(dbClient, nil, usecase.CookieLimits{})
+
+	// JWT のベアラートークンの検証に使う JWKS を読み込む（JWT_JWKS が設定されている場合のみ、定期的に読み込み直す）
+	var tokenAuth usecase.TokenAuthUsecase
+	if jwtConfig.Enabled() {
+		keySet, err := jwt.NewKeySet(context.Background(), jwtConfig.JWKS)
+		if err != nil {
+			log.Fatalf("Failed to load JWKS: %v", err)
+		}
+		go scheduler.Run(context.Background(), "refresh-jwks", jwtConfig.RefreshInterval, keySet.Refresh)
+		tokenAuth = usecase.NewTokenAuthUsecase(
+			jwt.NewVerifier(keySet, jwtConfig.Issuer, jwtConfig.Audience),
+			usecase.TokenClaimMapping{ScopeClaim: jwtConfig.ScopeClaim, JarClaim: jwtConfig.JarClaim},
+		)
+	}
 
 	// gRPCサーバーを初期化（otelgrpc interceptorを追加、ヘルスチェックはトレース対象外）
-	grpcServer := grpc.NewServer(
+	serverOpts := []grpc.ServerOption{
 		grpc.StatsHandler(otelgrpc.NewServerHandler(
 			otelgrpc.WithFilter(filters.Not(filters.HealthCheck())),
 		)),
-	)
+	}
+	// TLS を有効化（ローテーションされた証明書は定期的に読み込み直す）
+	if tlsConfig.Enabled() {
+		reloader, err := tlscert.NewReloader(tlsConfig.CertFile, tlsConfig.KeyFile, tlsConfig.ClientCAFile)
+		if err != nil {
+			log.Fatalf("Failed to load TLS certificate: %v", err)
+		}
+		go scheduler.Run(context.Background(), "reload-tls-certificate", tlsConfig.ReloadInterval, reloader.Reload)
+		serverOpts = append(serverOpts, grpc.Creds(credentials.NewTLS(reloader.TLSConfig())))
+
+		// クライアント証明書のサブジェクトを監査用の識別情報に設定（API キー認証より前に実行する）
+		if tlsConfig.ClientCAFile != "" {
+			serverOpts = append(serverOpts,
+				grpc.ChainUnaryInterceptor(middleware.UnaryClientCertIdentity()),
+				grpc.ChainStreamInterceptor(middleware.StreamClientCertIdentity()),
+			)
+		}
+	} else {
+		log.Println("WARNING: TLS is disabled, gRPC traffic is served in plaintext (TLS_CERT_FILE is not set)")
+	}
+	// API キー・JWT 認証 interceptor を追加（ヘルスチェックは認証対象外）
+	if authConfig.Enabled {
+		serverOpts = append(serverOpts,
+			grpc.ChainUnaryInterceptor(middleware.UnaryAPIKeyAuth(container.AuthUsecase, tokenAuth)),
+			grpc.ChainStreamInterceptor(middleware.StreamAPIKeyAuth(container.AuthUsecase, tokenAuth)),
+		)
+	} else {
+		log.Println("WARNING: API key authentication is disabled (AUTH_ENABLED=false)")
+	}
+	grpcServer := grpc.NewServer(serverOpts...)
 	pb.RegisterCookieServiceServer(grpcServer, &cookieServiceServer{
 		container: container,
 	})
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	pb "github.com/takumi3488/cookiejar-server/gen/cookiejar/v1"
+	"github.com/takumi3488/cookiejar-server/internal/domain/entity"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestToProtoCookies(t *testing.T) {
+	created := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	accessed := created.Add(time.Hour)
+	expires := created.Add(24 * time.Hour)
+
+	tests := []struct {
+		name    string
+		cookies []*entity.Cookie
+		want    []*pb.Cookie
+	}{
+		{
+			name:    "Cookie なし",
+			cookies: nil,
+			want:    []*pb.Cookie{},
+		},
+		{
+			name: "ゼロ値の時刻は未設定（nil）として変換する",
+			cookies: []*entity.Cookie{
+				{Name: "sid", Value: "abc", Domain: "example.com", Path: "/"},
+			},
+			want: []*pb.Cookie{
+				{Name: "sid", Value: "abc", Domain: "example.com", Path: "/"},
+			},
+		},
+		{
+			name: "すべての属性と時刻を変換する",
+			cookies: []*entity.Cookie{
+				{
+					Name:           "sid",
+					Value:          "abc",
+					Domain:         ".Example.com",
+					Path:           "/app",
+					Expires:        expires,
+					Secure:         true,
+					HttpOnly:       true,
+					SameSite:       http.SameSiteStrictMode,
+					HostOnly:       true,
+					Partitioned:    true,
+					PartitionKey:   "https://site.example",
+					CreatedAt:      created,
+					LastAccessedAt: accessed,
+				},
+			},
+			want: []*pb.Cookie{
+				{
+					Name:           "sid",
+					Value:          "abc",
+					Domain:         "example.com",
+					Path:           "/app",
+					Expires:        timestamppb.New(expires),
+					Secure:         true,
+					HttpOnly:       true,
+					SameSite:       pb.SameSite_SAME_SITE_STRICT,
+					HostOnly:       true,
+					Partitioned:    true,
+					PartitionKey:   "https://site.example",
+					CreatedAt:      timestamppb.New(created),
+					LastAccessedAt: timestamppb.New(accessed),
+				},
+			},
+		},
+		{
+			name: "パスが空の場合は既定のパスとして変換する",
+			cookies: []*entity.Cookie{
+				{Name: "sid", Value: "abc", Domain: "example.com"},
+			},
+			want: []*pb.Cookie{
+				{Name: "sid", Value: "abc", Domain: "example.com", Path: "/"},
+			},
+		},
+		{
+			name: "cookie_header と同じくパスが長い順、作成時刻が早い順に並べる",
+			cookies: []*entity.Cookie{
+				{Name: "root", Value: "1", Domain: "example.com", Path: "/", CreatedAt: created},
+				{Name: "late", Value: "2", Domain: "example.com", Path: "/app", CreatedAt: accessed},
+				{Name: "early", Value: "3", Domain: "example.com", Path: "/app", CreatedAt: created},
+			},
+			want: []*pb.Cookie{
+				{Name: "early", Value: "3", Domain: "example.com", Path: "/app", CreatedAt: timestamppb.New(created)},
+				{Name: "late", Value: "2", Domain: "example.com", Path: "/app", CreatedAt: timestamppb.New(accessed)},
+				{Name: "root", Value: "1", Domain: "example.com", Path: "/", CreatedAt: timestamppb.New(created)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := toProtoCookies(tt.cookies)
+			if len(got) != len(tt.want) {
+				t.Fatalf("toProtoCookies() len = %v, want %v", len(got), len(tt.want))
+			}
+			for i := range got {
+				if !proto.Equal(got[i], tt.want[i]) {
+					t.Errorf("toProtoCookies()[%d] = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestToProtoCookies_DoesNotReorderInput(t *testing.T) {
+	cookies := []*entity.Cookie{
+		{Name: "root", Domain: "example.com", Path: "/"},
+		{Name: "app", Domain: "example.com", Path: "/app"},
+	}
+	toProtoCookies(cookies)
+
+	if cookies[0].Name != "root" || cookies[1].Name != "app" {
+		t.Errorf("toProtoCookies() reordered input = [%s %s], want [root app]", cookies[0].Name, cookies[1].Name)
+	}
+}
+
+func TestToProtoSameSite(t *testing.T) {
+	tests := []struct {
+		name     string
+		sameSite http.SameSite
+		want     pb.SameSite
+	}{
+		{name: "Lax", sameSite: http.SameSiteLaxMode, want: pb.SameSite_SAME_SITE_LAX},
+		{name: "Strict", sameSite: http.SameSiteStrictMode, want: pb.SameSite_SAME_SITE_STRICT},
+		{name: "None", sameSite: http.SameSiteNoneMode, want: pb.SameSite_SAME_SITE_NONE},
+		{name: "属性値なしの SameSite", sameSite: http.SameSiteDefaultMode, want: pb.SameSite_SAME_SITE_UNSPECIFIED},
+		{name: "未設定", sameSite: 0, want: pb.SameSite_SAME_SITE_UNSPECIFIED},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := toProtoSameSite(tt.sameSite); got != tt.want {
+				t.Errorf("toProtoSameSite(%v) = %v, want %v", tt.sameSite, got, tt.want)
+			}
+		})
+	}
+}
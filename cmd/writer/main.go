@@ -12,8 +12,13 @@ import (
 	"github.com/gofiber/fiber/v3"
 	"github.com/gofiber/fiber/v3/middleware/cors"
 	"github.com/takumi3488/cookiejar-server/internal/config"
+	"github.com/takumi3488/cookiejar-server/internal/infrastructure/jwt"
+	"github.com/takumi3488/cookiejar-server/internal/infrastructure/tlscert"
+	"github.com/takumi3488/cookiejar-server/internal/interface/handler"
 	"github.com/takumi3488/cookiejar-server/internal/middleware"
+	"github.com/takumi3488/cookiejar-server/internal/scheduler"
 	"github.com/takumi3488/cookiejar-server/internal/telemetry"
+	"github.com/takumi3488/cookiejar-server/internal/usecase"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
@@ -69,8 +74,72 @@ func main() {
 		}
 	}()
 
+	// 公開サフィックスの判定に使う Public Suffix List を読み込む
+	psl, err := config.LoadPublicSuffixList()
+	if err != nil {
+		log.Fatalf("Failed to load public suffix list: %v", err)
+	}
+
+	// Cookie の保存数・サイズの上限を読み込む
+	limits, err := config.LoadCookieLimits()
+	if err != nil {
+		log.Fatalf("Failed to load cookie limits: %v", err)
+	}
+
+	// API キー認証の設定を読み込む
+	authConfig, err := config.LoadAuthConfig()
+	if err != nil {
+		log.Fatalf("Failed to load auth config: %v", err)
+	}
+
+	// JWT 認証の設定を読み込む
+	jwtConfig, err := config.LoadJWTConfig()
+	if err != nil {
+		log.Fatalf("Failed to load JWT config: %v", err)
+	}
+	// 認証を無効化した場合に JWT_JWKS が黙って無視されないよう、起動を中止する
+	if jwtConfig.Enabled() && !authConfig.Enabled {
+		log.Fatalf("JWT_JWKS cannot be used with AUTH_ENABLED=false")
+	}
+
+	// TLS の設定を読み込む（クライアント証明書の検証は reader のみ対応）
+	tlsConfig, err := config.LoadTLSConfig()
+	if err != nil {
+		log.Fatalf("Failed to load TLS config: %v", err)
+	}
+	if tlsConfig.ClientCAFile != "" {
+		log.Fatalf("TLS_CLIENT_CA_FILE is only supported by the reader")
+	}
+
 	// 依存性注入コンテナを初期化
-	container := config.NewContainer(dbClient)
+	container := config.NewContainer(dbClient, psl, limits)
+
+	// JWT のベアラートークンの検証に使う JWKS を読み込む（JWT_JWKS が設定されている場合のみ、定期的に読み込み直す）
+	var tokenAuth usecase.TokenAuthUsecase
+	if jwtConfig.Enabled() {
+		keySet, err := jwt.NewKeySet(context.Background(), jwtConfig.JWKS)
+		if err != nil {
+			log.Fatalf("Failed to load JWKS: %v", err)
+		}
+		go scheduler.Run(context.Background(), "refresh-jwks", jwtConfig.RefreshInterval, keySet.Refresh)
+		tokenAuth = usecase.NewTokenAuthUsecase(
+			jwt.NewVerifier(keySet, jwtConfig.Issuer, jwtConfig.Audience),
+			usecase.TokenClaimMapping{ScopeClaim: jwtConfig.ScopeClaim, JarClaim: jwtConfig.JarClaim},
+		)
+	}
+
+	// 期限切れCookieの定期削除（PURGE_INTERVAL が設定されている場合のみ）
+	purgeConfig, err := config.LoadPurgeConfig(0)
+	if err != nil {
+		log.Fatalf("Failed to load purge config: %v", err)
+	}
+	if purgeConfig.Interval > 0 {
+		purgeUsecase := usecase.NewPurgeUsecase(container.CookieRepo, purgeConfig.BatchSize)
+		go scheduler.Run(context.Background(), "purge-expired-cookies", purgeConfig.Interval, func(ctx context.Context) error {
+			_, err := purgeUsecase.PurgeExpired(ctx)
+			return err
+		})
+	}
 
 	// 新しいFiberアプリを初期化
 	app := fiber.New()
@@ -83,23 +152,64 @@ func main() {
 
 	app.Use(cors.New(cors.Config{
 		AllowOrigins:     allowOrigins,
-		AllowMethods:     []string{"GET", "POST", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
+		AllowMethods:     []string{"GET", "POST", "DELETE", "OPTIONS"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", middleware.APIKeyHeader},
 		AllowCredentials: true,
 		MaxAge:           3600,
 		ExposeHeaders:    []string{"Content-Length"},
 	}))
 
-	// ルートを登録
-	app.Post("/", container.CookieHandler.StoreCookies)
-
-	// ヘルスチェックエンドポイント
+	// ヘルスチェックエンドポイント（認証なしで呼び出せるよう認証 middleware より前に登録）
 	app.Get("/health", func(c fiber.Ctx) error {
 		return c.JSON(fiber.Map{
 			"status": "ok",
 		})
 	})
 
-	// ポート3000でサーバーを起動
-	log.Fatal(app.Listen(":3000"))
+	// API キー・JWT 認証 middleware を追加（CORS のプリフライトリクエストは cors middleware が応答する）
+	if authConfig.Enabled {
+		app.Use(middleware.APIKeyAuth(container.AuthUsecase, tokenAuth))
+	} else {
+		log.Println("WARNING: API key authentication is disabled (AUTH_ENABLED=false)")
+	}
+
+	// jar の管理ルートを登録
+	app.Get("/jars", container.JarHandler.ListJars)
+	app.Post("/jars", container.JarHandler.CreateJar)
+	app.Post("/jars/:jar/clone", container.JarHandler.CloneJar)
+	app.Delete("/jars/:jar", container.JarHandler.DeleteJar)
+
+	// Cookie のルートを既定の jar（/）と名前付きの jar（/jars/:jar）に登録
+	registerCookieRoutes(app, container.CookieHandler)
+	registerCookieRoutes(app.Group("/jars/:jar"), container.CookieHandler)
+
+	// ポート3000でサーバーを起動（TLS が有効な場合は HTTPS、ローテーションされた証明書は定期的に読み込み直す）
+	listenConfig := fiber.ListenConfig{}
+	if tlsConfig.Enabled() {
+		reloader, err := tlscert.NewReloader(tlsConfig.CertFile, tlsConfig.KeyFile, "")
+		if err != nil {
+			log.Fatalf("Failed to load TLS certificate: %v", err)
+		}
+		go scheduler.Run(context.Background(), "reload-tls-certificate", tlsConfig.ReloadInterval, reloader.Reload)
+		listenConfig.TLSConfig = reloader.TLSConfig()
+	} else {
+		log.Println("WARNING: TLS is disabled, HTTP traffic is served in plaintext (TLS_CERT_FILE is not set)")
+	}
+	log.Fatal(app.Listen(":3000", listenConfig))
+}
+
+// registerCookieRoutes は Cookie を操作するルートを router に登録します
+func registerCookieRoutes(router fiber.Router, h *handler.CookieHandler) {
+	router.Post("/", h.StoreCookies)
+	router.Post("/set-cookie", h.StoreSetCookies)
+	router.Post("/har", h.ImportHAR)
+	router.Post("/cookies.txt", h.ImportNetscapeCookies)
+	router.Get("/cookies.txt", h.ExportNetscapeCookies)
+	router.Post("/playwright/storage-state", h.ImportStorageState)
+	router.Get("/playwright/storage-state", h.ExportStorageState)
+	router.Post("/puppeteer/cookies", h.ImportPuppeteerCookies)
+	router.Get("/puppeteer/cookies", h.ExportPuppeteerCookies)
+	router.Delete("/cookie", h.DeleteCookie)
+	router.Delete("/cookies", h.DeleteCookies)
+	router.Delete("/hosts/:host", h.DeleteHostCookies)
 }
@@ -7,26 +7,146 @@ package db
 
 import (
 	"context"
+	"database/sql"
 	"time"
+
+	"github.com/lib/pq"
 )
 
-const getCookiesByHost = `-- name: GetCookiesByHost :one
-SELECT host, cookies, updated_at FROM cookies WHERE host = $1
+const countCookies = `-- name: CountCookies :one
+SELECT COUNT(*) FROM cookie
+WHERE jar = $1
+  AND ($2::text IS NULL OR domain = $2 OR reverse(domain) LIKE $3)
+`
+
+type CountCookiesParams struct {
+	Jar              string         `json:"jar"`
+	Site             sql.NullString `json:"site"`
+	SubdomainPattern sql.NullString `json:"subdomain_pattern"`
+}
+
+func (q *Queries) CountCookies(ctx context.Context, arg CountCookiesParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countCookies, arg.Jar, arg.Site, arg.SubdomainPattern)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const deleteCookie = `-- name: DeleteCookie :execrows
+DELETE FROM cookie WHERE jar = $1 AND name = $2 AND domain = $3 AND host_only = $4 AND path = $5 AND partition_key = $6
+`
+
+type DeleteCookieParams struct {
+	Jar          string `json:"jar"`
+	Name         string `json:"name"`
+	Domain       string `json:"domain"`
+	HostOnly     bool   `json:"host_only"`
+	Path         string `json:"path"`
+	PartitionKey string `json:"partition_key"`
+}
+
+func (q *Queries) DeleteCookie(ctx context.Context, arg DeleteCookieParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteCookie, arg.Jar, arg.Name, arg.Domain, arg.HostOnly, arg.Path, arg.PartitionKey)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteCookiesByDomain = `-- name: DeleteCookiesByDomain :execrows
+DELETE FROM cookie WHERE jar = $1 AND domain = $2
 `
 
-func (q *Queries) GetCookiesByHost(ctx context.Context, host string) (Cookie, error) {
-	row := q.db.QueryRowContext(ctx, getCookiesByHost, host)
-	var i Cookie
-	err := row.Scan(&i.Host, &i.Cookies, &i.UpdatedAt)
-	return i, err
+type DeleteCookiesByDomainParams struct {
+	Jar    string `json:"jar"`
+	Domain string `json:"domain"`
+}
+
+func (q *Queries) DeleteCookiesByDomain(ctx context.Context, arg DeleteCookiesByDomainParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteCookiesByDomain, arg.Jar, arg.Domain)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteCookiesByFilter = `-- name: DeleteCookiesByFilter :execrows
+DELETE FROM cookie
+WHERE jar = $1
+  AND ($2::text IS NULL OR domain = $2)
+  AND ($3::text IS NULL OR domain = $3 OR reverse(domain) LIKE $4)
+  AND ($5::text IS NULL OR starts_with(name, $5))
+  AND (NOT $6::boolean OR expires_at <= $7::timestamptz)
+`
+
+type DeleteCookiesByFilterParams struct {
+	Jar              string         `json:"jar"`
+	Domain           sql.NullString `json:"domain"`
+	Site             sql.NullString `json:"site"`
+	SubdomainPattern sql.NullString `json:"subdomain_pattern"`
+	NamePrefix       sql.NullString `json:"name_prefix"`
+	ExpiredOnly      bool           `json:"expired_only"`
+	Now              time.Time      `json:"now"`
+}
+
+func (q *Queries) DeleteCookiesByFilter(ctx context.Context, arg DeleteCookiesByFilterParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteCookiesByFilter, arg.Jar, arg.Domain, arg.Site, arg.SubdomainPattern, arg.NamePrefix, arg.ExpiredOnly, arg.Now)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteExpiredCookies = `-- name: DeleteExpiredCookies :execrows
+DELETE FROM cookie WHERE id IN (
+    SELECT id FROM cookie WHERE expires_at <= $1::timestamptz ORDER BY expires_at LIMIT $2
+)
+`
+
+type DeleteExpiredCookiesParams struct {
+	Now       time.Time `json:"now"`
+	BatchSize int32     `json:"batch_size"`
+}
+
+func (q *Queries) DeleteExpiredCookies(ctx context.Context, arg DeleteExpiredCookiesParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteExpiredCookies, arg.Now, arg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const evictLeastRecentlyUsedCookies = `-- name: EvictLeastRecentlyUsedCookies :execrows
+DELETE FROM cookie WHERE id IN (
+    SELECT id FROM cookie
+    WHERE jar = $1
+      AND ($2::text IS NULL OR domain = $2 OR reverse(domain) LIKE $3)
+    ORDER BY last_accessed_at, id
+    LIMIT $4
+)
+`
+
+type EvictLeastRecentlyUsedCookiesParams struct {
+	Jar              string         `json:"jar"`
+	Site             sql.NullString `json:"site"`
+	SubdomainPattern sql.NullString `json:"subdomain_pattern"`
+	Count            int32          `json:"count"`
+}
+
+func (q *Queries) EvictLeastRecentlyUsedCookies(ctx context.Context, arg EvictLeastRecentlyUsedCookiesParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, evictLeastRecentlyUsedCookies, arg.Jar, arg.Site, arg.SubdomainPattern, arg.Count)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
 
 const listCookies = `-- name: ListCookies :many
-SELECT host, cookies, updated_at FROM cookies
+SELECT id, name, value, domain, host_only, path, expires_at, secure, http_only, same_site, partitioned, partition_key, created_at, updated_at, last_accessed_at, jar FROM cookie WHERE jar = $1 ORDER BY domain, path, created_at, id
 `
 
-func (q *Queries) ListCookies(ctx context.Context) ([]Cookie, error) {
-	rows, err := q.db.QueryContext(ctx, listCookies)
+func (q *Queries) ListCookies(ctx context.Context, jar string) ([]Cookie, error) {
+	rows, err := q.db.QueryContext(ctx, listCookies, jar)
 	if err != nil {
 		return nil, err
 	}
@@ -34,7 +154,24 @@ func (q *Queries) ListCookies(ctx context.Context) ([]Cookie, error) {
 	var items []Cookie
 	for rows.Next() {
 		var i Cookie
-		if err := rows.Scan(&i.Host, &i.Cookies, &i.UpdatedAt); err != nil {
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Value,
+			&i.Domain,
+			&i.HostOnly,
+			&i.Path,
+			&i.ExpiresAt,
+			&i.Secure,
+			&i.HttpOnly,
+			&i.SameSite,
+			&i.Partitioned,
+			&i.PartitionKey,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.LastAccessedAt,
+			&i.Jar,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -48,18 +185,159 @@ func (q *Queries) ListCookies(ctx context.Context) ([]Cookie, error) {
 	return items, nil
 }
 
-const upsertCookies = `-- name: UpsertCookies :exec
-INSERT INTO cookies (host, cookies, updated_at) VALUES ($1, $2, $3)
-ON CONFLICT (host) DO UPDATE SET cookies = $2, updated_at = $3
+const listCookiesByDomain = `-- name: ListCookiesByDomain :many
+SELECT id, name, value, domain, host_only, path, expires_at, secure, http_only, same_site, partitioned, partition_key, created_at, updated_at, last_accessed_at, jar FROM cookie WHERE jar = $1 AND domain = $2 ORDER BY path, created_at, id
+`
+
+type ListCookiesByDomainParams struct {
+	Jar    string `json:"jar"`
+	Domain string `json:"domain"`
+}
+
+func (q *Queries) ListCookiesByDomain(ctx context.Context, arg ListCookiesByDomainParams) ([]Cookie, error) {
+	rows, err := q.db.QueryContext(ctx, listCookiesByDomain, arg.Jar, arg.Domain)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Cookie
+	for rows.Next() {
+		var i Cookie
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Value,
+			&i.Domain,
+			&i.HostOnly,
+			&i.Path,
+			&i.ExpiresAt,
+			&i.Secure,
+			&i.HttpOnly,
+			&i.SameSite,
+			&i.Partitioned,
+			&i.PartitionKey,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.LastAccessedAt,
+			&i.Jar,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCookiesByDomains = `-- name: ListCookiesByDomains :many
+SELECT id, name, value, domain, host_only, path, expires_at, secure, http_only, same_site, partitioned, partition_key, created_at, updated_at, last_accessed_at, jar FROM cookie WHERE jar = $1 AND domain = ANY($2::text[]) ORDER BY domain, path, created_at, id
+`
+
+type ListCookiesByDomainsParams struct {
+	Jar     string   `json:"jar"`
+	Domains []string `json:"domains"`
+}
+
+func (q *Queries) ListCookiesByDomains(ctx context.Context, arg ListCookiesByDomainsParams) ([]Cookie, error) {
+	rows, err := q.db.QueryContext(ctx, listCookiesByDomains, arg.Jar, pq.Array(arg.Domains))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Cookie
+	for rows.Next() {
+		var i Cookie
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Value,
+			&i.Domain,
+			&i.HostOnly,
+			&i.Path,
+			&i.ExpiresAt,
+			&i.Secure,
+			&i.HttpOnly,
+			&i.SameSite,
+			&i.Partitioned,
+			&i.PartitionKey,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.LastAccessedAt,
+			&i.Jar,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const touchCookies = `-- name: TouchCookies :execrows
+UPDATE cookie SET last_accessed_at = $1
+FROM unnest($2::text[], $3::text[], $4::boolean[], $5::text[], $6::text[]) AS k(name, domain, host_only, path, partition_key)
+WHERE cookie.jar = $7 AND cookie.name = k.name AND cookie.domain = k.domain AND cookie.host_only = k.host_only AND cookie.path = k.path AND cookie.partition_key = k.partition_key
+`
+
+type TouchCookiesParams struct {
+	AccessedAt    time.Time `json:"accessed_at"`
+	Names         []string  `json:"names"`
+	Domains       []string  `json:"domains"`
+	HostOnlys     []bool    `json:"host_onlys"`
+	Paths         []string  `json:"paths"`
+	PartitionKeys []string  `json:"partition_keys"`
+	Jar           string    `json:"jar"`
+}
+
+func (q *Queries) TouchCookies(ctx context.Context, arg TouchCookiesParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, touchCookies, arg.AccessedAt, pq.Array(arg.Names), pq.Array(arg.Domains), pq.Array(arg.HostOnlys), pq.Array(arg.Paths), pq.Array(arg.PartitionKeys), arg.Jar)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const upsertCookie = `-- name: UpsertCookie :exec
+INSERT INTO cookie (jar, name, value, domain, host_only, path, expires_at, secure, http_only, same_site, partitioned, partition_key, created_at, updated_at, last_accessed_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $13, $13)
+ON CONFLICT (jar, name, domain, host_only, path, partition_key) DO UPDATE SET
+    value = EXCLUDED.value,
+    expires_at = EXCLUDED.expires_at,
+    secure = EXCLUDED.secure,
+    http_only = EXCLUDED.http_only,
+    same_site = EXCLUDED.same_site,
+    partitioned = EXCLUDED.partitioned,
+    updated_at = EXCLUDED.updated_at,
+    last_accessed_at = EXCLUDED.last_accessed_at
 `
 
-type UpsertCookiesParams struct {
-	Host      string    `json:"host"`
-	Cookies   string    `json:"cookies"`
-	UpdatedAt time.Time `json:"updated_at"`
+type UpsertCookieParams struct {
+	Jar          string       `json:"jar"`
+	Name         string       `json:"name"`
+	Value        string       `json:"value"`
+	Domain       string       `json:"domain"`
+	HostOnly     bool         `json:"host_only"`
+	Path         string       `json:"path"`
+	ExpiresAt    sql.NullTime `json:"expires_at"`
+	Secure       bool         `json:"secure"`
+	HttpOnly     bool         `json:"http_only"`
+	SameSite     string       `json:"same_site"`
+	Partitioned  bool         `json:"partitioned"`
+	PartitionKey string       `json:"partition_key"`
+	UpdatedAt    time.Time    `json:"updated_at"`
 }
 
-func (q *Queries) UpsertCookies(ctx context.Context, arg UpsertCookiesParams) error {
-	_, err := q.db.ExecContext(ctx, upsertCookies, arg.Host, arg.Cookies, arg.UpdatedAt)
+func (q *Queries) UpsertCookie(ctx context.Context, arg UpsertCookieParams) error {
+	_, err := q.db.ExecContext(ctx, upsertCookie, arg.Jar, arg.Name, arg.Value, arg.Domain, arg.HostOnly, arg.Path, arg.ExpiresAt, arg.Secure, arg.HttpOnly, arg.SameSite, arg.Partitioned, arg.PartitionKey, arg.UpdatedAt)
 	return err
 }
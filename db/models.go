@@ -5,11 +5,40 @@
 package db
 
 import (
+	"database/sql"
 	"time"
 )
 
+type ApiKey struct {
+	ID        int64        `json:"id"`
+	Name      string       `json:"name"`
+	Prefix    string       `json:"prefix"`
+	KeyHash   string       `json:"key_hash"`
+	CreatedAt time.Time    `json:"created_at"`
+	RevokedAt sql.NullTime `json:"revoked_at"`
+	Scopes    []string     `json:"scopes"`
+}
+
 type Cookie struct {
-	Host      string    `json:"host"`
-	Cookies   string    `json:"cookies"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID             int64        `json:"id"`
+	Name           string       `json:"name"`
+	Value          string       `json:"value"`
+	Domain         string       `json:"domain"`
+	HostOnly       bool         `json:"host_only"`
+	Path           string       `json:"path"`
+	ExpiresAt      sql.NullTime `json:"expires_at"`
+	Secure         bool         `json:"secure"`
+	HttpOnly       bool         `json:"http_only"`
+	SameSite       string       `json:"same_site"`
+	Partitioned    bool         `json:"partitioned"`
+	PartitionKey   string       `json:"partition_key"`
+	CreatedAt      time.Time    `json:"created_at"`
+	UpdatedAt      time.Time    `json:"updated_at"`
+	LastAccessedAt time.Time    `json:"last_accessed_at"`
+	Jar            string       `json:"jar"`
+}
+
+type Jar struct {
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
 }
@@ -9,6 +9,7 @@ package cookiejarv1
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -21,9 +22,68 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// SameSite は Cookie の SameSite 属性です
+type SameSite int32
+
+const (
+	// 属性なし
+	SameSite_SAME_SITE_UNSPECIFIED SameSite = 0
+	SameSite_SAME_SITE_LAX         SameSite = 1
+	SameSite_SAME_SITE_STRICT      SameSite = 2
+	SameSite_SAME_SITE_NONE        SameSite = 3
+)
+
+// Enum value maps for SameSite.
+var (
+	SameSite_name = map[int32]string{
+		0: "SAME_SITE_UNSPECIFIED",
+		1: "SAME_SITE_LAX",
+		2: "SAME_SITE_STRICT",
+		3: "SAME_SITE_NONE",
+	}
+	SameSite_value = map[string]int32{
+		"SAME_SITE_UNSPECIFIED": 0,
+		"SAME_SITE_LAX":         1,
+		"SAME_SITE_STRICT":      2,
+		"SAME_SITE_NONE":        3,
+	}
+)
+
+func (x SameSite) Enum() *SameSite {
+	p := new(SameSite)
+	*p = x
+	return p
+}
+
+func (x SameSite) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SameSite) Descriptor() protoreflect.EnumDescriptor {
+	return file_cookiejar_v1_cookie_proto_enumTypes[0].Descriptor()
+}
+
+func (SameSite) Type() protoreflect.EnumType {
+	return &file_cookiejar_v1_cookie_proto_enumTypes[0]
+}
+
+func (x SameSite) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SameSite.Descriptor instead.
+func (SameSite) EnumDescriptor() ([]byte, []int) {
+	return file_cookiejar_v1_cookie_proto_rawDescGZIP(), []int{0}
+}
+
 type GetCookiesRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Host          string                 `protobuf:"bytes,1,opt,name=host,proto3" json:"host,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Host  string                 `protobuf:"bytes,1,opt,name=host,proto3" json:"host,omitempty"`
+	// Cookie を取得する jar の名前（空の場合は既定の jar "default"）
+	Jar string `protobuf:"bytes,2,opt,name=jar,proto3" json:"jar,omitempty"`
+	// Partitioned Cookie（CHIPS）を取得するトップレベルサイト（"https://example.com" など）
+	// 空の場合はパーティションキーのない Cookie のみを返す
+	TopLevelSite  string `protobuf:"bytes,3,opt,name=top_level_site,json=topLevelSite,proto3" json:"top_level_site,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -65,9 +125,28 @@ func (x *GetCookiesRequest) GetHost() string {
 	return ""
 }
 
+func (x *GetCookiesRequest) GetJar() string {
+	if x != nil {
+		return x.Jar
+	}
+	return ""
+}
+
+func (x *GetCookiesRequest) GetTopLevelSite() string {
+	if x != nil {
+		return x.TopLevelSite
+	}
+	return ""
+}
+
 type GetCookiesResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Cookies       string                 `protobuf:"bytes,1,opt,name=cookies,proto3" json:"cookies,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Set-Cookie 形式の文字列を "; " で結合したもの（互換性のために残しています。Cookie ヘッダーには cookie_header を使用してください）
+	Cookies string `protobuf:"bytes,1,opt,name=cookies,proto3" json:"cookies,omitempty"`
+	// Cookie リクエストヘッダーの値（"name=value; name2=value2"、RFC 6265 §5.4 の順序）
+	CookieHeader string `protobuf:"bytes,2,opt,name=cookie_header,json=cookieHeader,proto3" json:"cookie_header,omitempty"`
+	// 構造化された Cookie（cookie_header と同じ順序）
+	CookieList    []*Cookie `protobuf:"bytes,3,rep,name=cookie_list,json=cookieList,proto3" json:"cookie_list,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -109,18 +188,341 @@ func (x *GetCookiesResponse) GetCookies() string {
 	return ""
 }
 
+func (x *GetCookiesResponse) GetCookieHeader() string {
+	if x != nil {
+		return x.CookieHeader
+	}
+	return ""
+}
+
+func (x *GetCookiesResponse) GetCookieList() []*Cookie {
+	if x != nil {
+		return x.CookieList
+	}
+	return nil
+}
+
+type GetCookiesForURLRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Url   string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	// Cookie を取得する jar の名前（空の場合は既定の jar "default"）
+	Jar string `protobuf:"bytes,2,opt,name=jar,proto3" json:"jar,omitempty"`
+	// Partitioned Cookie（CHIPS）を取得するトップレベルサイト（"https://example.com" など）
+	// 空の場合はパーティションキーのない Cookie のみを返す
+	TopLevelSite  string `protobuf:"bytes,3,opt,name=top_level_site,json=topLevelSite,proto3" json:"top_level_site,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCookiesForURLRequest) Reset() {
+	*x = GetCookiesForURLRequest{}
+	mi := &file_cookiejar_v1_cookie_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCookiesForURLRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCookiesForURLRequest) ProtoMessage() {}
+
+func (x *GetCookiesForURLRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cookiejar_v1_cookie_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCookiesForURLRequest.ProtoReflect.Descriptor instead.
+func (*GetCookiesForURLRequest) Descriptor() ([]byte, []int) {
+	return file_cookiejar_v1_cookie_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetCookiesForURLRequest) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *GetCookiesForURLRequest) GetJar() string {
+	if x != nil {
+		return x.Jar
+	}
+	return ""
+}
+
+func (x *GetCookiesForURLRequest) GetTopLevelSite() string {
+	if x != nil {
+		return x.TopLevelSite
+	}
+	return ""
+}
+
+type GetCookiesForURLResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Set-Cookie 形式の文字列を "; " で結合したもの（互換性のために残しています。Cookie ヘッダーには cookie_header を使用してください）
+	Cookies string `protobuf:"bytes,1,opt,name=cookies,proto3" json:"cookies,omitempty"`
+	// Cookie リクエストヘッダーの値（"name=value; name2=value2"、RFC 6265 §5.4 の順序）
+	CookieHeader string `protobuf:"bytes,2,opt,name=cookie_header,json=cookieHeader,proto3" json:"cookie_header,omitempty"`
+	// 構造化された Cookie（cookie_header と同じ順序）
+	CookieList    []*Cookie `protobuf:"bytes,3,rep,name=cookie_list,json=cookieList,proto3" json:"cookie_list,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCookiesForURLResponse) Reset() {
+	*x = GetCookiesForURLResponse{}
+	mi := &file_cookiejar_v1_cookie_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCookiesForURLResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCookiesForURLResponse) ProtoMessage() {}
+
+func (x *GetCookiesForURLResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_cookiejar_v1_cookie_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCookiesForURLResponse.ProtoReflect.Descriptor instead.
+func (*GetCookiesForURLResponse) Descriptor() ([]byte, []int) {
+	return file_cookiejar_v1_cookie_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetCookiesForURLResponse) GetCookies() string {
+	if x != nil {
+		return x.Cookies
+	}
+	return ""
+}
+
+func (x *GetCookiesForURLResponse) GetCookieHeader() string {
+	if x != nil {
+		return x.CookieHeader
+	}
+	return ""
+}
+
+func (x *GetCookiesForURLResponse) GetCookieList() []*Cookie {
+	if x != nil {
+		return x.CookieList
+	}
+	return nil
+}
+
+// Cookie は保存されている Cookie です
+type Cookie struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Name  string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Value string                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	// 先頭のドットを含まない小文字のドメイン
+	Domain string `protobuf:"bytes,3,opt,name=domain,proto3" json:"domain,omitempty"`
+	Path   string `protobuf:"bytes,4,opt,name=path,proto3" json:"path,omitempty"`
+	// 有効期限（セッション Cookie の場合は未設定）
+	Expires  *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=expires,proto3" json:"expires,omitempty"`
+	Secure   bool                   `protobuf:"varint,6,opt,name=secure,proto3" json:"secure,omitempty"`
+	HttpOnly bool                   `protobuf:"varint,7,opt,name=http_only,json=httpOnly,proto3" json:"http_only,omitempty"`
+	SameSite SameSite               `protobuf:"varint,8,opt,name=same_site,json=sameSite,proto3,enum=cookiejar.v1.SameSite" json:"same_site,omitempty"`
+	// true の場合は domain と完全に一致するホストにのみ送信される
+	HostOnly    bool `protobuf:"varint,9,opt,name=host_only,json=hostOnly,proto3" json:"host_only,omitempty"`
+	Partitioned bool `protobuf:"varint,10,opt,name=partitioned,proto3" json:"partitioned,omitempty"`
+	// 最初に保存された時刻（同じ Cookie を上書きしても変わらない）
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// 最後に設定された、または Reader から返却された時刻（このレスポンスでの返却を含む）
+	LastAccessedAt *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=last_accessed_at,json=lastAccessedAt,proto3" json:"last_accessed_at,omitempty"`
+	// Partitioned Cookie のトップレベルサイト（"https://example.com" など。パーティションキーがない場合は空）
+	PartitionKey  string `protobuf:"bytes,13,opt,name=partition_key,json=partitionKey,proto3" json:"partition_key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Cookie) Reset() {
+	*x = Cookie{}
+	mi := &file_cookiejar_v1_cookie_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Cookie) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Cookie) ProtoMessage() {}
+
+func (x *Cookie) ProtoReflect() protoreflect.Message {
+	mi := &file_cookiejar_v1_cookie_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Cookie.ProtoReflect.Descriptor instead.
+func (*Cookie) Descriptor() ([]byte, []int) {
+	return file_cookiejar_v1_cookie_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Cookie) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Cookie) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *Cookie) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *Cookie) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *Cookie) GetExpires() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Expires
+	}
+	return nil
+}
+
+func (x *Cookie) GetSecure() bool {
+	if x != nil {
+		return x.Secure
+	}
+	return false
+}
+
+func (x *Cookie) GetHttpOnly() bool {
+	if x != nil {
+		return x.HttpOnly
+	}
+	return false
+}
+
+func (x *Cookie) GetSameSite() SameSite {
+	if x != nil {
+		return x.SameSite
+	}
+	return SameSite_SAME_SITE_UNSPECIFIED
+}
+
+func (x *Cookie) GetHostOnly() bool {
+	if x != nil {
+		return x.HostOnly
+	}
+	return false
+}
+
+func (x *Cookie) GetPartitioned() bool {
+	if x != nil {
+		return x.Partitioned
+	}
+	return false
+}
+
+func (x *Cookie) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Cookie) GetLastAccessedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastAccessedAt
+	}
+	return nil
+}
+
+func (x *Cookie) GetPartitionKey() string {
+	if x != nil {
+		return x.PartitionKey
+	}
+	return ""
+}
+
 var File_cookiejar_v1_cookie_proto protoreflect.FileDescriptor
 
 const file_cookiejar_v1_cookie_proto_rawDesc = "" +
 	"\n" +
-	"\x19cookiejar/v1/cookie.proto\x12\fcookiejar.v1\"'\n" +
+	"\x19cookiejar/v1/cookie.proto\x12\fcookiejar.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"_\n" +
 	"\x11GetCookiesRequest\x12\x12\n" +
-	"\x04host\x18\x01 \x01(\tR\x04host\".\n" +
+	"\x04host\x18\x01 \x01(\tR\x04host\x12\x10\n" +
+	"\x03jar\x18\x02 \x01(\tR\x03jar\x12$\n" +
+	"\x0etop_level_site\x18\x03 \x01(\tR\ftopLevelSite\"\x8a\x01\n" +
 	"\x12GetCookiesResponse\x12\x18\n" +
-	"\acookies\x18\x01 \x01(\tR\acookies2`\n" +
+	"\acookies\x18\x01 \x01(\tR\acookies\x12#\n" +
+	"\rcookie_header\x18\x02 \x01(\tR\fcookieHeader\x125\n" +
+	"\vcookie_list\x18\x03 \x03(\v2\x14.cookiejar.v1.CookieR\n" +
+	"cookieList\"c\n" +
+	"\x17GetCookiesForURLRequest\x12\x10\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\x12\x10\n" +
+	"\x03jar\x18\x02 \x01(\tR\x03jar\x12$\n" +
+	"\x0etop_level_site\x18\x03 \x01(\tR\ftopLevelSite\"\x90\x01\n" +
+	"\x18GetCookiesForURLResponse\x12\x18\n" +
+	"\acookies\x18\x01 \x01(\tR\acookies\x12#\n" +
+	"\rcookie_header\x18\x02 \x01(\tR\fcookieHeader\x125\n" +
+	"\vcookie_list\x18\x03 \x03(\v2\x14.cookiejar.v1.CookieR\n" +
+	"cookieList\"\xe3\x03\n" +
+	"\x06Cookie\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value\x12\x16\n" +
+	"\x06domain\x18\x03 \x01(\tR\x06domain\x12\x12\n" +
+	"\x04path\x18\x04 \x01(\tR\x04path\x124\n" +
+	"\aexpires\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\aexpires\x12\x16\n" +
+	"\x06secure\x18\x06 \x01(\bR\x06secure\x12\x1b\n" +
+	"\thttp_only\x18\a \x01(\bR\bhttpOnly\x123\n" +
+	"\tsame_site\x18\b \x01(\x0e2\x16.cookiejar.v1.SameSiteR\bsameSite\x12\x1b\n" +
+	"\thost_only\x18\t \x01(\bR\bhostOnly\x12 \n" +
+	"\vpartitioned\x18\n" +
+	" \x01(\bR\vpartitioned\x129\n" +
+	"\n" +
+	"created_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12D\n" +
+	"\x10last_accessed_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\x0elastAccessedAt\x12#\n" +
+	"\rpartition_key\x18\r \x01(\tR\fpartitionKey*b\n" +
+	"\bSameSite\x12\x19\n" +
+	"\x15SAME_SITE_UNSPECIFIED\x10\x00\x12\x11\n" +
+	"\rSAME_SITE_LAX\x10\x01\x12\x14\n" +
+	"\x10SAME_SITE_STRICT\x10\x02\x12\x12\n" +
+	"\x0eSAME_SITE_NONE\x10\x032\xc3\x01\n" +
 	"\rCookieService\x12O\n" +
 	"\n" +
-	"GetCookies\x12\x1f.cookiejar.v1.GetCookiesRequest\x1a .cookiejar.v1.GetCookiesResponseB\xb5\x01\n" +
+	"GetCookies\x12\x1f.cookiejar.v1.GetCookiesRequest\x1a .cookiejar.v1.GetCookiesResponse\x12a\n" +
+	"\x10GetCookiesForURL\x12%.cookiejar.v1.GetCookiesForURLRequest\x1a&.cookiejar.v1.GetCookiesForURLResponseB\xb5\x01\n" +
 	"\x10com.cookiejar.v1B\vCookieProtoP\x01ZCgithub.com/takumi3488/cookiejar-server/gen/cookiejar/v1;cookiejarv1\xa2\x02\x03CXX\xaa\x02\fCookiejar.V1\xca\x02\fCookiejar\\V1\xe2\x02\x18Cookiejar\\V1\\GPBMetadata\xea\x02\rCookiejar::V1b\x06proto3"
 
 var (
@@ -135,19 +537,33 @@ func file_cookiejar_v1_cookie_proto_rawDescGZIP() []byte {
 	return file_cookiejar_v1_cookie_proto_rawDescData
 }
 
-var file_cookiejar_v1_cookie_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_cookiejar_v1_cookie_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_cookiejar_v1_cookie_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
 var file_cookiejar_v1_cookie_proto_goTypes = []any{
-	(*GetCookiesRequest)(nil),  // 0: cookiejar.v1.GetCookiesRequest
-	(*GetCookiesResponse)(nil), // 1: cookiejar.v1.GetCookiesResponse
+	(SameSite)(0),                    // 0: cookiejar.v1.SameSite
+	(*GetCookiesRequest)(nil),        // 1: cookiejar.v1.GetCookiesRequest
+	(*GetCookiesResponse)(nil),       // 2: cookiejar.v1.GetCookiesResponse
+	(*GetCookiesForURLRequest)(nil),  // 3: cookiejar.v1.GetCookiesForURLRequest
+	(*GetCookiesForURLResponse)(nil), // 4: cookiejar.v1.GetCookiesForURLResponse
+	(*Cookie)(nil),                   // 5: cookiejar.v1.Cookie
+	(*timestamppb.Timestamp)(nil),    // 6: google.protobuf.Timestamp
 }
 var file_cookiejar_v1_cookie_proto_depIdxs = []int32{
-	0, // 0: cookiejar.v1.CookieService.GetCookies:input_type -> cookiejar.v1.GetCookiesRequest
-	1, // 1: cookiejar.v1.CookieService.GetCookies:output_type -> cookiejar.v1.GetCookiesResponse
-	1, // [1:2] is the sub-list for method output_type
-	0, // [0:1] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	5, // 0: cookiejar.v1.GetCookiesResponse.cookie_list:type_name -> cookiejar.v1.Cookie
+	5, // 1: cookiejar.v1.GetCookiesForURLResponse.cookie_list:type_name -> cookiejar.v1.Cookie
+	6, // 2: cookiejar.v1.Cookie.expires:type_name -> google.protobuf.Timestamp
+	0, // 3: cookiejar.v1.Cookie.same_site:type_name -> cookiejar.v1.SameSite
+	6, // 4: cookiejar.v1.Cookie.created_at:type_name -> google.protobuf.Timestamp
+	6, // 5: cookiejar.v1.Cookie.last_accessed_at:type_name -> google.protobuf.Timestamp
+	1, // 6: cookiejar.v1.CookieService.GetCookies:input_type -> cookiejar.v1.GetCookiesRequest
+	3, // 7: cookiejar.v1.CookieService.GetCookiesForURL:input_type -> cookiejar.v1.GetCookiesForURLRequest
+	2, // 8: cookiejar.v1.CookieService.GetCookies:output_type -> cookiejar.v1.GetCookiesResponse
+	4, // 9: cookiejar.v1.CookieService.GetCookiesForURL:output_type -> cookiejar.v1.GetCookiesForURLResponse
+	8, // [8:10] is the sub-list for method output_type
+	6, // [6:8] is the sub-list for method input_type
+	6, // [6:6] is the sub-list for extension type_name
+	6, // [6:6] is the sub-list for extension extendee
+	0, // [0:6] is the sub-list for field type_name
 }
 
 func init() { file_cookiejar_v1_cookie_proto_init() }
@@ -160,13 +576,14 @@ func file_cookiejar_v1_cookie_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_cookiejar_v1_cookie_proto_rawDesc), len(file_cookiejar_v1_cookie_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   2,
+			NumEnums:      1,
+			NumMessages:   5,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_cookiejar_v1_cookie_proto_goTypes,
 		DependencyIndexes: file_cookiejar_v1_cookie_proto_depIdxs,
+		EnumInfos:         file_cookiejar_v1_cookie_proto_enumTypes,
 		MessageInfos:      file_cookiejar_v1_cookie_proto_msgTypes,
 	}.Build()
 	File_cookiejar_v1_cookie_proto = out.File
@@ -19,7 +19,8 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	CookieService_GetCookies_FullMethodName = "/cookiejar.v1.CookieService/GetCookies"
+	CookieService_GetCookies_FullMethodName       = "/cookiejar.v1.CookieService/GetCookies"
+	CookieService_GetCookiesForURL_FullMethodName = "/cookiejar.v1.CookieService/GetCookiesForURL"
 )
 
 // CookieServiceClient is the client API for CookieService service.
@@ -27,6 +28,7 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type CookieServiceClient interface {
 	GetCookies(ctx context.Context, in *GetCookiesRequest, opts ...grpc.CallOption) (*GetCookiesResponse, error)
+	GetCookiesForURL(ctx context.Context, in *GetCookiesForURLRequest, opts ...grpc.CallOption) (*GetCookiesForURLResponse, error)
 }
 
 type cookieServiceClient struct {
@@ -47,11 +49,22 @@ func (c *cookieServiceClient) GetCookies(ctx context.Context, in *GetCookiesRequ
 	return out, nil
 }
 
+func (c *cookieServiceClient) GetCookiesForURL(ctx context.Context, in *GetCookiesForURLRequest, opts ...grpc.CallOption) (*GetCookiesForURLResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetCookiesForURLResponse)
+	err := c.cc.Invoke(ctx, CookieService_GetCookiesForURL_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // CookieServiceServer is the server API for CookieService service.
 // All implementations must embed UnimplementedCookieServiceServer
 // for forward compatibility.
 type CookieServiceServer interface {
 	GetCookies(context.Context, *GetCookiesRequest) (*GetCookiesResponse, error)
+	GetCookiesForURL(context.Context, *GetCookiesForURLRequest) (*GetCookiesForURLResponse, error)
 	mustEmbedUnimplementedCookieServiceServer()
 }
 
@@ -65,6 +78,9 @@ type UnimplementedCookieServiceServer struct{}
 func (UnimplementedCookieServiceServer) GetCookies(context.Context, *GetCookiesRequest) (*GetCookiesResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetCookies not implemented")
 }
+func (UnimplementedCookieServiceServer) GetCookiesForURL(context.Context, *GetCookiesForURLRequest) (*GetCookiesForURLResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetCookiesForURL not implemented")
+}
 func (UnimplementedCookieServiceServer) mustEmbedUnimplementedCookieServiceServer() {}
 func (UnimplementedCookieServiceServer) testEmbeddedByValue()                       {}
 
@@ -104,6 +120,24 @@ func _CookieService_GetCookies_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CookieService_GetCookiesForURL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCookiesForURLRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CookieServiceServer).GetCookiesForURL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CookieService_GetCookiesForURL_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CookieServiceServer).GetCookiesForURL(ctx, req.(*GetCookiesForURLRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // CookieService_ServiceDesc is the grpc.ServiceDesc for CookieService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -115,6 +149,10 @@ var CookieService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetCookies",
 			Handler:    _CookieService_GetCookies_Handler,
 		},
+		{
+			MethodName: "GetCookiesForURL",
+			Handler:    _CookieService_GetCookiesForURL_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "cookiejar/v1/cookie.proto",
@@ -0,0 +1,28 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// AuthConfig は API キー認証の設定です
+type AuthConfig struct {
+	// false の場合は認証せずにすべてのリクエストを受け付ける（ローカル開発・E2E テスト用）
+	Enabled bool
+}
+
+// LoadAuthConfig は環境変数 AUTH_ENABLED から設定を読み込みます（未設定の場合は認証を有効にします）
+func LoadAuthConfig() (AuthConfig, error) {
+	cfg := AuthConfig{Enabled: true}
+
+	if v := os.Getenv("AUTH_ENABLED"); v != "" {
+		enabled, err := strconv.ParseBool(v)
+		if err != nil {
+			return cfg, fmt.Errorf("invalid AUTH_ENABLED %q: must be a boolean", v)
+		}
+		cfg.Enabled = enabled
+	}
+
+	return cfg, nil
+}
@@ -4,6 +4,7 @@ import (
 	"database/sql"
 
 	"github.com/takumi3488/cookiejar-server/db"
+	"github.com/takumi3488/cookiejar-server/internal/domain/entity"
 	"github.com/takumi3488/cookiejar-server/internal/domain/repository"
 	"github.com/takumi3488/cookiejar-server/internal/infrastructure/persistence"
 	"github.com/takumi3488/cookiejar-server/internal/interface/handler"
@@ -17,34 +18,52 @@ type Container struct {
 
 	// リポジトリ
 	CookieRepo repository.CookieRepository
+	APIKeyRepo repository.APIKeyRepository
+	JarRepo    repository.JarRepository
 
 	// ユースケース
 	CookieUsecase usecase.CookieUsecase
+	AuthUsecase   usecase.AuthUsecase
+	JarUsecase    usecase.JarUsecase
 
 	// ハンドラー
 	CookieHandler *handler.CookieHandler
+	JarHandler    *handler.JarHandler
 }
 
-func NewContainer(dbConn *sql.DB) *Container {
+// NewContainer は依存関係を組み立てます
+// psl は Cookie の保存時に公開サフィックスの判定に使います（nil の場合は検証しない）
+// limits は Cookie の保存時に適用する保存数・サイズの上限です
+func NewContainer(dbConn *sql.DB, psl entity.PublicSuffixList, limits usecase.CookieLimits) *Container {
 	queries := db.New(dbConn)
 
 	// リポジトリを初期化
-	cookieRepo := persistence.NewCookieRepository(queries)
+	cookieRepo := persistence.NewCookieRepository(dbConn, queries)
+	apiKeyRepo := persistence.NewAPIKeyRepository(queries)
+	jarRepo := persistence.NewJarRepository(dbConn, queries)
 
 	// ユースケースを初期化
-	cookieUsecase := usecase.NewCookieUsecase(cookieRepo)
+	cookieUsecase := usecase.NewCookieUsecase(cookieRepo, psl, limits)
+	authUsecase := usecase.NewAuthUsecase(apiKeyRepo)
+	jarUsecase := usecase.NewJarUsecase(jarRepo)
 
 	// ハンドラーを初期化
-	cookieHandler := handler.NewCookieHandler(cookieUsecase)
+	cookieHandler := handler.NewCookieHandler(cookieUsecase, psl)
+	jarHandler := handler.NewJarHandler(jarUsecase)
 
 	return &Container{
 		DB:      dbConn,
 		Queries: queries,
 
 		CookieRepo: cookieRepo,
+		APIKeyRepo: apiKeyRepo,
+		JarRepo:    jarRepo,
 
 		CookieUsecase: cookieUsecase,
+		AuthUsecase:   authUsecase,
+		JarUsecase:    jarUsecase,
 
 		CookieHandler: cookieHandler,
+		JarHandler:    jarHandler,
 	}
 }
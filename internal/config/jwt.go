@@ -0,0 +1,63 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+const (
+	// DefaultJWKSRefreshInterval は JWKS を読み込み直す既定の間隔です
+	DefaultJWKSRefreshInterval = 5 * time.Minute
+	// DefaultJWTScopeClaim はスコープを含む既定のクレームです（OAuth 2.0 の scope と同じ名前）
+	DefaultJWTScopeClaim = "scope"
+	// DefaultJWTJarClaim は操作を許可する jar を含む既定のクレームです
+	DefaultJWTJarClaim = "jars"
+)
+
+// JWTConfig は JWT（ベアラートークン）認証の設定です（JWKS が空の場合は JWT を受け付けない）
+type JWTConfig struct {
+	// JWKS は公開鍵の JWKS のファイルのパスまたは URL（http:// / https://）
+	JWKS string
+	// 空でない場合は iss・aud クレームを検証する
+	Issuer   string
+	Audience string
+	// クレームと権限の対応
+	ScopeClaim string
+	JarClaim   string
+	// JWKS を読み込み直す間隔
+	RefreshInterval time.Duration
+}
+
+// Enabled は JWT を受け付けるかを返します
+func (c JWTConfig) Enabled() bool {
+	return c.JWKS != ""
+}
+
+// LoadJWTConfig は環境変数 JWT_JWKS / JWT_ISSUER / JWT_AUDIENCE / JWT_SCOPE_CLAIM / JWT_JAR_CLAIM / JWT_JWKS_REFRESH_INTERVAL から設定を読み込みます
+func LoadJWTConfig() (JWTConfig, error) {
+	cfg := JWTConfig{
+		JWKS:            os.Getenv("JWT_JWKS"),
+		Issuer:          os.Getenv("JWT_ISSUER"),
+		Audience:        os.Getenv("JWT_AUDIENCE"),
+		ScopeClaim:      DefaultJWTScopeClaim,
+		JarClaim:        DefaultJWTJarClaim,
+		RefreshInterval: DefaultJWKSRefreshInterval,
+	}
+
+	if v := os.Getenv("JWT_SCOPE_CLAIM"); v != "" {
+		cfg.ScopeClaim = v
+	}
+	if v := os.Getenv("JWT_JAR_CLAIM"); v != "" {
+		cfg.JarClaim = v
+	}
+	if v := os.Getenv("JWT_JWKS_REFRESH_INTERVAL"); v != "" {
+		interval, err := time.ParseDuration(v)
+		if err != nil || interval <= 0 {
+			return cfg, fmt.Errorf("invalid JWT_JWKS_REFRESH_INTERVAL %q: must be a positive duration", v)
+		}
+		cfg.RefreshInterval = interval
+	}
+
+	return cfg, nil
+}
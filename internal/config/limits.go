@@ -0,0 +1,36 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/takumi3488/cookiejar-server/internal/usecase"
+)
+
+// LoadCookieLimits は環境変数 COOKIE_MAX_SIZE / COOKIE_MAX_PER_DOMAIN / COOKIE_MAX_TOTAL から Cookie の上限を読み込みます
+// 未設定の場合は既定の上限を使い、0 を指定した場合は上限なしになります
+func LoadCookieLimits() (usecase.CookieLimits, error) {
+	limits := usecase.DefaultCookieLimits()
+
+	for _, env := range []struct {
+		name  string
+		value *int
+	}{
+		{name: "COOKIE_MAX_SIZE", value: &limits.MaxCookieSize},
+		{name: "COOKIE_MAX_PER_DOMAIN", value: &limits.MaxCookiesPerDomain},
+		{name: "COOKIE_MAX_TOTAL", value: &limits.MaxCookies},
+	} {
+		v := os.Getenv(env.name)
+		if v == "" {
+			continue
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return limits, fmt.Errorf("invalid %s %q: must be a non-negative integer", env.name, v)
+		}
+		*env.value = n
+	}
+
+	return limits, nil
+}
@@ -0,0 +1,25 @@
+package config
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/takumi3488/cookiejar-server/internal/infrastructure/publicsuffix"
+)
+
+// LoadPublicSuffixList は環境変数 PUBLIC_SUFFIX_LIST_FILE で指定されたファイルから Public Suffix List を読み込みます
+// 未設定の場合は埋め込みのリストを使います
+func LoadPublicSuffixList() (*publicsuffix.List, error) {
+	path := os.Getenv("PUBLIC_SUFFIX_LIST_FILE")
+	if path == "" {
+		return publicsuffix.Default(), nil
+	}
+
+	psl, err := publicsuffix.Load(path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid PUBLIC_SUFFIX_LIST_FILE %q: %w", path, err)
+	}
+	log.Printf("Loaded public suffix list from %s (%d rules)", path, psl.Len())
+	return psl, nil
+}
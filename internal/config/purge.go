@@ -0,0 +1,45 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/takumi3488/cookiejar-server/internal/usecase"
+)
+
+// PurgeConfig は期限切れCookieの定期削除の設定です
+type PurgeConfig struct {
+	// 実行間隔（0 の場合は定期実行しない）
+	Interval time.Duration
+	// 1バッチで削除するCookie数
+	BatchSize int
+}
+
+// LoadPurgeConfig は環境変数 PURGE_INTERVAL / PURGE_BATCH_SIZE から設定を読み込みます
+// PURGE_INTERVAL が未設定の場合は defaultInterval を使います
+func LoadPurgeConfig(defaultInterval time.Duration) (PurgeConfig, error) {
+	cfg := PurgeConfig{
+		Interval:  defaultInterval,
+		BatchSize: usecase.DefaultPurgeBatchSize,
+	}
+
+	if v := os.Getenv("PURGE_INTERVAL"); v != "" {
+		interval, err := time.ParseDuration(v)
+		if err != nil || interval < 0 {
+			return cfg, fmt.Errorf("invalid PURGE_INTERVAL %q: must be a non-negative duration", v)
+		}
+		cfg.Interval = interval
+	}
+
+	if v := os.Getenv("PURGE_BATCH_SIZE"); v != "" {
+		batchSize, err := strconv.Atoi(v)
+		if err != nil || batchSize <= 0 {
+			return cfg, fmt.Errorf("invalid PURGE_BATCH_SIZE %q: must be a positive integer", v)
+		}
+		cfg.BatchSize = batchSize
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,53 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// DefaultTLSReloadInterval は証明書ファイルの更新を確認する既定の間隔です
+const DefaultTLSReloadInterval = time.Minute
+
+// TLSConfig は TLS の設定です（CertFile が空の場合は TLS を使わない）
+type TLSConfig struct {
+	CertFile string
+	KeyFile  string
+	// クライアント証明書を検証する CA（空の場合はクライアント証明書を要求しない）
+	ClientCAFile string
+	// 証明書ファイルの更新を確認する間隔
+	ReloadInterval time.Duration
+}
+
+// Enabled は TLS を使うかを返します
+func (c TLSConfig) Enabled() bool {
+	return c.CertFile != ""
+}
+
+// LoadTLSConfig は環境変数 TLS_CERT_FILE / TLS_KEY_FILE / TLS_CLIENT_CA_FILE / TLS_RELOAD_INTERVAL から設定を読み込みます
+// 証明書と秘密鍵はどちらも指定するか、どちらも指定しない必要があります
+func LoadTLSConfig() (TLSConfig, error) {
+	cfg := TLSConfig{
+		CertFile:       os.Getenv("TLS_CERT_FILE"),
+		KeyFile:        os.Getenv("TLS_KEY_FILE"),
+		ClientCAFile:   os.Getenv("TLS_CLIENT_CA_FILE"),
+		ReloadInterval: DefaultTLSReloadInterval,
+	}
+
+	if (cfg.CertFile == "") != (cfg.KeyFile == "") {
+		return cfg, fmt.Errorf("TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+	}
+	if cfg.ClientCAFile != "" && !cfg.Enabled() {
+		return cfg, fmt.Errorf("TLS_CLIENT_CA_FILE requires TLS_CERT_FILE and TLS_KEY_FILE")
+	}
+
+	if v := os.Getenv("TLS_RELOAD_INTERVAL"); v != "" {
+		interval, err := time.ParseDuration(v)
+		if err != nil || interval <= 0 {
+			return cfg, fmt.Errorf("invalid TLS_RELOAD_INTERVAL %q: must be a positive duration", v)
+		}
+		cfg.ReloadInterval = interval
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,71 @@
+package entity
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+)
+
+// APIKeyPrefix は API キーの先頭に付ける文字列です（ログやソースコード中のキーを見分けやすくするため）
+const APIKeyPrefix = "cjk_"
+
+// apiKeyDisplayLength は識別用に表示するキーの先頭部分の長さです
+const apiKeyDisplayLength = len(APIKeyPrefix) + 8
+
+// APIKey は Writer / Reader の認証に使う API キーです（キー自体は保存せずハッシュのみ保存します）
+type APIKey struct {
+	ID   int64
+	Name string
+	// Prefix は識別用に表示するキーの先頭部分
+	Prefix    string
+	CreatedAt time.Time
+	// RevokedAt は無効化された時刻（有効な場合はゼロ値）
+	RevokedAt time.Time
+	Scopes    []Scope
+}
+
+// IsRevoked は API キーが無効化されているかを返します
+func (k *APIKey) IsRevoked() bool {
+	return !k.RevokedAt.IsZero()
+}
+
+// Allows は API キーのいずれかのスコープが host に対する permission の操作を許可するかを判定します
+func (k *APIKey) Allows(permission Permission, host string) bool {
+	for _, scope := range k.Scopes {
+		if scope.Allows(permission, host) {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsJar は API キーが jar の操作を許可するかを判定します（API キーのスコープはすべての jar に共通です）
+func (k *APIKey) AllowsJar(string) bool {
+	return true
+}
+
+// GenerateAPIKey は 256 ビットの乱数から新しい API キーを生成します
+func GenerateAPIKey() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return APIKeyPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashAPIKey は API キーを保存・照合するための SHA-256 ハッシュ（16進数）を返します
+// キーは十分な長さの乱数のため、パスワードのような低速なハッシュは使いません
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// APIKeyDisplayPrefix は識別用に表示する API キーの先頭部分を返します
+func APIKeyDisplayPrefix(key string) string {
+	if len(key) <= apiKeyDisplayLength {
+		return key
+	}
+	return key[:apiKeyDisplayLength]
+}
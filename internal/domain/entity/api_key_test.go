@@ -0,0 +1,98 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGenerateAPIKey(t *testing.T) {
+	key, err := GenerateAPIKey()
+	if err != nil {
+		t.Fatalf("GenerateAPIKey() error = %v", err)
+	}
+	if !strings.HasPrefix(key, APIKeyPrefix) {
+		t.Errorf("GenerateAPIKey() = %q, want prefix %q", key, APIKeyPrefix)
+	}
+	// 32 バイトの乱数を base64url（パディングなし）でエンコードすると 43 文字
+	if got := len(key) - len(APIKeyPrefix); got != 43 {
+		t.Errorf("GenerateAPIKey() random part length = %v, want 43", got)
+	}
+
+	other, err := GenerateAPIKey()
+	if err != nil {
+		t.Fatalf("GenerateAPIKey() error = %v", err)
+	}
+	if key == other {
+		t.Error("GenerateAPIKey() returned the same key twice")
+	}
+}
+
+func TestHashAPIKey(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+		same bool
+	}{
+		{
+			name: "同じキーは同じハッシュになる",
+			a:    "cjk_abc",
+			b:    "cjk_abc",
+			same: true,
+		},
+		{
+			name: "異なるキーは異なるハッシュになる",
+			a:    "cjk_abc",
+			b:    "cjk_abd",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := HashAPIKey(tt.a), HashAPIKey(tt.b)
+			if len(a) != 64 {
+				t.Errorf("HashAPIKey() length = %v, want 64", len(a))
+			}
+			if (a == b) != tt.same {
+				t.Errorf("HashAPIKey(%q) == HashAPIKey(%q) is %v, want %v", tt.a, tt.b, a == b, tt.same)
+			}
+		})
+	}
+}
+
+func TestAPIKeyDisplayPrefix(t *testing.T) {
+	tests := []struct {
+		name string
+		key  string
+		want string
+	}{
+		{
+			name: "先頭部分のみを返す",
+			key:  "cjk_abcdefghijklmnop",
+			want: "cjk_abcdefgh",
+		},
+		{
+			name: "短いキーはそのまま返す",
+			key:  "cjk_abc",
+			want: "cjk_abc",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := APIKeyDisplayPrefix(tt.key); got != tt.want {
+				t.Errorf("APIKeyDisplayPrefix() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAPIKey_IsRevoked(t *testing.T) {
+	if (&APIKey{}).IsRevoked() {
+		t.Error("IsRevoked() = true, want false")
+	}
+	if !(&APIKey{RevokedAt: time.Now()}).IsRevoked() {
+		t.Error("IsRevoked() = false, want true")
+	}
+}
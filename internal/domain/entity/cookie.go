@@ -1,7 +1,10 @@
 package entity
 
 import (
+	"net"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 )
 
@@ -14,30 +17,243 @@ type Cookie struct {
 	Secure   bool
 	HttpOnly bool
 	SameSite http.SameSite
+	// HostOnly が true の Cookie は Domain と完全に一致するホストにのみ送信される（RFC 6265 §5.3 step 6）
+	HostOnly bool
+	// Partitioned が true の Cookie はトップレベルサイトごとに分離して保存される（CHIPS）
+	Partitioned bool
+	// PartitionKey は Partitioned Cookie のトップレベルサイト（"https://example.com" など）
+	// ブラウザから取り込んだ場合のみ設定され、Set-Cookie ヘッダーから取り込んだ場合は空になる
+	PartitionKey string
+	// CreatedAt は Cookie が最初に保存された時刻（同名 Cookie の上書きでは変わらない）
+	CreatedAt time.Time
+	// LastAccessedAt は Cookie が最後に設定された、または Reader から返却された時刻（RFC 6265 §5.3 step 3、§5.4 step 3）
+	LastAccessedAt time.Time
 }
 
 func NewCookie(httpCookie *http.Cookie) *Cookie {
+	return NewCookieAt(httpCookie, time.Now())
+}
+
+// NewCookieAt は now を基準に MaxAge を絶対的な有効期限（Expires）へ変換して Cookie を生成します
+// RFC 6265 §5.2.2 に従い MaxAge は Expires より優先され、0 未満の場合は即時失効となります
+func NewCookieAt(httpCookie *http.Cookie, now time.Time) *Cookie {
+	expires := httpCookie.Expires
+	switch {
+	case httpCookie.MaxAge > 0:
+		expires = now.Add(time.Duration(httpCookie.MaxAge) * time.Second)
+	case httpCookie.MaxAge < 0:
+		expires = time.Unix(0, 0).UTC()
+	}
+
 	return &Cookie{
-		Name:     httpCookie.Name,
-		Value:    httpCookie.Value,
-		Domain:   httpCookie.Domain,
-		Path:     httpCookie.Path,
-		Expires:  httpCookie.Expires,
-		Secure:   httpCookie.Secure,
-		HttpOnly: httpCookie.HttpOnly,
-		SameSite: httpCookie.SameSite,
+		Name:        httpCookie.Name,
+		Value:       httpCookie.Value,
+		Domain:      httpCookie.Domain,
+		Path:        httpCookie.Path,
+		Expires:     expires,
+		Secure:      httpCookie.Secure,
+		HttpOnly:    httpCookie.HttpOnly,
+		SameSite:    httpCookie.SameSite,
+		Partitioned: httpCookie.Partitioned,
 	}
 }
 
+// IsExpired は Cookie が now 時点で失効しているかを判定します
+// Expires が未設定のセッション Cookie は失効しません
+func (c *Cookie) IsExpired(now time.Time) bool {
+	return !c.Expires.IsZero() && !c.Expires.After(now)
+}
+
+// RemoveExpired は now 時点で失効していない Cookie のみを返します
+func RemoveExpired(cookies []*Cookie, now time.Time) []*Cookie {
+	result := make([]*Cookie, 0, len(cookies))
+	for _, cookie := range cookies {
+		if !cookie.IsExpired(now) {
+			result = append(result, cookie)
+		}
+	}
+	return result
+}
+
 func (c *Cookie) ToHTTPCookie() *http.Cookie {
 	return &http.Cookie{
-		Name:     c.Name,
-		Value:    c.Value,
-		Domain:   c.Domain,
-		Path:     c.Path,
-		Expires:  c.Expires,
-		Secure:   c.Secure,
-		HttpOnly: c.HttpOnly,
-		SameSite: c.SameSite,
+		Name:        c.Name,
+		Value:       c.Value,
+		Domain:      c.Domain,
+		Path:        c.Path,
+		Expires:     c.Expires,
+		Secure:      c.Secure,
+		HttpOnly:    c.HttpOnly,
+		SameSite:    c.SameSite,
+		Partitioned: c.Partitioned,
+	}
+}
+
+// CookieKey は保存済みCookieを一意に識別するキーです
+// RFC 6265 §5.3 step 11 に従い、名前・ドメイン・host-only フラグ・パスの組で識別します
+// CHIPS と同様に、パーティションキーが異なる Cookie は別の Cookie として扱います
+type CookieKey struct {
+	Name         string
+	Domain       string
+	HostOnly     bool
+	Path         string
+	PartitionKey string
+}
+
+// Key は Cookie の識別キーを返します（Domain と Path は正規化されます）
+func (c *Cookie) Key() CookieKey {
+	return CookieKey{
+		Name:         c.Name,
+		Domain:       c.CanonicalDomain(),
+		HostOnly:     c.HostOnly,
+		Path:         c.CanonicalPath(),
+		PartitionKey: c.PartitionKey,
+	}
+}
+
+// MergeCookies は existing に incoming をマージした結果を返します
+// 識別キーが同じ Cookie は incoming で置き換え、既存の順序を保ったまま新しい Cookie を末尾に追加します
+func MergeCookies(existing, incoming []*Cookie) []*Cookie {
+	merged := make([]*Cookie, 0, len(existing)+len(incoming))
+	index := make(map[CookieKey]int, len(existing)+len(incoming))
+	for _, cookies := range [][]*Cookie{existing, incoming} {
+		for _, cookie := range cookies {
+			key := cookie.Key()
+			if i, ok := index[key]; ok {
+				merged[i] = cookie
+				continue
+			}
+			index[key] = len(merged)
+			merged = append(merged, cookie)
+		}
+	}
+	return merged
+}
+
+// CanonicalDomain は Domain から先頭のドットを取り除き、小文字化した値を返します
+func (c *Cookie) CanonicalDomain() string {
+	return CanonicalizeHost(strings.TrimPrefix(c.Domain, "."))
+}
+
+// MatchesHost は Cookie が指定されたホストへ送信されるべきかを判定します
+func (c *Cookie) MatchesHost(host string) bool {
+	host = CanonicalizeHost(host)
+	domain := c.CanonicalDomain()
+	if c.HostOnly {
+		return host == domain
+	}
+	return DomainMatch(host, domain)
+}
+
+// CanonicalPath は Path が未設定の場合に "/" を返します
+func (c *Cookie) CanonicalPath() string {
+	if c.Path == "" || !strings.HasPrefix(c.Path, "/") {
+		return "/"
+	}
+	return c.Path
+}
+
+// MatchesURL は Cookie がリクエスト URL に送信されるべきかを判定します
+// domain-match、path-match（RFC 6265 §5.1.4）、Secure 属性とスキームの整合性を確認します
+func (c *Cookie) MatchesURL(u *url.URL) bool {
+	if !c.MatchesHost(u.Hostname()) {
+		return false
+	}
+	if !PathMatch(u.EscapedPath(), c.CanonicalPath()) {
+		return false
+	}
+	if c.Secure && !IsSecureScheme(u.Scheme) {
+		return false
+	}
+	return true
+}
+
+// MatchesPartition は Cookie がトップレベルサイト topLevelSite のページからのリクエストに送信されるべきかを判定します
+// パーティションキーのない Cookie はすべてのトップレベルサイトに、Partitioned Cookie は同じトップレベルサイトにのみ送信される（CHIPS）
+func (c *Cookie) MatchesPartition(topLevelSite string) bool {
+	return c.PartitionKey == "" || canonicalTopLevelSite(c.PartitionKey) == canonicalTopLevelSite(topLevelSite)
+}
+
+// RemoveOtherPartitions はトップレベルサイト topLevelSite のページからのリクエストに送信される Cookie のみを返します
+func RemoveOtherPartitions(cookies []*Cookie, topLevelSite string) []*Cookie {
+	result := make([]*Cookie, 0, len(cookies))
+	for _, cookie := range cookies {
+		if cookie.MatchesPartition(topLevelSite) {
+			result = append(result, cookie)
+		}
+	}
+	return result
+}
+
+// canonicalTopLevelSite はトップレベルサイトを比較できるよう、小文字化して末尾の "/" を取り除きます
+func canonicalTopLevelSite(site string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(site)), "/")
+}
+
+// IsSecureScheme は Secure 属性付きの Cookie を送信できるスキームかを判定します
+func IsSecureScheme(scheme string) bool {
+	switch strings.ToLower(scheme) {
+	case "https", "wss":
+		return true
+	}
+	return false
+}
+
+// PathMatch は RFC 6265 §5.1.4 の path-match を判定します
+func PathMatch(requestPath, cookiePath string) bool {
+	if requestPath == "" {
+		requestPath = "/"
+	}
+	if requestPath == cookiePath {
+		return true
+	}
+	if !strings.HasPrefix(requestPath, cookiePath) {
+		return false
+	}
+	// cookie-path が "/" で終わる、またはプレフィックス直後の文字が "/" の場合のみマッチ
+	return strings.HasSuffix(cookiePath, "/") || requestPath[len(cookiePath)] == '/'
+}
+
+// CanonicalizeHost はホスト名を小文字化し、末尾のドットを取り除きます
+func CanonicalizeHost(host string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(host)), ".")
+}
+
+// DomainMatch は RFC 6265 §5.1.3 の domain-match を判定します
+// host と domain はいずれも CanonicalizeHost 済みであることを前提とします
+func DomainMatch(host, domain string) bool {
+	if host == "" || domain == "" {
+		return false
+	}
+	if host == domain {
+		return true
+	}
+	// IPアドレスは完全一致のみ許可する
+	if net.ParseIP(host) != nil {
+		return false
+	}
+	return strings.HasSuffix(host, "."+domain)
+}
+
+// DomainCandidates はホストに domain-match し得る Domain の候補を返します
+// 先頭ドットの有無の両方を含むため、保存時の表記に関わらず検索に利用できます
+func DomainCandidates(host string) []string {
+	host = CanonicalizeHost(host)
+	if host == "" {
+		return nil
+	}
+	if net.ParseIP(host) != nil {
+		return []string{host}
+	}
+
+	var candidates []string
+	for domain := host; domain != ""; {
+		candidates = append(candidates, domain, "."+domain)
+		_, parent, found := strings.Cut(domain, ".")
+		if !found {
+			break
+		}
+		domain = parent
 	}
+	return candidates
 }
@@ -0,0 +1,133 @@
+package entity
+
+import (
+	"strings"
+	"time"
+)
+
+// cookieDateMonths は RFC 6265 §5.1.1 の month の先頭3文字です
+var cookieDateMonths = [...]string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}
+
+// ParseCookieDate は RFC 6265 §5.1.1 のアルゴリズムで Expires 属性の日付を解析します
+// RFC 1123 の形式に加え、"Wed, 01-Jan-31 00:00:00 GMT" や asctime 形式（"Wed Jan  1 00:00:00 2031"）、2桁の年なども受け付けます
+// 解析できない場合は ok = false を返します
+func ParseCookieDate(s string) (time.Time, bool) {
+	var (
+		hour, minute, second                       int
+		day, month, year                           int
+		foundTime, foundDay, foundMonth, foundYear bool
+	)
+	for _, token := range strings.FieldsFunc(s, isCookieDateDelimiter) {
+		if !foundTime {
+			if h, m, sec, ok := parseCookieTime(token); ok {
+				hour, minute, second = h, m, sec
+				foundTime = true
+				continue
+			}
+		}
+		if !foundDay {
+			if d, ok := parseCookieDigits(token, 1, 2); ok {
+				day = d
+				foundDay = true
+				continue
+			}
+		}
+		if !foundMonth {
+			if m, ok := parseCookieMonth(token); ok {
+				month = m
+				foundMonth = true
+				continue
+			}
+		}
+		if !foundYear {
+			if y, ok := parseCookieDigits(token, 2, 4); ok {
+				year = y
+				foundYear = true
+				continue
+			}
+		}
+	}
+
+	// 2桁の年は 70〜99 を 1900 年代、0〜69 を 2000 年代とする
+	switch {
+	case year >= 70 && year <= 99:
+		year += 1900
+	case year >= 0 && year <= 69:
+		year += 2000
+	}
+	if !foundTime || !foundDay || !foundMonth || !foundYear ||
+		day < 1 || day > 31 || year < 1601 || hour > 23 || minute > 59 || second > 59 {
+		return time.Time{}, false
+	}
+
+	t := time.Date(year, time.Month(month), day, hour, minute, second, 0, time.UTC)
+	// 2月30日など存在しない日付は拒否する
+	if t.Day() != day {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// isCookieDateDelimiter は RFC 6265 §5.1.1 の delimiter かを判定します
+func isCookieDateDelimiter(r rune) bool {
+	return r == 0x09 ||
+		(r >= 0x20 && r <= 0x2F) ||
+		(r >= 0x3B && r <= 0x40) ||
+		(r >= 0x5B && r <= 0x60) ||
+		(r >= 0x7B && r <= 0x7E)
+}
+
+// parseCookieDigits は token の先頭の min〜max 桁の数字を解析します
+// 数字の後に続くのは数字以外の文字のみ許可します（"1*2DIGIT ( non-digit *OCTET )" など）
+func parseCookieDigits(token string, min, max int) (int, bool) {
+	n, i := 0, 0
+	for ; i < len(token) && isDigit(token[i]); i++ {
+		if i == max {
+			return 0, false
+		}
+		n = n*10 + int(token[i]-'0')
+	}
+	if i < min {
+		return 0, false
+	}
+	return n, true
+}
+
+// parseCookieTime は token を "hh:mm:ss" 形式（各フィールドは1〜2桁）の時刻として解析します
+func parseCookieTime(token string) (hour, minute, second int, ok bool) {
+	fields := strings.SplitN(token, ":", 3)
+	if len(fields) != 3 {
+		return 0, 0, 0, false
+	}
+	var values [3]int
+	for i, field := range fields {
+		// 最後のフィールド以外は数字のみ
+		if i < 2 && (field == "" || len(field) > 2 || !isDigit(field[len(field)-1])) {
+			return 0, 0, 0, false
+		}
+		v, ok := parseCookieDigits(field, 1, 2)
+		if !ok {
+			return 0, 0, 0, false
+		}
+		values[i] = v
+	}
+	return values[0], values[1], values[2], true
+}
+
+// parseCookieMonth は token の先頭3文字を月の名前として解析します（大文字小文字は区別しない）
+func parseCookieMonth(token string) (int, bool) {
+	if len(token) < 3 {
+		return 0, false
+	}
+	prefix := strings.ToLower(token[:3])
+	for i, name := range cookieDateMonths {
+		if prefix == name {
+			return i + 1, true
+		}
+	}
+	return 0, false
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
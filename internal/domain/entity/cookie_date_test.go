@@ -0,0 +1,100 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCookieDate(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		want   time.Time
+		wantOk bool
+	}{
+		{
+			name:   "RFC 1123形式",
+			input:  "Wed, 21 Oct 2015 07:28:00 GMT",
+			want:   time.Date(2015, 10, 21, 7, 28, 0, 0, time.UTC),
+			wantOk: true,
+		},
+		{
+			name:   "ハイフン区切りで2桁の年（0〜69は2000年代）",
+			input:  "Wed, 01-Jan-31 00:00:00 GMT",
+			want:   time.Date(2031, 1, 1, 0, 0, 0, 0, time.UTC),
+			wantOk: true,
+		},
+		{
+			name:   "ハイフン区切りで2桁の年（70〜99は1900年代）",
+			input:  "Thursday, 01-Jan-70 00:00:01 GMT",
+			want:   time.Date(1970, 1, 1, 0, 0, 1, 0, time.UTC),
+			wantOk: true,
+		},
+		{
+			name:   "asctime形式",
+			input:  "Wed Jan  1 00:00:00 2031",
+			want:   time.Date(2031, 1, 1, 0, 0, 0, 0, time.UTC),
+			wantOk: true,
+		},
+		{
+			name:   "ハイフン区切りで4桁の年",
+			input:  "Sat, 15-Mar-2031 12:34:56 UTC",
+			want:   time.Date(2031, 3, 15, 12, 34, 56, 0, time.UTC),
+			wantOk: true,
+		},
+		{
+			name:   "1桁の時刻と小文字の月名",
+			input:  "1 jan 2031 1:2:3",
+			want:   time.Date(2031, 1, 1, 1, 2, 3, 0, time.UTC),
+			wantOk: true,
+		},
+		{
+			name:   "月名は先頭3文字で判定する",
+			input:  "Monday, 06 September 2027 10:00:00 GMT",
+			want:   time.Date(2027, 9, 6, 10, 0, 0, 0, time.UTC),
+			wantOk: true,
+		},
+		{
+			name:   "タイムゾーンの表記は無視する",
+			input:  "Wed, 01 Jan 2031 00:00:00 +0900",
+			want:   time.Date(2031, 1, 1, 0, 0, 0, 0, time.UTC),
+			wantOk: true,
+		},
+		{
+			name:  "存在しない日付",
+			input: "Wed, 31 Feb 2031 00:00:00 GMT",
+		},
+		{
+			name:  "1601年より前",
+			input: "Wed, 01 Jan 1600 00:00:00 GMT",
+		},
+		{
+			name:  "時刻の範囲外",
+			input: "Wed, 01 Jan 2031 24:00:00 GMT",
+		},
+		{
+			name:  "時刻がない",
+			input: "Wed, 01 Jan 2031",
+		},
+		{
+			name:  "月がない",
+			input: "2031-01-01 00:00:00",
+		},
+		{
+			name:  "日付ではない",
+			input: "invalid",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ParseCookieDate(tt.input)
+			if ok != tt.wantOk {
+				t.Fatalf("ParseCookieDate(%q) ok = %v, want %v", tt.input, ok, tt.wantOk)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseCookieDate(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
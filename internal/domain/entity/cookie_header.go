@@ -0,0 +1,31 @@
+package entity
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// SortForCookieHeader は RFC 6265 §5.4 step 2 に従い、パスが長い Cookie を先に、
+// 同じ長さの場合は作成時刻が早い Cookie を先に並べ替えます
+func SortForCookieHeader(cookies []*Cookie) {
+	slices.SortStableFunc(cookies, func(a, b *Cookie) int {
+		if c := cmp.Compare(len(b.CanonicalPath()), len(a.CanonicalPath())); c != 0 {
+			return c
+		}
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+}
+
+// CookieHeader は RFC 6265 §5.4 に従い、Cookie リクエストヘッダーの値（"name=value; name2=value2"）を組み立てます
+// 引数のスライスは並べ替えません
+func CookieHeader(cookies []*Cookie) string {
+	sorted := slices.Clone(cookies)
+	SortForCookieHeader(sorted)
+
+	pairs := make([]string, len(sorted))
+	for i, cookie := range sorted {
+		pairs[i] = cookie.Name + "=" + cookie.Value
+	}
+	return strings.Join(pairs, "; ")
+}
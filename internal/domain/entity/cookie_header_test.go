@@ -0,0 +1,57 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCookieHeader(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		cookies []*Cookie
+		want    string
+	}{
+		{
+			name:    "Cookieがなければ空文字列",
+			cookies: nil,
+			want:    "",
+		},
+		{
+			name: "属性を含めずname=valueのみを結合する",
+			cookies: []*Cookie{
+				{Name: "sid", Value: "abc", Path: "/", Secure: true, HttpOnly: true, Expires: base},
+				{Name: "lang", Value: "ja", Path: "/", CreatedAt: base.Add(time.Second)},
+			},
+			want: "sid=abc; lang=ja",
+		},
+		{
+			name: "パスが長いCookieを先に並べる",
+			cookies: []*Cookie{
+				{Name: "root", Value: "1", Path: "/"},
+				{Name: "deep", Value: "3", Path: "/docs/guide"},
+				{Name: "docs", Value: "2", Path: "/docs"},
+				{Name: "nopath", Value: "0"},
+			},
+			want: "deep=3; docs=2; root=1; nopath=0",
+		},
+		{
+			name: "同じ長さのパスは作成時刻が早いCookieを先に並べる",
+			cookies: []*Cookie{
+				{Name: "late", Value: "2", Path: "/", CreatedAt: base.Add(time.Minute)},
+				{Name: "early", Value: "1", Path: "/", CreatedAt: base},
+				{Name: "docs", Value: "0", Path: "/docs", CreatedAt: base.Add(time.Hour)},
+			},
+			want: "docs=0; early=1; late=2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CookieHeader(tt.cookies); got != tt.want {
+				t.Errorf("CookieHeader() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
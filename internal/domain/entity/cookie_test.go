@@ -2,6 +2,7 @@ package entity
 
 import (
 	"net/http"
+	"net/url"
 	"testing"
 	"time"
 )
@@ -207,3 +208,428 @@ func TestCookie_ToHTTPCookie(t *testing.T) {
 		})
 	}
 }
+
+func TestCookie_MatchesHost(t *testing.T) {
+	tests := []struct {
+		name   string
+		cookie *Cookie
+		host   string
+		want   bool
+	}{
+		{
+			name:   "完全一致",
+			cookie: &Cookie{Domain: "example.com"},
+			host:   "example.com",
+			want:   true,
+		},
+		{
+			name:   "先頭ドット付きのDomainはサブドメインにマッチする",
+			cookie: &Cookie{Domain: ".example.com"},
+			host:   "api.example.com",
+			want:   true,
+		},
+		{
+			name:   "先頭ドットなしのDomainもサブドメインにマッチする",
+			cookie: &Cookie{Domain: "example.com"},
+			host:   "a.b.example.com",
+			want:   true,
+		},
+		{
+			name:   "大文字小文字と末尾ドットを無視する",
+			cookie: &Cookie{Domain: ".Example.COM"},
+			host:   "API.example.com.",
+			want:   true,
+		},
+		{
+			name:   "サフィックスが一致するだけの別ドメインにはマッチしない",
+			cookie: &Cookie{Domain: "example.com"},
+			host:   "badexample.com",
+			want:   false,
+		},
+		{
+			name:   "子ドメインのCookieは親ドメインにマッチしない",
+			cookie: &Cookie{Domain: "api.example.com"},
+			host:   "example.com",
+			want:   false,
+		},
+		{
+			name:   "host-only Cookieはサブドメインにマッチしない",
+			cookie: &Cookie{Domain: "example.com", HostOnly: true},
+			host:   "api.example.com",
+			want:   false,
+		},
+		{
+			name:   "host-only Cookieは同一ホストにマッチする",
+			cookie: &Cookie{Domain: "example.com", HostOnly: true},
+			host:   "example.com",
+			want:   true,
+		},
+		{
+			name:   "IPアドレスは完全一致のみ",
+			cookie: &Cookie{Domain: "0.0.1"},
+			host:   "192.168.0.1",
+			want:   false,
+		},
+		{
+			name:   "空のホスト",
+			cookie: &Cookie{Domain: "example.com"},
+			host:   "",
+			want:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cookie.MatchesHost(tt.host); got != tt.want {
+				t.Errorf("MatchesHost(%q) = %v, want %v", tt.host, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDomainCandidates(t *testing.T) {
+	tests := []struct {
+		name string
+		host string
+		want []string
+	}{
+		{
+			name: "サブドメイン",
+			host: "api.example.com",
+			want: []string{"api.example.com", ".api.example.com", "example.com", ".example.com", "com", ".com"},
+		},
+		{
+			name: "正規化される",
+			host: "Example.COM.",
+			want: []string{"example.com", ".example.com", "com", ".com"},
+		},
+		{
+			name: "IPアドレス",
+			host: "127.0.0.1",
+			want: []string{"127.0.0.1"},
+		},
+		{
+			name: "空のホスト",
+			host: "",
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DomainCandidates(tt.host)
+			if len(got) != len(tt.want) {
+				t.Fatalf("DomainCandidates(%q) = %v, want %v", tt.host, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("DomainCandidates(%q)[%d] = %v, want %v", tt.host, i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestPathMatch(t *testing.T) {
+	tests := []struct {
+		name        string
+		requestPath string
+		cookiePath  string
+		want        bool
+	}{
+		{name: "完全一致", requestPath: "/api", cookiePath: "/api", want: true},
+		{name: "ルートパスはすべてにマッチ", requestPath: "/api/users", cookiePath: "/", want: true},
+		{name: "ディレクトリ配下にマッチ", requestPath: "/api/users", cookiePath: "/api", want: true},
+		{name: "末尾スラッシュ付きのCookieパス", requestPath: "/api/users", cookiePath: "/api/", want: true},
+		{name: "プレフィックスだけ一致する別パス", requestPath: "/apiv2", cookiePath: "/api", want: false},
+		{name: "親パスにはマッチしない", requestPath: "/", cookiePath: "/api", want: false},
+		{name: "空のリクエストパスはルート扱い", requestPath: "", cookiePath: "/", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PathMatch(tt.requestPath, tt.cookiePath); got != tt.want {
+				t.Errorf("PathMatch(%q, %q) = %v, want %v", tt.requestPath, tt.cookiePath, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCookie_MatchesURL(t *testing.T) {
+	tests := []struct {
+		name   string
+		cookie *Cookie
+		url    string
+		want   bool
+	}{
+		{
+			name:   "ドメインとパスが一致",
+			cookie: &Cookie{Domain: ".example.com", Path: "/api"},
+			url:    "http://api.example.com/api/users",
+			want:   true,
+		},
+		{
+			name:   "パスが一致しない",
+			cookie: &Cookie{Domain: "example.com", Path: "/api"},
+			url:    "https://example.com/",
+			want:   false,
+		},
+		{
+			name:   "Path未設定はルート扱い",
+			cookie: &Cookie{Domain: "example.com"},
+			url:    "https://example.com/any/where",
+			want:   true,
+		},
+		{
+			name:   "SecureなCookieはhttpに送信しない",
+			cookie: &Cookie{Domain: "example.com", Path: "/", Secure: true},
+			url:    "http://example.com/",
+			want:   false,
+		},
+		{
+			name:   "SecureなCookieはhttpsに送信する",
+			cookie: &Cookie{Domain: "example.com", Path: "/", Secure: true},
+			url:    "https://example.com/",
+			want:   true,
+		},
+		{
+			name:   "ドメインが一致しない",
+			cookie: &Cookie{Domain: "example.com", Path: "/"},
+			url:    "https://example.org/",
+			want:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := url.Parse(tt.url)
+			if err != nil {
+				t.Fatalf("Failed to parse url: %v", err)
+			}
+			if got := tt.cookie.MatchesURL(u); got != tt.want {
+				t.Errorf("MatchesURL(%q) = %v, want %v", tt.url, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewCookieAt(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	expires := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		httpCookie  *http.Cookie
+		wantExpires time.Time
+		wantExpired bool
+	}{
+		{
+			name:        "MaxAgeを絶対時刻に変換する",
+			httpCookie:  &http.Cookie{Name: "test", Value: "value", MaxAge: 3600},
+			wantExpires: now.Add(time.Hour),
+			wantExpired: false,
+		},
+		{
+			name:        "MaxAgeはExpiresより優先される",
+			httpCookie:  &http.Cookie{Name: "test", Value: "value", MaxAge: 60, Expires: expires},
+			wantExpires: now.Add(time.Minute),
+			wantExpired: false,
+		},
+		{
+			name:        "MaxAgeが負の場合は即時失効",
+			httpCookie:  &http.Cookie{Name: "test", Value: "value", MaxAge: -1},
+			wantExpires: time.Unix(0, 0).UTC(),
+			wantExpired: true,
+		},
+		{
+			name:        "MaxAge未指定の場合はExpiresを使う",
+			httpCookie:  &http.Cookie{Name: "test", Value: "value", Expires: expires},
+			wantExpires: expires,
+			wantExpired: false,
+		},
+		{
+			name:        "過去のExpiresは失効",
+			httpCookie:  &http.Cookie{Name: "test", Value: "value", Expires: now.Add(-time.Second)},
+			wantExpires: now.Add(-time.Second),
+			wantExpired: true,
+		},
+		{
+			name:        "セッションCookieは失効しない",
+			httpCookie:  &http.Cookie{Name: "test", Value: "value"},
+			wantExpires: time.Time{},
+			wantExpired: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewCookieAt(tt.httpCookie, now)
+			if !got.Expires.Equal(tt.wantExpires) {
+				t.Errorf("Expires = %v, want %v", got.Expires, tt.wantExpires)
+			}
+			if got.IsExpired(now) != tt.wantExpired {
+				t.Errorf("IsExpired() = %v, want %v", got.IsExpired(now), tt.wantExpired)
+			}
+		})
+	}
+}
+
+func TestMergeCookies(t *testing.T) {
+	tests := []struct {
+		name       string
+		existing   []*Cookie
+		incoming   []*Cookie
+		wantValues []string
+	}{
+		{
+			name: "同じ名前・ドメイン・パスのCookieは上書きされる",
+			existing: []*Cookie{
+				{Name: "sid", Value: "old", Domain: "example.com", Path: "/"},
+			},
+			incoming: []*Cookie{
+				{Name: "sid", Value: "new", Domain: "example.com", Path: "/"},
+			},
+			wantValues: []string{"new"},
+		},
+		{
+			name: "パスが異なる同名Cookieは共存する",
+			existing: []*Cookie{
+				{Name: "sid", Value: "root", Domain: "example.com", Path: "/"},
+			},
+			incoming: []*Cookie{
+				{Name: "sid", Value: "admin", Domain: "example.com", Path: "/admin"},
+			},
+			wantValues: []string{"root", "admin"},
+		},
+		{
+			name: "ドメインが異なる同名Cookieは共存する",
+			existing: []*Cookie{
+				{Name: "sid", Value: "parent", Domain: "example.com", Path: "/"},
+			},
+			incoming: []*Cookie{
+				{Name: "sid", Value: "child", Domain: "api.example.com", Path: "/"},
+			},
+			wantValues: []string{"parent", "child"},
+		},
+		{
+			name: "パーティションキーが異なる同名Cookieは共存する",
+			existing: []*Cookie{
+				{Name: "chips", Value: "a", Domain: "example.com", Path: "/", Partitioned: true, PartitionKey: "https://a.example"},
+			},
+			incoming: []*Cookie{
+				{Name: "chips", Value: "b", Domain: "example.com", Path: "/", Partitioned: true, PartitionKey: "https://b.example"},
+			},
+			wantValues: []string{"a", "b"},
+		},
+		{
+			name: "host-onlyと非host-onlyの同名Cookieは共存する",
+			existing: []*Cookie{
+				{Name: "sid", Value: "domain", Domain: "example.com", Path: "/"},
+			},
+			incoming: []*Cookie{
+				{Name: "sid", Value: "host", Domain: "example.com", Path: "/", HostOnly: true},
+			},
+			wantValues: []string{"domain", "host"},
+		},
+		{
+			name: "先頭ドット・大文字小文字・未設定パスは正規化して比較する",
+			existing: []*Cookie{
+				{Name: "sid", Value: "old", Domain: ".Example.com", Path: ""},
+			},
+			incoming: []*Cookie{
+				{Name: "sid", Value: "new", Domain: "example.com", Path: "/"},
+			},
+			wantValues: []string{"new"},
+		},
+		{
+			name: "名前は大文字小文字を区別する",
+			existing: []*Cookie{
+				{Name: "SID", Value: "upper", Domain: "example.com", Path: "/"},
+			},
+			incoming: []*Cookie{
+				{Name: "sid", Value: "lower", Domain: "example.com", Path: "/"},
+			},
+			wantValues: []string{"upper", "lower"},
+		},
+		{
+			name:     "同一リクエスト内の重複は後勝ち",
+			existing: []*Cookie{},
+			incoming: []*Cookie{
+				{Name: "sid", Value: "first", Domain: "example.com", Path: "/"},
+				{Name: "sid", Value: "second", Domain: "example.com", Path: "/"},
+			},
+			wantValues: []string{"second"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MergeCookies(tt.existing, tt.incoming)
+			if len(got) != len(tt.wantValues) {
+				t.Fatalf("MergeCookies() len = %v, want %v", len(got), len(tt.wantValues))
+			}
+			for i, cookie := range got {
+				if cookie.Value != tt.wantValues[i] {
+					t.Errorf("MergeCookies()[%d].Value = %v, want %v", i, cookie.Value, tt.wantValues[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCookie_MatchesPartition(t *testing.T) {
+	tests := []struct {
+		name         string
+		cookie       *Cookie
+		topLevelSite string
+		want         bool
+	}{
+		{
+			name:         "パーティションキーのないCookieはすべてのトップレベルサイトにマッチする",
+			cookie:       &Cookie{Name: "sid"},
+			topLevelSite: "https://a.example",
+			want:         true,
+		},
+		{
+			name:   "パーティションキーのないCookieはトップレベルサイトの指定がなくてもマッチする",
+			cookie: &Cookie{Name: "sid"},
+			want:   true,
+		},
+		{
+			name:   "パーティションキーのないPartitioned Cookieはトップレベルサイトの指定がなくてもマッチする",
+			cookie: &Cookie{Name: "sid", Partitioned: true},
+			want:   true,
+		},
+		{
+			name:         "同じトップレベルサイトのパーティション",
+			cookie:       &Cookie{Name: "chips", Partitioned: true, PartitionKey: "https://a.example"},
+			topLevelSite: "https://a.example",
+			want:         true,
+		},
+		{
+			name:         "大文字小文字と末尾のスラッシュを無視する",
+			cookie:       &Cookie{Name: "chips", Partitioned: true, PartitionKey: "https://A.example"},
+			topLevelSite: "https://a.example/",
+			want:         true,
+		},
+		{
+			name:         "別のトップレベルサイトのパーティション",
+			cookie:       &Cookie{Name: "chips", Partitioned: true, PartitionKey: "https://a.example"},
+			topLevelSite: "https://b.example",
+			want:         false,
+		},
+		{
+			name:   "トップレベルサイトの指定がない場合はパーティションの Cookie にマッチしない",
+			cookie: &Cookie{Name: "chips", Partitioned: true, PartitionKey: "https://a.example"},
+			want:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cookie.MatchesPartition(tt.topLevelSite); got != tt.want {
+				t.Errorf("MatchesPartition(%q) = %v, want %v", tt.topLevelSite, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// DefaultJar は jar を指定しないリクエストで使う jar の名前です
+const DefaultJar = "default"
+
+var (
+	// ErrInvalidJarName は jar の名前の形式が不正であることを示すエラーです
+	ErrInvalidJarName = errors.New("invalid jar name")
+	// ErrJarNotFound は jar が存在しないことを示すエラーです
+	ErrJarNotFound = errors.New("jar not found")
+	// ErrJarExists は同じ名前の jar がすでに存在することを示すエラーです
+	ErrJarExists = errors.New("jar already exists")
+)
+
+// jarNamePattern は jar の名前に使える文字列です（URL のパスにそのまま含められる文字に限る）
+var jarNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,63}$`)
+
+// Jar は Cookie を分離する単位です（アカウントやボットごとに作成し、同じサイトの Cookie を別々に保存する）
+type Jar struct {
+	Name      string
+	CreatedAt time.Time
+	// CookieCount は jar に保存されている Cookie の数（一覧の取得時のみ設定）
+	CookieCount int
+}
+
+// ValidateJarName は jar の名前が英数字で始まる 64 文字以内の英数字・"."・"_"・"-" であるかを検証します
+func ValidateJarName(name string) error {
+	if !jarNamePattern.MatchString(name) {
+		return fmt.Errorf("%w: %q must be 1-64 characters of letters, digits, '.', '_' or '-' starting with a letter or digit", ErrInvalidJarName, name)
+	}
+	return nil
+}
+
+// ResolveJarName は jar の名前を検証し、空の場合は DefaultJar を返します
+func ResolveJarName(name string) (string, error) {
+	if name == "" {
+		return DefaultJar, nil
+	}
+	if err := ValidateJarName(name); err != nil {
+		return "", err
+	}
+	return name, nil
+}
@@ -0,0 +1,65 @@
+package entity
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestResolveJarName(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "空の場合は default",
+			input: "",
+			want:  DefaultJar,
+		},
+		{
+			name:  "英数字と記号",
+			input: "account-1.bot_A",
+			want:  "account-1.bot_A",
+		},
+		{
+			name:  "64文字",
+			input: strings.Repeat("a", 64),
+			want:  strings.Repeat("a", 64),
+		},
+		{
+			name:    "65文字",
+			input:   strings.Repeat("a", 65),
+			wantErr: true,
+		},
+		{
+			name:    "記号で始まる",
+			input:   ".hidden",
+			wantErr: true,
+		},
+		{
+			name:    "スラッシュを含む",
+			input:   "team/bot",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveJarName(tt.input)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidJarName) {
+					t.Errorf("ResolveJarName() error = %v, want %v", err, ErrInvalidJarName)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ResolveJarName() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ResolveJarName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package entity
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// netscapeHttpOnlyPrefix は curl が HttpOnly 属性の Cookie の行に付けるプレフィックスです
+const netscapeHttpOnlyPrefix = "#HttpOnly_"
+
+// NetscapeParseError は Netscape 形式の cookies.txt の解析エラーです
+type NetscapeParseError struct {
+	Line int
+	Err  error
+}
+
+func (e *NetscapeParseError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *NetscapeParseError) Unwrap() error {
+	return e.Err
+}
+
+// ParseNetscapeCookies は curl / wget 互換の Netscape 形式（cookies.txt）を解析します
+// include-subdomains 列が FALSE の Cookie は host-only として扱います
+func ParseNetscapeCookies(r io.Reader) ([]*Cookie, error) {
+	var cookies []*Cookie
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimRight(scanner.Text(), "\r")
+
+		httpOnly := false
+		if strings.HasPrefix(text, netscapeHttpOnlyPrefix) {
+			httpOnly = true
+			text = strings.TrimPrefix(text, netscapeHttpOnlyPrefix)
+		} else if strings.TrimSpace(text) == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		cookie, err := parseNetscapeLine(text)
+		if err == nil {
+			err = cookie.ValidatePrefix()
+		}
+		if err != nil {
+			return nil, &NetscapeParseError{Line: line, Err: err}
+		}
+		cookie.HttpOnly = httpOnly
+		cookies = append(cookies, cookie)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return cookies, nil
+}
+
+// parseNetscapeLine は domain, include-subdomains, path, secure, expires, name, value の7列を解析します
+// value が空の場合は6列の行も受け付けます
+func parseNetscapeLine(text string) (*Cookie, error) {
+	fields := strings.Split(text, "\t")
+	if len(fields) == 6 {
+		fields = append(fields, "")
+	}
+	if len(fields) != 7 {
+		return nil, fmt.Errorf("expected 7 tab-separated fields, got %d", len(fields))
+	}
+
+	domain := CanonicalizeHost(strings.TrimPrefix(fields[0], "."))
+	if domain == "" {
+		return nil, fmt.Errorf("domain is empty")
+	}
+	includeSubdomains, err := parseNetscapeBool(fields[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid include-subdomains flag: %w", err)
+	}
+	secure, err := parseNetscapeBool(fields[3])
+	if err != nil {
+		return nil, fmt.Errorf("invalid secure flag: %w", err)
+	}
+	expires, err := strconv.ParseInt(fields[4], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expires: %w", err)
+	}
+	if fields[5] == "" {
+		return nil, fmt.Errorf("name is empty")
+	}
+
+	cookie := &Cookie{
+		Name:     fields[5],
+		Value:    fields[6],
+		Domain:   domain,
+		Path:     fields[2],
+		Secure:   secure,
+		HostOnly: !includeSubdomains,
+	}
+	// 0 はセッション Cookie
+	if expires > 0 {
+		cookie.Expires = time.Unix(expires, 0).UTC()
+	}
+	return cookie, nil
+}
+
+func parseNetscapeBool(s string) (bool, error) {
+	switch strings.ToUpper(s) {
+	case "TRUE":
+		return true, nil
+	case "FALSE":
+		return false, nil
+	default:
+		return false, fmt.Errorf("%q is not TRUE or FALSE", s)
+	}
+}
+
+// WriteNetscapeCookies は Cookie を curl / wget 互換の Netscape 形式（cookies.txt）で書き出します
+func WriteNetscapeCookies(w io.Writer, cookies []*Cookie) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString("# Netscape HTTP Cookie File\n")
+	bw.WriteString("# https://curl.se/docs/http-cookies.html\n")
+	bw.WriteString("# This file was generated by cookiejar-server. Edit at your own risk.\n\n")
+
+	for _, cookie := range cookies {
+		domain := cookie.CanonicalDomain()
+		if !cookie.HostOnly {
+			domain = "." + domain
+		}
+		if cookie.HttpOnly {
+			domain = netscapeHttpOnlyPrefix + domain
+		}
+		var expires int64
+		if !cookie.Expires.IsZero() {
+			expires = cookie.Expires.Unix()
+		}
+		fmt.Fprintf(bw, "%s\t%s\t%s\t%s\t%d\t%s\t%s\n",
+			domain,
+			formatNetscapeBool(!cookie.HostOnly),
+			cookie.CanonicalPath(),
+			formatNetscapeBool(cookie.Secure),
+			expires,
+			cookie.Name,
+			cookie.Value,
+		)
+	}
+	return bw.Flush()
+}
+
+func formatNetscapeBool(b bool) string {
+	if b {
+		return "TRUE"
+	}
+	return "FALSE"
+}
@@ -0,0 +1,129 @@
+package entity
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseNetscapeCookies(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		want     []*Cookie
+		wantLine int
+	}{
+		{
+			name: "コメントと空行を読み飛ばす",
+			input: "# Netscape HTTP Cookie File\n" +
+				"\n" +
+				".example.com\tTRUE\t/\tTRUE\t1924992000\tsid\tabc\n",
+			want: []*Cookie{
+				{Name: "sid", Value: "abc", Domain: "example.com", Path: "/", Secure: true, Expires: time.Unix(1924992000, 0).UTC()},
+			},
+		},
+		{
+			name:  "include-subdomainsがFALSEならhost-onlyになる",
+			input: "www.example.com\tFALSE\t/docs\tFALSE\t0\tlang\tja\r\n",
+			want: []*Cookie{
+				{Name: "lang", Value: "ja", Domain: "www.example.com", Path: "/docs", HostOnly: true},
+			},
+		},
+		{
+			name:  "#HttpOnly_プレフィックスはHttpOnly属性になる",
+			input: "#HttpOnly_.example.com\tTRUE\t/\tFALSE\t0\tsession\txyz\n",
+			want: []*Cookie{
+				{Name: "session", Value: "xyz", Domain: "example.com", Path: "/", HttpOnly: true},
+			},
+		},
+		{
+			name:  "値が空の6列の行を受け付ける",
+			input: "example.com\tFALSE\t/\tFALSE\t0\tempty\n",
+			want: []*Cookie{
+				{Name: "empty", Value: "", Domain: "example.com", Path: "/", HostOnly: true},
+			},
+		},
+		{
+			name:     "列が足りない行はエラー",
+			input:    "# comment\nexample.com\tFALSE\t/\n",
+			wantLine: 2,
+		},
+		{
+			name:     "フラグが不正な行はエラー",
+			input:    "example.com\tYES\t/\tFALSE\t0\tname\tvalue\n",
+			wantLine: 1,
+		},
+		{
+			name:     "有効期限が不正な行はエラー",
+			input:    "example.com\tFALSE\t/\tFALSE\ttomorrow\tname\tvalue\n",
+			wantLine: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseNetscapeCookies(strings.NewReader(tt.input))
+			if tt.wantLine > 0 {
+				var parseErr *NetscapeParseError
+				if !errors.As(err, &parseErr) || parseErr.Line != tt.wantLine {
+					t.Errorf("ParseNetscapeCookies() error = %v, want error at line %d", err, tt.wantLine)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseNetscapeCookies() error = %v", err)
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("ParseNetscapeCookies() len = %v, want %v", len(got), len(tt.want))
+			}
+			for i := range got {
+				if *got[i] != *tt.want[i] {
+					t.Errorf("ParseNetscapeCookies()[%d] = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestWriteNetscapeCookies(t *testing.T) {
+	cookies := []*Cookie{
+		{Name: "sid", Value: "abc", Domain: ".Example.com", Path: "/", Secure: true, HttpOnly: true, Expires: time.Unix(1924992000, 0)},
+		{Name: "lang", Value: "ja", Domain: "www.example.com", HostOnly: true},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteNetscapeCookies(&buf, cookies); err != nil {
+		t.Fatalf("WriteNetscapeCookies() error = %v", err)
+	}
+
+	out := buf.String()
+	if !strings.HasPrefix(out, "# Netscape HTTP Cookie File\n") {
+		t.Errorf("WriteNetscapeCookies() header missing: %q", out)
+	}
+	wantLines := []string{
+		"#HttpOnly_.example.com\tTRUE\t/\tTRUE\t1924992000\tsid\tabc",
+		"www.example.com\tFALSE\t/\tFALSE\t0\tlang\tja",
+	}
+	for _, line := range wantLines {
+		if !strings.Contains(out, line+"\n") {
+			t.Errorf("WriteNetscapeCookies() missing line %q in %q", line, out)
+		}
+	}
+
+	// 書き出した内容を読み込むと同じCookieになる
+	parsed, err := ParseNetscapeCookies(&buf)
+	if err != nil {
+		t.Fatalf("ParseNetscapeCookies() error = %v", err)
+	}
+	if len(parsed) != len(cookies) {
+		t.Fatalf("round trip len = %v, want %v", len(parsed), len(cookies))
+	}
+	for i := range parsed {
+		if parsed[i].Key() != cookies[i].Key() || parsed[i].HttpOnly != cookies[i].HttpOnly || !parsed[i].Expires.Equal(cookies[i].Expires) {
+			t.Errorf("round trip [%d] = %+v, want %+v", i, parsed[i], cookies[i])
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Cookie 名のプレフィックス（RFC 6265bis §4.1.3）
+const (
+	SecurePrefix = "__Secure-"
+	HostPrefix   = "__Host-"
+)
+
+// ErrCookiePrefix は Cookie 名のプレフィックスの要件を満たさない Cookie であることを示すエラーです
+var ErrCookiePrefix = errors.New("cookie prefix requirements not met")
+
+// ValidatePrefix は Cookie 名のプレフィックスの要件を検証します（プレフィックスの大文字小文字は区別しない）
+//   - __Secure-: Secure 属性が必要
+//   - __Host-: Secure 属性が必要で、Domain 属性なし（host-only）かつ Path が "/"
+func (c *Cookie) ValidatePrefix() error {
+	switch {
+	case hasPrefixFold(c.Name, SecurePrefix):
+		if !c.Secure {
+			return fmt.Errorf("%w: %s cookie requires Secure", ErrCookiePrefix, SecurePrefix)
+		}
+	case hasPrefixFold(c.Name, HostPrefix):
+		if !c.Secure {
+			return fmt.Errorf("%w: %s cookie requires Secure", ErrCookiePrefix, HostPrefix)
+		}
+		if !c.HostOnly {
+			return fmt.Errorf("%w: %s cookie must not have a Domain attribute", ErrCookiePrefix, HostPrefix)
+		}
+		if c.CanonicalPath() != "/" {
+			return fmt.Errorf(`%w: %s cookie requires Path "/"`, ErrCookiePrefix, HostPrefix)
+		}
+	}
+	return nil
+}
+
+// RemoveInvalidPrefix はプレフィックスの要件を満たす Cookie のみを返します
+func RemoveInvalidPrefix(cookies []*Cookie) []*Cookie {
+	result := make([]*Cookie, 0, len(cookies))
+	for _, cookie := range cookies {
+		if cookie.ValidatePrefix() == nil {
+			result = append(result, cookie)
+		}
+	}
+	return result
+}
+
+func hasPrefixFold(s, prefix string) bool {
+	return len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix)
+}
@@ -0,0 +1,68 @@
+package entity
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCookie_ValidatePrefix(t *testing.T) {
+	tests := []struct {
+		name    string
+		cookie  *Cookie
+		wantErr bool
+	}{
+		{
+			name:   "プレフィックスなし",
+			cookie: &Cookie{Name: "sid", Domain: "example.com", Path: "/app"},
+		},
+		{
+			name:   "__Secure-はSecureがあればよい",
+			cookie: &Cookie{Name: "__Secure-sid", Domain: "example.com", Path: "/app", Secure: true},
+		},
+		{
+			name:    "__Secure-でSecureなし",
+			cookie:  &Cookie{Name: "__Secure-sid", Domain: "example.com", Path: "/"},
+			wantErr: true,
+		},
+		{
+			name:   "__Host-はSecureかつhost-onlyかつPathが/",
+			cookie: &Cookie{Name: "__Host-sid", Domain: "example.com", Path: "/", Secure: true, HostOnly: true},
+		},
+		{
+			name:   "__Host-でPath未設定は/として扱う",
+			cookie: &Cookie{Name: "__Host-sid", Domain: "example.com", Secure: true, HostOnly: true},
+		},
+		{
+			name:    "__Host-でDomain属性あり",
+			cookie:  &Cookie{Name: "__Host-sid", Domain: "example.com", Path: "/", Secure: true},
+			wantErr: true,
+		},
+		{
+			name:    "__Host-でPathが/以外",
+			cookie:  &Cookie{Name: "__Host-sid", Domain: "example.com", Path: "/app", Secure: true, HostOnly: true},
+			wantErr: true,
+		},
+		{
+			name:    "__Host-でSecureなし",
+			cookie:  &Cookie{Name: "__Host-sid", Domain: "example.com", Path: "/", HostOnly: true},
+			wantErr: true,
+		},
+		{
+			name:    "プレフィックスの大文字小文字は区別しない",
+			cookie:  &Cookie{Name: "__HOST-sid", Domain: "example.com", Path: "/", Secure: true},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cookie.ValidatePrefix()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidatePrefix() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrCookiePrefix) {
+				t.Errorf("ValidatePrefix() error = %v, want ErrCookiePrefix", err)
+			}
+		})
+	}
+}
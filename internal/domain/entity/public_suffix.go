@@ -0,0 +1,51 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// ErrPublicSuffix は Cookie のドメインが公開サフィックス（com, co.uk, github.io など）であることを示すエラーです
+var ErrPublicSuffix = errors.New("cookie domain is a public suffix")
+
+// PublicSuffixList はドメインの公開サフィックスを求めます（net/http/cookiejar.PublicSuffixList と同じ形）
+type PublicSuffixList interface {
+	// PublicSuffix は domain の公開サフィックスを返します
+	PublicSuffix(domain string) string
+}
+
+// IsPublicSuffix は domain 自体が公開サフィックスかを判定します
+// psl が nil の場合と IP アドレスの場合は false を返します
+func IsPublicSuffix(psl PublicSuffixList, domain string) bool {
+	domain = CanonicalizeHost(domain)
+	if psl == nil || domain == "" || net.ParseIP(domain) != nil {
+		return false
+	}
+	return psl.PublicSuffix(domain) == domain
+}
+
+// RegistrableDomain は domain の登録可能ドメイン（公開サフィックスとその直前のラベル、eTLD+1）を返します
+// psl が nil の場合、IP アドレスの場合、domain 自体が公開サフィックスの場合は domain をそのまま返します
+func RegistrableDomain(psl PublicSuffixList, domain string) string {
+	domain = CanonicalizeHost(domain)
+	if psl == nil || domain == "" || net.ParseIP(domain) != nil {
+		return domain
+	}
+	suffix := psl.PublicSuffix(domain)
+	if suffix == domain || !strings.HasSuffix(domain, "."+suffix) {
+		return domain
+	}
+	rest := strings.TrimSuffix(domain, "."+suffix)
+	return rest[strings.LastIndex(rest, ".")+1:] + "." + suffix
+}
+
+// ValidatePublicSuffix は公開サフィックスを Domain 属性とする Cookie を拒否します
+// 公開サフィックスのホスト自体が設定した host-only Cookie は許可されます
+func (c *Cookie) ValidatePublicSuffix(psl PublicSuffixList) error {
+	if c.HostOnly || !IsPublicSuffix(psl, c.CanonicalDomain()) {
+		return nil
+	}
+	return fmt.Errorf("%w: %q", ErrPublicSuffix, c.Domain)
+}
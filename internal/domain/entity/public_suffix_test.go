@@ -0,0 +1,107 @@
+package entity
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// testPublicSuffixList は列挙したドメインと最上位のラベルを公開サフィックスとして扱います
+type testPublicSuffixList []string
+
+func (l testPublicSuffixList) PublicSuffix(domain string) string {
+	for _, suffix := range l {
+		if domain == suffix || strings.HasSuffix(domain, "."+suffix) {
+			return suffix
+		}
+	}
+	return domain[strings.LastIndex(domain, ".")+1:]
+}
+
+func TestCookie_ValidatePublicSuffix(t *testing.T) {
+	psl := testPublicSuffixList{"co.uk", "github.io"}
+
+	tests := []struct {
+		name    string
+		cookie  *Cookie
+		psl     PublicSuffixList
+		wantErr bool
+	}{
+		{
+			name:   "登録可能なドメイン",
+			cookie: &Cookie{Name: "sid", Domain: "example.co.uk"},
+			psl:    psl,
+		},
+		{
+			name:    "最上位ドメイン",
+			cookie:  &Cookie{Name: "sid", Domain: "com"},
+			psl:     psl,
+			wantErr: true,
+		},
+		{
+			name:    "複数ラベルの公開サフィックス",
+			cookie:  &Cookie{Name: "sid", Domain: ".CO.UK"},
+			psl:     psl,
+			wantErr: true,
+		},
+		{
+			name:    "プライベートセクションの公開サフィックス",
+			cookie:  &Cookie{Name: "sid", Domain: "github.io"},
+			psl:     psl,
+			wantErr: true,
+		},
+		{
+			name:   "公開サフィックスのhost-only Cookie",
+			cookie: &Cookie{Name: "sid", Domain: "github.io", HostOnly: true},
+			psl:    psl,
+		},
+		{
+			name:   "IPアドレス",
+			cookie: &Cookie{Name: "sid", Domain: "192.0.2.1"},
+			psl:    psl,
+		},
+		{
+			name:   "pslがnilの場合は検証しない",
+			cookie: &Cookie{Name: "sid", Domain: "com"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cookie.ValidatePublicSuffix(tt.psl)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidatePublicSuffix() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrPublicSuffix) {
+				t.Errorf("ValidatePublicSuffix() error = %v, want ErrPublicSuffix", err)
+			}
+		})
+	}
+}
+
+func TestRegistrableDomain(t *testing.T) {
+	psl := testPublicSuffixList{"co.uk", "github.io"}
+
+	tests := []struct {
+		name   string
+		psl    PublicSuffixList
+		domain string
+		want   string
+	}{
+		{name: "サブドメインは登録可能ドメインにまとめる", psl: psl, domain: "a.b.Example.com", want: "example.com"},
+		{name: "登録可能ドメイン自体", psl: psl, domain: "example.com", want: "example.com"},
+		{name: "複数ラベルの公開サフィックス", psl: psl, domain: "www.example.co.uk", want: "example.co.uk"},
+		{name: "プライベートセクションの公開サフィックス", psl: psl, domain: "user.github.io", want: "user.github.io"},
+		{name: "公開サフィックス自体", psl: psl, domain: "github.io", want: "github.io"},
+		{name: "IPアドレス", psl: psl, domain: "127.0.0.1", want: "127.0.0.1"},
+		{name: "pslがnilの場合はそのまま", domain: "a.example.com", want: "a.example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RegistrableDomain(tt.psl, tt.domain); got != tt.want {
+				t.Errorf("RegistrableDomain(%q) = %q, want %q", tt.domain, got, tt.want)
+			}
+		})
+	}
+}
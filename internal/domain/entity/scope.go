@@ -0,0 +1,106 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Permission は API キーに許可する Cookie の操作です
+type Permission string
+
+const (
+	PermissionRead   Permission = "read"
+	PermissionWrite  Permission = "write"
+	PermissionDelete Permission = "delete"
+)
+
+// AllHosts はすべてのホストに一致するホストパターンです
+const AllHosts = "*"
+
+// ErrInvalidScope はスコープの形式が不正であることを示すエラーです
+var ErrInvalidScope = errors.New("invalid scope")
+
+// Scope は API キーに許可する操作と、その対象となるホストのパターンの組です
+// ホストパターンは完全一致するホスト（"example.com"）、サブドメイン（"*.example.com"、example.com 自体は含まない）、
+// またはすべてのホスト（"*"）のいずれかです
+type Scope struct {
+	Permission  Permission
+	HostPattern string
+}
+
+// ParseScope は "read:*.example.com" 形式のスコープを解析します
+func ParseScope(s string) (Scope, error) {
+	permission, pattern, ok := strings.Cut(strings.TrimSpace(s), ":")
+	if !ok {
+		return Scope{}, fmt.Errorf("%w: %q must be <permission>:<host pattern>", ErrInvalidScope, s)
+	}
+
+	scope := Scope{Permission: Permission(strings.ToLower(permission)), HostPattern: CanonicalizeHost(pattern)}
+	switch scope.Permission {
+	case PermissionRead, PermissionWrite, PermissionDelete:
+	default:
+		return Scope{}, fmt.Errorf("%w: %q has unknown permission %q", ErrInvalidScope, s, permission)
+	}
+
+	host := strings.TrimPrefix(scope.HostPattern, "*.")
+	if scope.HostPattern != AllHosts && (host == "" || strings.Contains(host, "*") || strings.HasPrefix(host, ".")) {
+		return Scope{}, fmt.Errorf("%w: %q has invalid host pattern %q", ErrInvalidScope, s, pattern)
+	}
+	return scope, nil
+}
+
+// ParseScopes は複数のスコープを解析します
+func ParseScopes(values []string) ([]Scope, error) {
+	scopes := make([]Scope, 0, len(values))
+	for _, v := range values {
+		scope, err := ParseScope(v)
+		if err != nil {
+			return nil, err
+		}
+		scopes = append(scopes, scope)
+	}
+	return scopes, nil
+}
+
+// FullAccessScopes はすべてのホストに対するすべての操作を許可するスコープを返します
+func FullAccessScopes() []Scope {
+	return []Scope{
+		{Permission: PermissionRead, HostPattern: AllHosts},
+		{Permission: PermissionWrite, HostPattern: AllHosts},
+		{Permission: PermissionDelete, HostPattern: AllHosts},
+	}
+}
+
+// String は "read:*.example.com" 形式の文字列を返します
+func (s Scope) String() string {
+	return string(s.Permission) + ":" + s.HostPattern
+}
+
+// Allows はスコープが host に対する permission の操作を許可するかを判定します
+// host が空の場合（ホストを限定しない操作）は "*" のみが一致します
+func (s Scope) Allows(permission Permission, host string) bool {
+	if s.Permission != permission {
+		return false
+	}
+	if s.HostPattern == AllHosts {
+		return true
+	}
+	host = CanonicalizeHost(strings.TrimPrefix(host, "."))
+	if host == "" {
+		return false
+	}
+	if suffix, ok := strings.CutPrefix(s.HostPattern, "*"); ok {
+		return strings.HasSuffix(host, suffix)
+	}
+	return host == s.HostPattern
+}
+
+// ScopeStrings はスコープを文字列に変換します
+func ScopeStrings(scopes []Scope) []string {
+	result := make([]string, len(scopes))
+	for i, scope := range scopes {
+		result[i] = scope.String()
+	}
+	return result
+}
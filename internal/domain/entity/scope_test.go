@@ -0,0 +1,162 @@
+package entity
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseScope(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    Scope
+		wantErr bool
+	}{
+		{
+			name:  "完全一致のホスト",
+			input: "read:example.com",
+			want:  Scope{Permission: PermissionRead, HostPattern: "example.com"},
+		},
+		{
+			name:  "サブドメインのワイルドカード",
+			input: "write:*.Example.com.",
+			want:  Scope{Permission: PermissionWrite, HostPattern: "*.example.com"},
+		},
+		{
+			name:  "すべてのホスト",
+			input: "DELETE:*",
+			want:  Scope{Permission: PermissionDelete, HostPattern: AllHosts},
+		},
+		{
+			name:    "区切りがない",
+			input:   "read",
+			wantErr: true,
+		},
+		{
+			name:    "不明な権限",
+			input:   "admin:example.com",
+			wantErr: true,
+		},
+		{
+			name:    "ホストパターンが空",
+			input:   "read:",
+			wantErr: true,
+		},
+		{
+			name:    "先頭以外のワイルドカード",
+			input:   "read:api.*.example.com",
+			wantErr: true,
+		},
+		{
+			name:    "ラベルの一部のワイルドカード",
+			input:   "read:*example.com",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseScope(tt.input)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidScope) {
+					t.Errorf("ParseScope() error = %v, want %v", err, ErrInvalidScope)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseScope() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseScope() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScope_Allows(t *testing.T) {
+	tests := []struct {
+		name       string
+		scope      string
+		permission Permission
+		host       string
+		want       bool
+	}{
+		{
+			name:       "完全一致",
+			scope:      "read:example.com",
+			permission: PermissionRead,
+			host:       "Example.com",
+			want:       true,
+		},
+		{
+			name:       "完全一致のパターンはサブドメインに一致しない",
+			scope:      "read:example.com",
+			permission: PermissionRead,
+			host:       "api.example.com",
+		},
+		{
+			name:       "権限が異なる",
+			scope:      "read:example.com",
+			permission: PermissionWrite,
+			host:       "example.com",
+		},
+		{
+			name:       "ワイルドカードはサブドメインに一致する",
+			scope:      "read:*.example.com",
+			permission: PermissionRead,
+			host:       "a.b.example.com",
+			want:       true,
+		},
+		{
+			name:       "ワイルドカードは親ドメイン自体に一致しない",
+			scope:      "read:*.example.com",
+			permission: PermissionRead,
+			host:       "example.com",
+		},
+		{
+			name:       "ワイルドカードは末尾が同じだけの別ドメインに一致しない",
+			scope:      "read:*.example.com",
+			permission: PermissionRead,
+			host:       "badexample.com",
+		},
+		{
+			name:       "先頭のドットは無視する",
+			scope:      "write:example.com",
+			permission: PermissionWrite,
+			host:       ".example.com",
+			want:       true,
+		},
+		{
+			name:       "すべてのホスト",
+			scope:      "delete:*",
+			permission: PermissionDelete,
+			host:       "example.com",
+			want:       true,
+		},
+		{
+			name:       "ホストを限定しない操作はすべてのホストのパターンのみ許可する",
+			scope:      "delete:*.example.com",
+			permission: PermissionDelete,
+			host:       "",
+		},
+		{
+			name:       "すべてのホストのパターンはホストを限定しない操作を許可する",
+			scope:      "delete:*",
+			permission: PermissionDelete,
+			host:       "",
+			want:       true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scope, err := ParseScope(tt.scope)
+			if err != nil {
+				t.Fatalf("ParseScope() error = %v", err)
+			}
+			if got := scope.Allows(tt.permission, tt.host); got != tt.want {
+				t.Errorf("Allows(%q, %q) = %v, want %v", tt.permission, tt.host, got, tt.want)
+			}
+		})
+	}
+}
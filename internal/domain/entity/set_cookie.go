@@ -0,0 +1,94 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ErrCookieRejected はブラウザが保存を拒否する Cookie であることを示すエラーです
+var ErrCookieRejected = errors.New("cookie rejected")
+
+// ParseSetCookie は requestURL へのレスポンスで受け取った Set-Cookie ヘッダーの値を解析し、
+// RFC 6265 §5.3 のストレージモデルに従って保存すべき Cookie を返します
+// ブラウザが拒否する Cookie の場合は ErrCookieRejected をラップしたエラーを返します
+// psl が nil の場合は公開サフィックスの検証を行いません
+func ParseSetCookie(header string, requestURL *url.URL, now time.Time, psl PublicSuffixList) (*Cookie, error) {
+	httpCookie, err := http.ParseSetCookie(header)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCookieRejected, err)
+	}
+	// net/http は RFC 1123 などの一部の形式しか解釈しないため、Expires は RFC 6265 §5.1.1 のアルゴリズムで解析し直す
+	// 解析できない Expires 属性は無視する（RFC 6265 §5.2.1）
+	if httpCookie.RawExpires != "" {
+		httpCookie.Expires, _ = ParseCookieDate(httpCookie.RawExpires)
+	}
+	cookie := NewCookieAt(httpCookie, now)
+
+	host := CanonicalizeHost(requestURL.Hostname())
+	if host == "" {
+		return nil, fmt.Errorf("%w: request url has no host", ErrCookieRejected)
+	}
+
+	// Domain 属性がなければ host-only Cookie とし、あればリクエストホストに domain-match する必要がある
+	domain := CanonicalizeHost(strings.TrimPrefix(httpCookie.Domain, "."))
+	// Domain 属性が公開サフィックスの場合、リクエストホスト自体であれば host-only とし、そうでなければ拒否する（RFC 6265 §5.3 step 5）
+	if domain != "" && IsPublicSuffix(psl, domain) {
+		if domain != host {
+			return nil, fmt.Errorf("%w: %w: %q", ErrCookieRejected, ErrPublicSuffix, httpCookie.Domain)
+		}
+		domain = ""
+	}
+	switch {
+	case domain == "":
+		cookie.Domain = host
+		cookie.HostOnly = true
+	case !DomainMatch(host, domain):
+		return nil, fmt.Errorf("%w: domain %q does not match request host %q", ErrCookieRejected, httpCookie.Domain, host)
+	case domain != host && net.ParseIP(host) != nil:
+		return nil, fmt.Errorf("%w: domain attribute is not allowed for ip address host", ErrCookieRejected)
+	default:
+		cookie.Domain = domain
+		cookie.HostOnly = false
+	}
+
+	// Path 属性がない、または "/" で始まらない場合はリクエスト URI から既定のパスを求める
+	if !strings.HasPrefix(cookie.Path, "/") {
+		cookie.Path = DefaultPath(requestURL.EscapedPath())
+	}
+
+	// 安全でないスキームからは Secure 属性付きの Cookie を設定できない
+	secureOrigin := IsSecureScheme(requestURL.Scheme)
+	if cookie.Secure && !secureOrigin {
+		return nil, fmt.Errorf("%w: secure cookie from insecure origin", ErrCookieRejected)
+	}
+	// SameSite=None と Partitioned は Secure 属性が必須
+	if cookie.SameSite == http.SameSiteNoneMode && !cookie.Secure {
+		return nil, fmt.Errorf("%w: SameSite=None requires Secure", ErrCookieRejected)
+	}
+	if cookie.Partitioned && !cookie.Secure {
+		return nil, fmt.Errorf("%w: Partitioned requires Secure", ErrCookieRejected)
+	}
+	// __Secure- / __Host- プレフィックスの要件
+	if err := cookie.ValidatePrefix(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCookieRejected, err)
+	}
+
+	return cookie, nil
+}
+
+// DefaultPath は RFC 6265 §5.1.4 に従い、リクエスト URI のパスから既定の Cookie パスを求めます
+func DefaultPath(uriPath string) string {
+	if uriPath == "" || uriPath[0] != '/' {
+		return "/"
+	}
+	i := strings.LastIndex(uriPath, "/")
+	if i == 0 {
+		return "/"
+	}
+	return uriPath[:i]
+}
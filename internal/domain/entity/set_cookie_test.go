@@ -0,0 +1,214 @@
+package entity
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestParseSetCookie(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		header     string
+		requestURL string
+		want       *Cookie
+		wantErr    bool
+	}{
+		{
+			name:       "Domain属性がなければhost-onlyになる",
+			header:     "sid=abc",
+			requestURL: "https://www.example.com/",
+			want:       &Cookie{Name: "sid", Value: "abc", Domain: "www.example.com", HostOnly: true, Path: "/"},
+		},
+		{
+			name:       "Domain属性があれば親ドメインに設定できる",
+			header:     "sid=abc; Domain=.Example.COM; Path=/app",
+			requestURL: "https://www.example.com/",
+			want:       &Cookie{Name: "sid", Value: "abc", Domain: "example.com", Path: "/app"},
+		},
+		{
+			name:       "Path属性がなければリクエストURIから既定のパスを求める",
+			header:     "sid=abc",
+			requestURL: "https://example.com/docs/index.html",
+			want:       &Cookie{Name: "sid", Value: "abc", Domain: "example.com", HostOnly: true, Path: "/docs"},
+		},
+		{
+			name:       "Max-AgeはExpiresより優先される",
+			header:     "sid=abc; Max-Age=60; Expires=Wed, 01 Jan 2031 00:00:00 GMT",
+			requestURL: "https://example.com/",
+			want:       &Cookie{Name: "sid", Value: "abc", Domain: "example.com", HostOnly: true, Path: "/", Expires: now.Add(time.Minute)},
+		},
+		{
+			name:       "Expires属性を解釈する",
+			header:     "sid=abc; Expires=Wed, 01 Jan 2031 00:00:00 GMT",
+			requestURL: "https://example.com/",
+			want:       &Cookie{Name: "sid", Value: "abc", Domain: "example.com", HostOnly: true, Path: "/", Expires: time.Date(2031, 1, 1, 0, 0, 0, 0, time.UTC)},
+		},
+		{
+			name:       "2桁の年のExpiresを解釈する",
+			header:     "sid=abc; Expires=Wed, 01-Jan-31 00:00:00 GMT",
+			requestURL: "https://example.com/",
+			want:       &Cookie{Name: "sid", Value: "abc", Domain: "example.com", HostOnly: true, Path: "/", Expires: time.Date(2031, 1, 1, 0, 0, 0, 0, time.UTC)},
+		},
+		{
+			name:       "asctime形式のExpiresを解釈する",
+			header:     "sid=abc; Expires=Wed Jan 1 00:00:00 2031",
+			requestURL: "https://example.com/",
+			want:       &Cookie{Name: "sid", Value: "abc", Domain: "example.com", HostOnly: true, Path: "/", Expires: time.Date(2031, 1, 1, 0, 0, 0, 0, time.UTC)},
+		},
+		{
+			name:       "過去の2桁の年のExpiresは失効したCookieになる",
+			header:     "sid=; Expires=Thu, 01-Jan-70 00:00:01 GMT",
+			requestURL: "https://example.com/",
+			want:       &Cookie{Name: "sid", Domain: "example.com", HostOnly: true, Path: "/", Expires: time.Date(1970, 1, 1, 0, 0, 1, 0, time.UTC)},
+		},
+		{
+			name:       "解析できないExpiresは無視してセッションCookieにする",
+			header:     "sid=abc; Expires=someday",
+			requestURL: "https://example.com/",
+			want:       &Cookie{Name: "sid", Value: "abc", Domain: "example.com", HostOnly: true, Path: "/"},
+		},
+		{
+			name:       "SameSite・Secure・HttpOnly・Partitionedを解釈する",
+			header:     "sid=abc; Secure; HttpOnly; SameSite=None; Partitioned",
+			requestURL: "https://example.com/",
+			want:       &Cookie{Name: "sid", Value: "abc", Domain: "example.com", HostOnly: true, Path: "/", Secure: true, HttpOnly: true, SameSite: http.SameSiteNoneMode, Partitioned: true},
+		},
+		{
+			name:       "リクエストホストにdomain-matchしないDomainは拒否する",
+			header:     "sid=abc; Domain=other.com",
+			requestURL: "https://example.com/",
+			wantErr:    true,
+		},
+		{
+			name:       "IPアドレスのホストに別のDomainは拒否する",
+			header:     "sid=abc; Domain=0.0.1",
+			requestURL: "http://127.0.0.1/",
+			wantErr:    true,
+		},
+		{
+			name:       "安全でないスキームからのSecure Cookieは拒否する",
+			header:     "sid=abc; Secure",
+			requestURL: "http://example.com/",
+			wantErr:    true,
+		},
+		{
+			name:       "SecureなしのSameSite=Noneは拒否する",
+			header:     "sid=abc; SameSite=None",
+			requestURL: "https://example.com/",
+			wantErr:    true,
+		},
+		{
+			name:       "SecureなしのPartitionedは拒否する",
+			header:     "sid=abc; Partitioned",
+			requestURL: "https://example.com/",
+			wantErr:    true,
+		},
+		{
+			name:       "__Host-プレフィックスの要件を満たすCookie",
+			header:     "__Host-sid=abc; Secure; Path=/",
+			requestURL: "https://www.example.com/docs/",
+			want:       &Cookie{Name: "__Host-sid", Value: "abc", Domain: "www.example.com", HostOnly: true, Path: "/", Secure: true},
+		},
+		{
+			name:       "Domain属性のある__Host-プレフィックスは拒否する",
+			header:     "__Host-sid=abc; Secure; Path=/; Domain=example.com",
+			requestURL: "https://www.example.com/",
+			wantErr:    true,
+		},
+		{
+			name:       "Path属性がなく既定のパスが/以外の__Host-プレフィックスは拒否する",
+			header:     "__Host-sid=abc; Secure",
+			requestURL: "https://www.example.com/docs/index.html",
+			wantErr:    true,
+		},
+		{
+			name:       "Secureのない__Secure-プレフィックスは拒否する",
+			header:     "__Secure-sid=abc",
+			requestURL: "https://www.example.com/",
+			wantErr:    true,
+		},
+		{
+			name:       "公開サフィックスをDomain属性とするCookieは拒否する",
+			header:     "sid=abc; Domain=com",
+			requestURL: "https://www.example.com/",
+			wantErr:    true,
+		},
+		{
+			name:       "プライベートセクションの公開サフィックスも拒否する",
+			header:     "sid=abc; Domain=github.io",
+			requestURL: "https://user.github.io/",
+			wantErr:    true,
+		},
+		{
+			name:       "公開サフィックスのホスト自体が設定した場合はhost-onlyになる",
+			header:     "sid=abc; Domain=github.io",
+			requestURL: "https://github.io/",
+			want:       &Cookie{Name: "sid", Value: "abc", Domain: "github.io", HostOnly: true, Path: "/"},
+		},
+		{
+			name:       "解析できないヘッダーは拒否する",
+			header:     "invalid",
+			requestURL: "https://example.com/",
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requestURL, _ := url.Parse(tt.requestURL)
+			got, err := ParseSetCookie(tt.header, requestURL, now, testPublicSuffixList{"com", "github.io"})
+			if tt.wantErr {
+				if !errors.Is(err, ErrCookieRejected) {
+					t.Errorf("ParseSetCookie() error = %v, want ErrCookieRejected", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseSetCookie() error = %v", err)
+			}
+
+			if got.Name != tt.want.Name || got.Value != tt.want.Value {
+				t.Errorf("Name/Value = %v=%v, want %v=%v", got.Name, got.Value, tt.want.Name, tt.want.Value)
+			}
+			if got.Domain != tt.want.Domain || got.HostOnly != tt.want.HostOnly {
+				t.Errorf("Domain/HostOnly = %v/%v, want %v/%v", got.Domain, got.HostOnly, tt.want.Domain, tt.want.HostOnly)
+			}
+			if got.Path != tt.want.Path {
+				t.Errorf("Path = %v, want %v", got.Path, tt.want.Path)
+			}
+			if !got.Expires.Equal(tt.want.Expires) {
+				t.Errorf("Expires = %v, want %v", got.Expires, tt.want.Expires)
+			}
+			if got.Secure != tt.want.Secure || got.HttpOnly != tt.want.HttpOnly || got.SameSite != tt.want.SameSite || got.Partitioned != tt.want.Partitioned {
+				t.Errorf("attributes = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDefaultPath(t *testing.T) {
+	tests := []struct {
+		uriPath string
+		want    string
+	}{
+		{"", "/"},
+		{"relative", "/"},
+		{"/", "/"},
+		{"/index.html", "/"},
+		{"/docs/", "/docs"},
+		{"/docs/guide/index.html", "/docs/guide"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.uriPath, func(t *testing.T) {
+			if got := DefaultPath(tt.uriPath); got != tt.want {
+				t.Errorf("DefaultPath(%q) = %v, want %v", tt.uriPath, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package entity
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+)
+
+// AllJars はすべての jar に一致する jar の指定です
+const AllJars = "*"
+
+// ErrInvalidToken は JWT の形式・署名・有効期限などが不正であることを示すエラーです
+var ErrInvalidToken = errors.New("invalid token")
+
+// TokenVerifier は JWT の署名と登録済みクレーム（exp・nbf・iss・aud）を検証し、ペイロードのクレームを返します
+// 検証に失敗した場合は ErrInvalidToken をラップしたエラーを返します
+type TokenVerifier interface {
+	Verify(ctx context.Context, token string) (map[string]any, error)
+}
+
+// TokenClaims は検証済みの JWT（ベアラートークン）の主体と、クレームから得た権限です
+type TokenClaims struct {
+	Subject   string
+	Issuer    string
+	ExpiresAt time.Time
+	Scopes    []Scope
+	// Jars は操作を許可する jar の名前（AllJars を含む場合はすべての jar）
+	Jars []string
+}
+
+// Allows はトークンのいずれかのスコープが host に対する permission の操作を許可するかを判定します
+func (c *TokenClaims) Allows(permission Permission, host string) bool {
+	for _, scope := range c.Scopes {
+		if scope.Allows(permission, host) {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsJar はトークンが jar の操作を許可するかを判定します
+func (c *TokenClaims) AllowsJar(jar string) bool {
+	return slices.Contains(c.Jars, AllJars) || slices.Contains(c.Jars, jar)
+}
+
+// ParseTokenScopes はクレームのスコープを解析します
+// OAuth 2.0 のスコープには "openid" など Cookie の操作以外のものも含まれるため、解析できないスコープは無視します
+func ParseTokenScopes(values []string) []Scope {
+	scopes := make([]Scope, 0, len(values))
+	for _, v := range values {
+		if scope, err := ParseScope(v); err == nil {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes
+}
+
+// ClaimStrings はクレームの値を、スペース区切りの文字列（OAuth 2.0 の scope と同じ形式）または文字列の配列として解釈します
+// 値がない場合や形式が異なる場合は ok = false を返します
+func ClaimStrings(claims map[string]any, name string) (values []string, ok bool) {
+	switch v := claims[name].(type) {
+	case string:
+		return strings.Fields(v), true
+	case []any:
+		values = make([]string, 0, len(v))
+		for _, item := range v {
+			s, isString := item.(string)
+			if !isString {
+				return nil, false
+			}
+			values = append(values, s)
+		}
+		return values, true
+	default:
+		return nil, false
+	}
+}
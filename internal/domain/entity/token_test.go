@@ -0,0 +1,107 @@
+package entity
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTokenClaims_AllowsJar(t *testing.T) {
+	tests := []struct {
+		name string
+		jars []string
+		jar  string
+		want bool
+	}{
+		{
+			name: "許可された jar",
+			jars: []string{"bot-1", "bot-2"},
+			jar:  "bot-2",
+			want: true,
+		},
+		{
+			name: "許可されていない jar",
+			jars: []string{"bot-1"},
+			jar:  DefaultJar,
+			want: false,
+		},
+		{
+			name: "すべての jar",
+			jars: []string{AllJars},
+			jar:  "bot-1",
+			want: true,
+		},
+		{
+			name: "jar の指定なし",
+			jar:  DefaultJar,
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := &TokenClaims{Jars: tt.jars}
+			if got := claims.AllowsJar(tt.jar); got != tt.want {
+				t.Errorf("AllowsJar(%q) = %v, want %v", tt.jar, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTokenScopes(t *testing.T) {
+	scopes := ParseTokenScopes([]string{"openid", "read:*.example.com", "profile", "admin:*", "write:example.com"})
+	want := []string{"read:*.example.com", "write:example.com"}
+	if got := ScopeStrings(scopes); !slices.Equal(got, want) {
+		t.Errorf("ParseTokenScopes() = %v, want %v", got, want)
+	}
+
+	claims := &TokenClaims{Scopes: scopes}
+	if !claims.Allows(PermissionRead, "api.example.com") {
+		t.Error("Allows(read, api.example.com) = false, want true")
+	}
+	if claims.Allows(PermissionDelete, "example.com") {
+		t.Error("Allows(delete, example.com) = true, want false")
+	}
+}
+
+func TestClaimStrings(t *testing.T) {
+	tests := []struct {
+		name   string
+		claims map[string]any
+		want   []string
+		wantOK bool
+	}{
+		{
+			name:   "スペース区切りの文字列",
+			claims: map[string]any{"scope": "openid  read:*"},
+			want:   []string{"openid", "read:*"},
+			wantOK: true,
+		},
+		{
+			name:   "文字列の配列",
+			claims: map[string]any{"scope": []any{"read:*", "write:*"}},
+			want:   []string{"read:*", "write:*"},
+			wantOK: true,
+		},
+		{
+			name:   "文字列以外を含む配列",
+			claims: map[string]any{"scope": []any{"read:*", 1.0}},
+		},
+		{
+			name:   "数値",
+			claims: map[string]any{"scope": 1.0},
+		},
+		{
+			name:   "クレームなし",
+			claims: map[string]any{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ClaimStrings(tt.claims, "scope")
+			if ok != tt.wantOK || !slices.Equal(got, tt.want) {
+				t.Errorf("ClaimStrings() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
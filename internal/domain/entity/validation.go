@@ -0,0 +1,93 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// ValidateCookieName は名前が RFC 6265 §4.1.1 の cookie-name（RFC 2616 の token）であるかを検証します
+func ValidateCookieName(name string) error {
+	if name == "" {
+		return errors.New("name is required")
+	}
+	for i := 0; i < len(name); i++ {
+		if !isTokenChar(name[i]) {
+			return fmt.Errorf("name contains invalid character %q", name[i])
+		}
+	}
+	return nil
+}
+
+// ValidateCookieValue は値が RFC 6265 §4.1.1 の cookie-value（ダブルクォートで囲まれていてもよい cookie-octet の並び）であるかを検証します
+func ValidateCookieValue(value string) error {
+	if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+		value = value[1 : len(value)-1]
+	}
+	for i := 0; i < len(value); i++ {
+		if !isCookieOctet(value[i]) {
+			return fmt.Errorf("value contains invalid character %q", value[i])
+		}
+	}
+	return nil
+}
+
+// ValidateCookieDomain はドメインがホスト名または IP アドレスとして妥当かを検証します（先頭のドットは許可）
+func ValidateCookieDomain(domain string) error {
+	host := CanonicalizeHost(strings.TrimPrefix(domain, "."))
+	if host == "" {
+		return errors.New("domain is required")
+	}
+	if net.ParseIP(host) != nil {
+		return nil
+	}
+	if len(host) > 253 {
+		return errors.New("domain is too long")
+	}
+	for _, label := range strings.Split(host, ".") {
+		if label == "" || len(label) > 63 {
+			return fmt.Errorf("domain has invalid label %q", label)
+		}
+		for i := 0; i < len(label); i++ {
+			if !isHostChar(label[i]) {
+				return fmt.Errorf("domain contains invalid character %q", label[i])
+			}
+		}
+	}
+	return nil
+}
+
+// ValidateCookiePath はパスが "/" で始まり、RFC 6265 §4.1.1 の path-value（制御文字と ";" 以外）であるかを検証します
+// 空文字列は既定のパス "/" として扱うため許可します
+func ValidateCookiePath(path string) error {
+	if path == "" {
+		return nil
+	}
+	if path[0] != '/' {
+		return errors.New(`path must start with "/"`)
+	}
+	for i := 0; i < len(path); i++ {
+		if c := path[i]; c < 0x20 || c == 0x7f || c == ';' {
+			return fmt.Errorf("path contains invalid character %q", c)
+		}
+	}
+	return nil
+}
+
+// isTokenChar は RFC 2616 §2.2 の token を構成する文字（制御文字と区切り文字以外の US-ASCII）かを判定します
+func isTokenChar(c byte) bool {
+	if c <= 0x20 || c >= 0x7f {
+		return false
+	}
+	return !strings.ContainsRune(`()<>@,;:\"/[]?={}`, rune(c))
+}
+
+// isCookieOctet は cookie-octet（%x21 / %x23-2B / %x2D-3A / %x3C-5B / %x5D-7E）かを判定します
+func isCookieOctet(c byte) bool {
+	return c >= 0x21 && c <= 0x7e && c != '"' && c != ',' && c != ';' && c != '\\'
+}
+
+func isHostChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '-' || c == '_'
+}
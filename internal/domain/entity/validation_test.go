@@ -0,0 +1,77 @@
+package entity
+
+import "testing"
+
+func TestValidateCookieName(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{"英数字と記号", "session_id-1.x!", false},
+		{"空", "", true},
+		{"空白を含む", "session id", true},
+		{"区切り文字を含む", "a=b", true},
+		{"制御文字を含む", "a\tb", true},
+		{"非ASCII文字を含む", "クッキー", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateCookieName(tt.input); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCookieName(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateCookieValue(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{"空", "", false},
+		{"cookie-octetのみ", "abc123+/=:", false},
+		{"ダブルクォートで囲まれた値", `"abc"`, false},
+		{"途中のダブルクォート", `a"b`, true},
+		{"カンマを含む", "a,b", true},
+		{"空白を含む", "a b", true},
+		{"バックスラッシュを含む", `a\b`, true},
+		{"制御文字を含む", "a\x7fb", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateCookieValue(tt.input); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCookieValue(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateCookieDomain(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{"ホスト名", "www.example.com", false},
+		{"先頭のドット", ".Example.COM", false},
+		{"IPv4アドレス", "127.0.0.1", false},
+		{"IPv6アドレス", "::1", false},
+		{"空", "", true},
+		{"ドットのみ", ".", true},
+		{"空のラベル", "example..com", true},
+		{"ポートを含む", "example.com:8080", true},
+		{"スラッシュを含む", "example.com/path", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateCookieDomain(tt.input); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateCookieDomain(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+		})
+	}
+}
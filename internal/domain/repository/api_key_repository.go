@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/takumi3488/cookiejar-server/internal/domain/entity"
+)
+
+type APIKeyRepository interface {
+	// Create は API キーのハッシュとスコープを保存し、採番された API キーを返します
+	Create(ctx context.Context, name, prefix, keyHash string, scopes []entity.Scope, createdAt time.Time) (*entity.APIKey, error)
+	// FindByHash はハッシュが一致する API キーを返します（存在しない場合は nil）
+	FindByHash(ctx context.Context, keyHash string) (*entity.APIKey, error)
+	FindAll(ctx context.Context) ([]*entity.APIKey, error)
+	// Revoke は有効な API キーを無効化し、無効化した件数を返します
+	Revoke(ctx context.Context, id int64, revokedAt time.Time) (int, error)
+}
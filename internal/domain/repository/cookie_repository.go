@@ -7,10 +7,58 @@ import (
 	"github.com/takumi3488/cookiejar-server/internal/domain/entity"
 )
 
+// CookieFilter は一括削除の対象を絞り込む条件です（未設定の条件は無視されます）
+type CookieFilter struct {
+	// 正規化済みのドメインと完全一致
+	Domain string
+	// 登録可能ドメイン（eTLD+1）と一致、またはそのサブドメイン
+	Site string
+	// 名前の前方一致
+	NamePrefix string
+	// 期限切れのCookieのみ
+	ExpiredOnly bool
+}
+
+// IsEmpty はいずれの条件も設定されていない場合に true を返します
+func (f CookieFilter) IsEmpty() bool {
+	return f.Domain == "" && f.Site == "" && f.NamePrefix == "" && !f.ExpiredOnly
+}
+
+// CookieRepository は Cookie の永続化を行います
+// PurgeExpired と WithinTx 以外の操作は jar 引数で指定した jar の Cookie のみを対象とします
+// 存在しない jar へ Cookie を保存しようとした場合は entity.ErrJarNotFound を返します
 type CookieRepository interface {
-	Upsert(ctx context.Context, cookie *entity.Cookie, updatedAt time.Time) error
-	UpsertMany(ctx context.Context, host string, cookies []*entity.Cookie, updatedAt time.Time) error
-	FindAll(ctx context.Context) ([]*entity.Cookie, error)
+	Upsert(ctx context.Context, jar string, cookie *entity.Cookie, updatedAt time.Time) error
+	// UpsertMany は複数のCookieを1つのトランザクションで保存します
+	// 同じ識別キーのCookieは後勝ちとし、並行する書き込みとのデッドロックを防ぐため識別キー順に書き込みます
+	UpsertMany(ctx context.Context, jar string, cookies []*entity.Cookie, updatedAt time.Time) error
+	FindAll(ctx context.Context, jar string) ([]*entity.Cookie, error)
+
+	// FindByHost は Domain がホストと一致するCookie（host-only を含む）を返します（先頭のドットは無視します）
+	FindByHost(ctx context.Context, jar, host string) ([]*entity.Cookie, error)
+	FindByDomainMatch(ctx context.Context, jar, host string) ([]*entity.Cookie, error)
+
+	// Touch は識別キーに一致するCookieの最終アクセス時刻を accessedAt に更新し、更新した件数を返します
+	Touch(ctx context.Context, jar string, keys []entity.CookieKey, accessedAt time.Time) (int, error)
+
+	// Delete は識別キーに一致するCookieを削除し、削除した件数を返します
+	Delete(ctx context.Context, jar string, key entity.CookieKey) (int, error)
+	// DeleteByHost は Domain がホストと一致するCookie（host-only を含む）をすべて削除します（先頭のドットは無視します）
+	DeleteByHost(ctx context.Context, jar, host string) (int, error)
+	// DeleteByFilter は now 時点の状態で条件に一致するCookieをすべて削除します
+	DeleteByFilter(ctx context.Context, jar string, filter CookieFilter, now time.Time) (int, error)
+
+	// Count は Domain が登録可能ドメイン site と一致するか、そのサブドメインであるCookieの件数を返します（site が空の場合はすべてのCookieの件数）
+	Count(ctx context.Context, jar, site string) (int, error)
+	// EvictLeastRecentlyUsed は Domain が登録可能ドメイン site と一致するか、そのサブドメインであるCookie（site が空の場合はすべてのCookie）のうち、
+	// 最も長くアクセスされていないものから最大 n 件削除し、削除した件数を返します
+	EvictLeastRecentlyUsed(ctx context.Context, jar, site string, n int) (int, error)
+
+	// PurgeExpired は now 時点で期限切れのCookieをすべての jar から最大 batchSize 件削除し、削除した件数を返します
+	PurgeExpired(ctx context.Context, now time.Time, batchSize int) (int, error)
 
-	FindByHost(ctx context.Context, host string) ([]*entity.Cookie, error)
+	// WithinTx は fn を1つのトランザクション（Unit of Work）として実行します
+	// fn に渡されるリポジトリの操作はすべて同じトランザクションで行われ、fn がエラーを返した場合はロールバックされます
+	// すでにトランザクション内のリポジトリから呼び出した場合は、そのトランザクションに参加します
+	WithinTx(ctx context.Context, fn func(repo CookieRepository) error) error
 }
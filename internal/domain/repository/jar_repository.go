@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/takumi3488/cookiejar-server/internal/domain/entity"
+)
+
+type JarRepository interface {
+	// Create は jar を作成します（同じ名前の jar が存在する場合は entity.ErrJarExists）
+	Create(ctx context.Context, name string, createdAt time.Time) (*entity.Jar, error)
+	// Find は jar を返します（存在しない場合は nil）
+	Find(ctx context.Context, name string) (*entity.Jar, error)
+	// FindAll はすべての jar を Cookie の数とともに返します
+	FindAll(ctx context.Context) ([]*entity.Jar, error)
+	// Delete は jar とその Cookie を削除し、削除した jar の件数を返します
+	Delete(ctx context.Context, name string) (int, error)
+	// Clone は src の Cookie をすべて複製した新しい jar dst を1つのトランザクションで作成し、複製した Cookie の数を設定して返します
+	// src が存在しない場合は entity.ErrJarNotFound、dst が存在する場合は entity.ErrJarExists を返します
+	Clone(ctx context.Context, src, dst string, createdAt time.Time) (*entity.Jar, error)
+}
@@ -0,0 +1,280 @@
+// Package jwt は JWKS（JSON Web Key Set）の公開鍵でベアラートークン（JWT）の署名と有効期限を検証します
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// minRefreshInterval は JWKS を読み込み直す最短の間隔です（未知の kid のトークンで JWKS の取得が繰り返されないようにする）
+	minRefreshInterval = 10 * time.Second
+	// maxJWKSSize は JWKS の最大サイズです
+	maxJWKSSize = 1 << 20
+	// minRSAKeyBits は受け付ける RSA 鍵の最小ビット数です
+	minRSAKeyBits = 2048
+)
+
+// publicKey は JWKS の署名検証用の公開鍵です
+type publicKey struct {
+	id string
+	// alg は鍵に指定されたアルゴリズム（空の場合は鍵の種類に合うすべてのアルゴリズムを受け付ける）
+	alg string
+	key crypto.PublicKey
+}
+
+// KeySet はファイルまたは URL（http:// / https://）から読み込んだ JWKS をキャッシュします
+// Refresh で読み込み直し、失敗した場合は以前の鍵を使い続けます
+type KeySet struct {
+	source string
+	client *http.Client
+	now    func() time.Time
+
+	// refreshMu は読み込みを1つに限定します
+	refreshMu sync.Mutex
+
+	mu          sync.RWMutex
+	keys        []publicKey
+	refreshedAt time.Time
+}
+
+// NewKeySet は source の JWKS を読み込んだ KeySet を返します
+func NewKeySet(ctx context.Context, source string) (*KeySet, error) {
+	s := &KeySet{
+		source: source,
+		client: &http.Client{Timeout: 10 * time.Second},
+		now:    time.Now,
+	}
+	if err := s.refresh(ctx); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Refresh は JWKS を読み込み直します（scheduler.Job として定期実行する）
+// 直前に読み込んでいる場合は何もしません
+func (s *KeySet) Refresh(ctx context.Context) error {
+	s.refreshMu.Lock()
+	defer s.refreshMu.Unlock()
+
+	if !s.refreshable() {
+		return nil
+	}
+	return s.refresh(ctx)
+}
+
+// lookup は kid の公開鍵を返します（kid が空の場合はすべての公開鍵）
+// kid の鍵がない場合は、鍵がローテーションされた可能性があるため JWKS を読み込み直してから探します
+func (s *KeySet) lookup(ctx context.Context, kid string) []publicKey {
+	if keys := s.find(kid); len(keys) > 0 || kid == "" {
+		return keys
+	}
+
+	s.refreshMu.Lock()
+	defer s.refreshMu.Unlock()
+	// 待っている間に他のリクエストが読み込み直している場合がある
+	if keys := s.find(kid); len(keys) > 0 || !s.refreshable() {
+		return keys
+	}
+	if err := s.refresh(ctx); err != nil {
+		log.Printf("Failed to refresh JWKS for unknown kid %q: %v", kid, err)
+	}
+	return s.find(kid)
+}
+
+func (s *KeySet) find(kid string) []publicKey {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if kid == "" {
+		return s.keys
+	}
+	var keys []publicKey
+	for _, key := range s.keys {
+		if key.id == kid {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+func (s *KeySet) refreshable() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.now().Sub(s.refreshedAt) >= minRefreshInterval
+}
+
+// refresh は JWKS を読み込み、署名検証に使える鍵が1つ以上ある場合のみ置き換えます
+func (s *KeySet) refresh(ctx context.Context) error {
+	data, err := s.read(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read JWKS from %s: %w", s.source, err)
+	}
+	keys, err := parseJWKS(data)
+	if err != nil {
+		return fmt.Errorf("invalid JWKS from %s: %w", s.source, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	changed := !slices.Equal(keyIDs(keys), keyIDs(s.keys))
+	s.keys = keys
+	s.refreshedAt = s.now()
+	if changed {
+		log.Printf("Loaded %d keys from JWKS %s (kid=%v)", len(keys), s.source, keyIDs(keys))
+	}
+	return nil
+}
+
+func keyIDs(keys []publicKey) []string {
+	ids := make([]string, len(keys))
+	for i, key := range keys {
+		ids[i] = key.id
+	}
+	return ids
+}
+
+func (s *KeySet) read(ctx context.Context) ([]byte, error) {
+	if !strings.HasPrefix(s.source, "http://") && !strings.HasPrefix(s.source, "https://") {
+		return os.ReadFile(s.source)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.source, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/jwk-set+json, application/json")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxJWKSSize))
+}
+
+// jsonWebKey は JWKS の鍵（RFC 7517）のうち、署名検証に使う項目です
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	// RSA
+	N string `json:"n"`
+	E string `json:"e"`
+	// EC・OKP
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// parseJWKS は JWKS から署名検証に使える公開鍵を取り出します
+// 暗号化用（use が "enc"）の鍵や対応していない種類の鍵は無視します
+func parseJWKS(data []byte) ([]publicKey, error) {
+	var jwks struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &jwks); err != nil {
+		return nil, err
+	}
+
+	keys := make([]publicKey, 0, len(jwks.Keys))
+	for _, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			log.Printf("Ignored JWKS key kid=%q: %v", jwk.Kid, err)
+			continue
+		}
+		keys = append(keys, publicKey{id: jwk.Kid, alg: jwk.Alg, key: key})
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("no signing keys found")
+	}
+	return keys, nil
+}
+
+func (jwk *jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch jwk.Kty {
+	case "RSA":
+		n, err := decodeBigInt(jwk.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid modulus: %w", err)
+		}
+		e, err := decodeBigInt(jwk.E)
+		if err != nil || !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 {
+			return nil, errors.New("invalid exponent")
+		}
+		if n.BitLen() < minRSAKeyBits {
+			return nil, fmt.Errorf("RSA key must be at least %d bits", minRSAKeyBits)
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		switch jwk.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", jwk.Crv)
+		}
+		x, errX := base64.RawURLEncoding.DecodeString(jwk.X)
+		y, errY := base64.RawURLEncoding.DecodeString(jwk.Y)
+		size := (curve.Params().BitSize + 7) / 8
+		if errX != nil || errY != nil || len(x) != size || len(y) != size {
+			return nil, errors.New("invalid coordinates")
+		}
+		// 非圧縮形式（0x04 || X || Y）に変換して曲線上の点であるかを検証する
+		return ecdsa.ParseUncompressedPublicKey(curve, append(append([]byte{4}, x...), y...))
+
+	case "OKP":
+		if jwk.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", jwk.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid public key")
+		}
+		return ed25519.PublicKey(x), nil
+
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", jwk.Kty)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == 0 {
+		return nil, errors.New("empty value")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
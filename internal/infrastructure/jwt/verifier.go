@@ -0,0 +1,222 @@
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/takumi3488/cookiejar-server/internal/domain/entity"
+)
+
+// clockSkew は exp・nbf の検証で許容する、トークンの発行者との時刻のずれです
+const clockSkew = 30 * time.Second
+
+// ecdsaCurveBits は ECDSA の署名アルゴリズムに対応する曲線のビット数です（ES512 は P-521）
+var ecdsaCurveBits = map[string]int{"ES256": 256, "ES384": 384, "ES512": 521}
+
+// Verifier は KeySet の公開鍵で JWT を検証します（entity.TokenVerifier の実装）
+// 対応する署名アルゴリズムは RS256/384/512・PS256/384/512・ES256/384/512・EdDSA（Ed25519）です
+type Verifier struct {
+	keys *KeySet
+	// issuer・audience が空でない場合は iss・aud クレームを検証する
+	issuer   string
+	audience string
+	now      func() time.Time
+}
+
+// NewVerifier は keys の公開鍵で JWT を検証する Verifier を返します
+func NewVerifier(keys *KeySet, issuer, audience string) *Verifier {
+	return &Verifier{
+		keys:     keys,
+		issuer:   issuer,
+		audience: audience,
+		now:      time.Now,
+	}
+}
+
+type header struct {
+	Alg  string   `json:"alg"`
+	Kid  string   `json:"kid"`
+	Crit []string `json:"crit"`
+}
+
+// Verify は JWT（JWS Compact Serialization）の署名と exp・nbf・iss・aud クレームを検証し、ペイロードのクレームを返します
+func (v *Verifier) Verify(ctx context.Context, token string) (map[string]any, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: malformed token", entity.ErrInvalidToken)
+	}
+
+	var h header
+	if err := decodeSegment(parts[0], &h); err != nil {
+		return nil, fmt.Errorf("%w: malformed header: %v", entity.ErrInvalidToken, err)
+	}
+	// 拡張ヘッダー（crit）は解釈できないため拒否する（RFC 7515 §4.1.11）
+	if len(h.Crit) > 0 {
+		return nil, fmt.Errorf("%w: unsupported critical header %v", entity.ErrInvalidToken, h.Crit)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed signature: %v", entity.ErrInvalidToken, err)
+	}
+	if err := v.verifySignature(ctx, h, []byte(parts[0]+"."+parts[1]), signature); err != nil {
+		return nil, err
+	}
+
+	var claims map[string]any
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("%w: malformed payload: %v", entity.ErrInvalidToken, err)
+	}
+	if claims == nil {
+		return nil, fmt.Errorf("%w: payload must be a JSON object", entity.ErrInvalidToken)
+	}
+	if err := v.validateClaims(claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// verifySignature は kid の公開鍵のうち alg に対応するいずれかで署名を検証します
+func (v *Verifier) verifySignature(ctx context.Context, h header, signingInput, signature []byte) error {
+	hash, ok := algorithmHash(h.Alg)
+	if !ok {
+		return fmt.Errorf("%w: unsupported algorithm %q", entity.ErrInvalidToken, h.Alg)
+	}
+
+	keys := v.keys.lookup(ctx, h.Kid)
+	if len(keys) == 0 {
+		return fmt.Errorf("%w: unknown key id %q", entity.ErrInvalidToken, h.Kid)
+	}
+	for _, key := range keys {
+		if key.alg != "" && key.alg != h.Alg {
+			continue
+		}
+		if verify(h.Alg, hash, key.key, signingInput, signature) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: signature verification failed", entity.ErrInvalidToken)
+}
+
+// algorithmHash は署名アルゴリズムのハッシュ関数を返します（EdDSA はハッシュを使わないため 0）
+func algorithmHash(alg string) (crypto.Hash, bool) {
+	switch alg {
+	case "RS256", "PS256", "ES256":
+		return crypto.SHA256, true
+	case "RS384", "PS384", "ES384":
+		return crypto.SHA384, true
+	case "RS512", "PS512", "ES512":
+		return crypto.SHA512, true
+	case "EdDSA":
+		return 0, true
+	default:
+		return 0, false
+	}
+}
+
+// verify は公開鍵の種類が alg に一致し、署名が正しい場合に true を返します
+func verify(alg string, hash crypto.Hash, key crypto.PublicKey, signingInput, signature []byte) bool {
+	if alg == "EdDSA" {
+		pub, ok := key.(ed25519.PublicKey)
+		return ok && ed25519.Verify(pub, signingInput, signature)
+	}
+
+	h := hash.New()
+	h.Write(signingInput)
+	digest := h.Sum(nil)
+
+	switch alg[:2] {
+	case "RS":
+		pub, ok := key.(*rsa.PublicKey)
+		return ok && rsa.VerifyPKCS1v15(pub, hash, digest, signature) == nil
+	case "PS":
+		pub, ok := key.(*rsa.PublicKey)
+		return ok && rsa.VerifyPSS(pub, hash, digest, signature, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash}) == nil
+	case "ES":
+		pub, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return false
+		}
+		// 曲線が alg に一致する鍵のみ使う
+		bits := pub.Curve.Params().BitSize
+		size := (bits + 7) / 8
+		if bits != ecdsaCurveBits[alg] || len(signature) != 2*size {
+			return false
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		return ecdsa.Verify(pub, digest, r, s)
+	default:
+		return false
+	}
+}
+
+// validateClaims は exp（必須）・nbf・iss・aud クレームを検証します
+func (v *Verifier) validateClaims(claims map[string]any) error {
+	now := v.now()
+
+	exp, ok := numericDate(claims, "exp")
+	if !ok {
+		return fmt.Errorf("%w: exp claim is required", entity.ErrInvalidToken)
+	}
+	if now.After(exp.Add(clockSkew)) {
+		return fmt.Errorf("%w: token expired at %s", entity.ErrInvalidToken, exp.Format(time.RFC3339))
+	}
+	if _, exists := claims["nbf"]; exists {
+		nbf, ok := numericDate(claims, "nbf")
+		if !ok {
+			return fmt.Errorf("%w: invalid nbf claim", entity.ErrInvalidToken)
+		}
+		if now.Add(clockSkew).Before(nbf) {
+			return fmt.Errorf("%w: token is not valid before %s", entity.ErrInvalidToken, nbf.Format(time.RFC3339))
+		}
+	}
+
+	if v.issuer != "" {
+		if iss, _ := claims["iss"].(string); iss != v.issuer {
+			return fmt.Errorf("%w: unexpected issuer %q", entity.ErrInvalidToken, iss)
+		}
+	}
+	if v.audience != "" {
+		// aud は文字列または文字列の配列（RFC 7519 §4.1.3）
+		var audiences []string
+		switch aud := claims["aud"].(type) {
+		case string:
+			audiences = []string{aud}
+		case []any:
+			audiences, _ = entity.ClaimStrings(claims, "aud")
+		}
+		if !slices.Contains(audiences, v.audience) {
+			return fmt.Errorf("%w: token is not intended for audience %q", entity.ErrInvalidToken, v.audience)
+		}
+	}
+	return nil
+}
+
+// numericDate は NumericDate（1970-01-01T00:00:00Z からの秒数）のクレームを時刻に変換します
+func numericDate(claims map[string]any, name string) (time.Time, bool) {
+	seconds, ok := claims[name].(float64)
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(seconds), 0), true
+}
+
+func decodeSegment(segment string, v any) error {
+	b, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
@@ -0,0 +1,366 @@
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/takumi3488/cookiejar-server/internal/domain/entity"
+)
+
+// testKey はテスト用の署名鍵と、その公開鍵の JWK です
+type testKey struct {
+	kid     string
+	alg     string
+	private crypto.Signer
+}
+
+func newTestKey(t *testing.T, kid, alg string) *testKey {
+	t.Helper()
+	var (
+		private crypto.Signer
+		err     error
+	)
+	switch alg {
+	case "RS256", "PS256":
+		private, err = rsa.GenerateKey(rand.Reader, 2048)
+	case "ES256":
+		private, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case "ES384":
+		private, err = ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	case "EdDSA":
+		_, private, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		t.Fatalf("unsupported algorithm %q", alg)
+	}
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	return &testKey{kid: kid, alg: alg, private: private}
+}
+
+// jwk は公開鍵の JWK を返します
+func (k *testKey) jwk() map[string]string {
+	b64 := base64.RawURLEncoding.EncodeToString
+	jwk := map[string]string{"kid": k.kid, "use": "sig"}
+	switch pub := k.private.Public().(type) {
+	case *rsa.PublicKey:
+		jwk["kty"] = "RSA"
+		jwk["n"] = b64(pub.N.Bytes())
+		jwk["e"] = b64(big.NewInt(int64(pub.E)).Bytes())
+	case *ecdsa.PublicKey:
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		jwk["kty"] = "EC"
+		jwk["crv"] = pub.Curve.Params().Name
+		jwk["x"] = b64(pub.X.FillBytes(make([]byte, size)))
+		jwk["y"] = b64(pub.Y.FillBytes(make([]byte, size)))
+	case ed25519.PublicKey:
+		jwk["kty"] = "OKP"
+		jwk["crv"] = "Ed25519"
+		jwk["x"] = b64(pub)
+	}
+	return jwk
+}
+
+// sign は claims を署名した JWT を返します
+func (k *testKey) sign(t *testing.T, claims map[string]any) string {
+	t.Helper()
+	return signToken(t, k, map[string]any{"alg": k.alg, "kid": k.kid, "typ": "JWT"}, claims)
+}
+
+func signToken(t *testing.T, k *testKey, header, claims map[string]any) string {
+	t.Helper()
+	encode := func(v any) string {
+		b, err := json.Marshal(v)
+		if err != nil {
+			t.Fatalf("Marshal() error = %v", err)
+		}
+		return base64.RawURLEncoding.EncodeToString(b)
+	}
+	signingInput := encode(header) + "." + encode(claims)
+
+	var (
+		signature []byte
+		err       error
+	)
+	switch k.alg {
+	case "EdDSA":
+		signature = ed25519.Sign(k.private.(ed25519.PrivateKey), []byte(signingInput))
+	default:
+		hash := crypto.SHA256
+		if k.alg == "ES384" {
+			hash = crypto.SHA384
+		}
+		h := hash.New()
+		h.Write([]byte(signingInput))
+		digest := h.Sum(nil)
+		switch private := k.private.(type) {
+		case *rsa.PrivateKey:
+			if k.alg == "PS256" {
+				signature, err = rsa.SignPSS(rand.Reader, private, hash, digest, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+			} else {
+				signature, err = rsa.SignPKCS1v15(rand.Reader, private, hash, digest)
+			}
+		case *ecdsa.PrivateKey:
+			var r, s *big.Int
+			r, s, err = ecdsa.Sign(rand.Reader, private, digest)
+			size := (private.Curve.Params().BitSize + 7) / 8
+			signature = append(r.FillBytes(make([]byte, size)), s.FillBytes(make([]byte, size))...)
+		}
+	}
+	if err != nil {
+		t.Fatalf("sign error = %v", err)
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+// writeJWKS は鍵の JWKS を file に書き込みます
+func writeJWKS(t *testing.T, file string, keys ...*testKey) {
+	t.Helper()
+	if err := os.WriteFile(file, jwksJSON(t, keys...), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+}
+
+func jwksJSON(t *testing.T, keys ...*testKey) []byte {
+	t.Helper()
+	jwks := struct {
+		Keys []map[string]string `json:"keys"`
+	}{}
+	for _, key := range keys {
+		jwks.Keys = append(jwks.Keys, key.jwk())
+	}
+	b, err := json.Marshal(jwks)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	return b
+}
+
+func TestVerifier_Verify(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	rsaKey := newTestKey(t, "rsa", "RS256")
+	psKey := newTestKey(t, "ps", "PS256")
+	ecKey := newTestKey(t, "ec", "ES256")
+	edKey := newTestKey(t, "ed", "EdDSA")
+	otherKey := newTestKey(t, "ec", "ES256")
+	p384Key := newTestKey(t, "p384", "ES384")
+
+	file := filepath.Join(t.TempDir(), "jwks.json")
+	writeJWKS(t, file, rsaKey, psKey, ecKey, edKey, p384Key)
+	keySet, err := NewKeySet(context.Background(), file)
+	if err != nil {
+		t.Fatalf("NewKeySet() error = %v", err)
+	}
+	verifier := NewVerifier(keySet, "https://issuer.example.com", "cookiejar")
+	verifier.now = func() time.Time { return now }
+
+	claims := func(overrides map[string]any) map[string]any {
+		c := map[string]any{
+			"iss": "https://issuer.example.com",
+			"aud": "cookiejar",
+			"sub": "bot-1",
+			"exp": now.Add(time.Hour).Unix(),
+		}
+		for k, v := range overrides {
+			if v == nil {
+				delete(c, k)
+				continue
+			}
+			c[k] = v
+		}
+		return c
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{name: "RS256", token: rsaKey.sign(t, claims(nil))},
+		{name: "PS256", token: psKey.sign(t, claims(nil))},
+		{name: "ES256", token: ecKey.sign(t, claims(nil))},
+		{name: "ES384", token: p384Key.sign(t, claims(nil))},
+		{name: "EdDSA", token: edKey.sign(t, claims(nil))},
+		{name: "aud が配列", token: ecKey.sign(t, claims(map[string]any{"aud": []string{"other", "cookiejar"}}))},
+		{name: "許容する時刻のずれの範囲内で期限切れ", token: ecKey.sign(t, claims(map[string]any{"exp": now.Add(-10 * time.Second).Unix()}))},
+		{name: "有効期限切れ", token: ecKey.sign(t, claims(map[string]any{"exp": now.Add(-time.Minute).Unix()})), wantErr: true},
+		{name: "exp なし", token: ecKey.sign(t, claims(map[string]any{"exp": nil})), wantErr: true},
+		{name: "nbf より前", token: ecKey.sign(t, claims(map[string]any{"nbf": now.Add(time.Minute).Unix()})), wantErr: true},
+		{name: "nbf より後", token: ecKey.sign(t, claims(map[string]any{"nbf": now.Add(-time.Minute).Unix()}))},
+		{name: "発行者が異なる", token: ecKey.sign(t, claims(map[string]any{"iss": "https://evil.example.com"})), wantErr: true},
+		{name: "対象者が異なる", token: ecKey.sign(t, claims(map[string]any{"aud": "other"})), wantErr: true},
+		{name: "JWKS にない鍵で署名", token: otherKey.sign(t, claims(nil)), wantErr: true},
+		{name: "未知の kid", token: signToken(t, ecKey, map[string]any{"alg": "ES256", "kid": "unknown"}, claims(nil)), wantErr: true},
+		{name: "kid なし", token: signToken(t, ecKey, map[string]any{"alg": "ES256"}, claims(nil))},
+		{name: "鍵の種類と異なるアルゴリズム", token: signToken(t, ecKey, map[string]any{"alg": "RS256", "kid": "ec"}, claims(nil)), wantErr: true},
+		{name: "alg none", token: "eyJhbGciOiJub25lIn0." + base64.RawURLEncoding.EncodeToString([]byte(`{"exp":9999999999}`)) + ".", wantErr: true},
+		{name: "crit ヘッダー", token: signToken(t, ecKey, map[string]any{"alg": "ES256", "kid": "ec", "crit": []string{"exp"}}, claims(nil)), wantErr: true},
+		{name: "形式が不正", token: "not-a-jwt", wantErr: true},
+		{
+			name: "ペイロードの改ざん",
+			token: func() string {
+				parts := strings.Split(ecKey.sign(t, claims(nil)), ".")
+				payload, _ := json.Marshal(claims(map[string]any{"sub": "admin"}))
+				return parts[0] + "." + base64.RawURLEncoding.EncodeToString(payload) + "." + parts[2]
+			}(),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := verifier.Verify(context.Background(), tt.token)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if !errors.Is(err, entity.ErrInvalidToken) {
+					t.Errorf("Verify() error = %v, want %v", err, entity.ErrInvalidToken)
+				}
+				return
+			}
+			if got["sub"] != "bot-1" {
+				t.Errorf("Verify() sub = %v, want bot-1", got["sub"])
+			}
+		})
+	}
+}
+
+func TestKeySet_URL(t *testing.T) {
+	oldKey := newTestKey(t, "2026-09", "ES256")
+	newKey := newTestKey(t, "2026-10", "ES256")
+
+	var (
+		jwks     atomic.Value
+		requests atomic.Int32
+	)
+	jwks.Store(jwksJSON(t, oldKey))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/jwk-set+json")
+		_, _ = w.Write(jwks.Load().([]byte))
+	}))
+	defer server.Close()
+
+	keySet, err := NewKeySet(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("NewKeySet() error = %v", err)
+	}
+	now := time.Now()
+	keySet.now = func() time.Time { return now }
+	verifier := NewVerifier(keySet, "", "")
+	claims := map[string]any{"sub": "bot-1", "exp": now.Add(time.Hour).Unix()}
+
+	if _, err := verifier.Verify(context.Background(), oldKey.sign(t, claims)); err != nil {
+		t.Fatalf("Verify(old key) error = %v", err)
+	}
+
+	// 読み込んだ直後は Refresh しても取得しない
+	if err := keySet.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("requests = %v, want 1", got)
+	}
+
+	// 鍵がローテーションされた直後は、未知の kid でも読み込み直さない
+	jwks.Store(jwksJSON(t, oldKey, newKey))
+	if _, err := verifier.Verify(context.Background(), newKey.sign(t, claims)); err == nil {
+		t.Error("Verify(new key) error = nil, want error within min refresh interval")
+	}
+
+	// 最短の間隔が過ぎた後は、未知の kid のトークンで JWKS を読み込み直す
+	now = now.Add(minRefreshInterval)
+	if _, err := verifier.Verify(context.Background(), newKey.sign(t, claims)); err != nil {
+		t.Errorf("Verify(new key) error = %v", err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("requests = %v, want 2", got)
+	}
+
+	// 読み込みに失敗した場合は以前の鍵を使い続ける
+	now = now.Add(minRefreshInterval)
+	jwks.Store([]byte(`{"keys": []}`))
+	if err := keySet.Refresh(context.Background()); err == nil {
+		t.Error("Refresh() error = nil, want error for empty JWKS")
+	}
+	if _, err := verifier.Verify(context.Background(), newKey.sign(t, claims)); err != nil {
+		t.Errorf("Verify(new key) after failed refresh error = %v", err)
+	}
+}
+
+func TestParseJWKS(t *testing.T) {
+	ecKey := newTestKey(t, "ec", "ES256")
+	valid := ecKey.jwk()
+
+	tests := []struct {
+		name     string
+		keys     []map[string]string
+		wantKeys int
+		wantErr  bool
+	}{
+		{
+			name:     "署名用の鍵",
+			keys:     []map[string]string{valid},
+			wantKeys: 1,
+		},
+		{
+			name:     "暗号化用の鍵は無視する",
+			keys:     []map[string]string{valid, {"kty": "RSA", "kid": "enc", "use": "enc", "n": "AQAB", "e": "AQAB"}},
+			wantKeys: 1,
+		},
+		{
+			name:     "対称鍵は無視する",
+			keys:     []map[string]string{valid, {"kty": "oct", "kid": "hmac", "k": "c2VjcmV0"}},
+			wantKeys: 1,
+		},
+		{
+			name:     "短い RSA 鍵は無視する",
+			keys:     []map[string]string{valid, {"kty": "RSA", "kid": "short", "n": base64.RawURLEncoding.EncodeToString(make([]byte, 128)), "e": "AQAB"}},
+			wantKeys: 1,
+		},
+		{
+			name:     "曲線上にない点は無視する",
+			keys:     []map[string]string{valid, {"kty": "EC", "kid": "bad", "crv": "P-256", "x": valid["x"], "y": valid["x"]}},
+			wantKeys: 1,
+		},
+		{
+			name:    "署名に使える鍵がない",
+			keys:    []map[string]string{{"kty": "oct", "kid": "hmac", "k": "c2VjcmV0"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(map[string]any{"keys": tt.keys})
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+			keys, err := parseJWKS(data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseJWKS() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(keys) != tt.wantKeys {
+				t.Errorf("parseJWKS() = %d keys, want %d", len(keys), tt.wantKeys)
+			}
+		})
+	}
+}
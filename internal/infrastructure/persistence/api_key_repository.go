@@ -0,0 +1,93 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/takumi3488/cookiejar-server/db"
+	"github.com/takumi3488/cookiejar-server/internal/domain/entity"
+	"github.com/takumi3488/cookiejar-server/internal/domain/repository"
+)
+
+type apiKeyRepository struct {
+	queries *db.Queries
+}
+
+func NewAPIKeyRepository(queries *db.Queries) repository.APIKeyRepository {
+	return &apiKeyRepository{
+		queries: queries,
+	}
+}
+
+func (r *apiKeyRepository) Create(ctx context.Context, name, prefix, keyHash string, scopes []entity.Scope, createdAt time.Time) (*entity.APIKey, error) {
+	row, err := r.queries.CreateAPIKey(ctx, db.CreateAPIKeyParams{
+		Name:      name,
+		Prefix:    prefix,
+		KeyHash:   keyHash,
+		CreatedAt: createdAt,
+		Scopes:    entity.ScopeStrings(scopes),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toAPIKeyEntity(row)
+}
+
+func (r *apiKeyRepository) FindByHash(ctx context.Context, keyHash string) (*entity.APIKey, error) {
+	row, err := r.queries.GetAPIKeyByHash(ctx, keyHash)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return toAPIKeyEntity(row)
+}
+
+func (r *apiKeyRepository) FindAll(ctx context.Context) ([]*entity.APIKey, error) {
+	rows, err := r.queries.ListAPIKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*entity.APIKey, 0, len(rows))
+	for _, row := range rows {
+		apiKey, err := toAPIKeyEntity(row)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, apiKey)
+	}
+	return result, nil
+}
+
+func (r *apiKeyRepository) Revoke(ctx context.Context, id int64, revokedAt time.Time) (int, error) {
+	revoked, err := r.queries.RevokeAPIKey(ctx, db.RevokeAPIKeyParams{
+		RevokedAt: sql.NullTime{Time: revokedAt, Valid: true},
+		ID:        id,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return int(revoked), nil
+}
+
+func toAPIKeyEntity(row db.ApiKey) (*entity.APIKey, error) {
+	scopes, err := entity.ParseScopes(row.Scopes)
+	if err != nil {
+		return nil, fmt.Errorf("api key %d: %w", row.ID, err)
+	}
+	apiKey := &entity.APIKey{
+		ID:        row.ID,
+		Name:      row.Name,
+		Prefix:    row.Prefix,
+		CreatedAt: row.CreatedAt,
+		Scopes:    scopes,
+	}
+	if row.RevokedAt.Valid {
+		apiKey.RevokedAt = row.RevokedAt.Time
+	}
+	return apiKey, nil
+}
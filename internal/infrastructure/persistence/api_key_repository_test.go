@@ -0,0 +1,61 @@
+package persistence
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/takumi3488/cookiejar-server/db"
+	"github.com/takumi3488/cookiejar-server/internal/domain/entity"
+)
+
+func TestAPIKeyRepository(t *testing.T) {
+	dbConn := openTestDB(t)
+	repo := NewAPIKeyRepository(db.New(dbConn))
+	ctx := context.Background()
+	// PostgreSQL の TIMESTAMPTZ はマイクロ秒精度
+	now := time.Now().Truncate(time.Microsecond)
+
+	scopes := []entity.Scope{{Permission: entity.PermissionRead, HostPattern: "*.example.com"}}
+	created, err := repo.Create(ctx, "ci", "cjk_abcdefgh", "hash", scopes, now)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if created.ID == 0 || created.Name != "ci" || created.Prefix != "cjk_abcdefgh" || !created.CreatedAt.Equal(now) {
+		t.Errorf("Create() = %+v", created)
+	}
+
+	found, err := repo.FindByHash(ctx, "hash")
+	if err != nil {
+		t.Fatalf("FindByHash() error = %v", err)
+	}
+	if found == nil || found.ID != created.ID || found.IsRevoked() {
+		t.Fatalf("FindByHash() = %+v, want active key %d", found, created.ID)
+	}
+	if len(found.Scopes) != 1 || found.Scopes[0] != scopes[0] {
+		t.Errorf("FindByHash() Scopes = %v, want %v", found.Scopes, scopes)
+	}
+	if missing, err := repo.FindByHash(ctx, "missing"); err != nil || missing != nil {
+		t.Errorf("FindByHash(missing) = %+v, %v, want nil, nil", missing, err)
+	}
+
+	revoked, err := repo.Revoke(ctx, created.ID, now.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("Revoke() error = %v", err)
+	}
+	if revoked != 1 {
+		t.Errorf("Revoke() = %v, want 1", revoked)
+	}
+	// 無効化済みのキーは再度無効化しない
+	if revoked, err := repo.Revoke(ctx, created.ID, now.Add(time.Hour)); err != nil || revoked != 0 {
+		t.Errorf("Revoke() = %v, %v, want 0", revoked, err)
+	}
+
+	apiKeys, err := repo.FindAll(ctx)
+	if err != nil {
+		t.Fatalf("FindAll() error = %v", err)
+	}
+	if len(apiKeys) != 1 || !apiKeys[0].RevokedAt.Equal(now.Add(time.Minute)) {
+		t.Errorf("FindAll() = %+v, want 1 key revoked at %v", apiKeys, now.Add(time.Minute))
+	}
+}
@@ -2,7 +2,10 @@ package persistence
 
 import (
 	"context"
-	"encoding/json"
+	"database/sql"
+	"net/http"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/takumi3488/cookiejar-server/db"
@@ -11,128 +14,355 @@ import (
 )
 
 type cookieRepository struct {
+	db      *sql.DB
 	queries *db.Queries
+	// WithinTx の中で生成されたリポジトリの場合のみ設定される
+	tx *sql.Tx
 }
 
-func NewCookieRepository(queries *db.Queries) repository.CookieRepository {
+func NewCookieRepository(dbConn *sql.DB, queries *db.Queries) repository.CookieRepository {
 	return &cookieRepository{
+		db:      dbConn,
 		queries: queries,
 	}
 }
 
-func (r *cookieRepository) Upsert(ctx context.Context, cookie *entity.Cookie, updatedAt time.Time) error {
-	// 既存のCookieを取得
-	existingCookies, err := r.FindByHost(ctx, cookie.Domain)
+func (r *cookieRepository) Upsert(ctx context.Context, jar string, cookie *entity.Cookie, updatedAt time.Time) error {
+	return translateJarError(upsertCookie(ctx, r.queries, jar, cookie, updatedAt), jar)
+}
+
+func (r *cookieRepository) UpsertMany(ctx context.Context, jar string, cookies []*entity.Cookie, updatedAt time.Time) error {
+	return r.WithinTx(ctx, func(repo repository.CookieRepository) error {
+		for _, cookie := range upsertOrder(cookies) {
+			if err := repo.Upsert(ctx, jar, cookie, updatedAt); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *cookieRepository) FindAll(ctx context.Context, jar string) ([]*entity.Cookie, error) {
+	rows, err := r.queries.ListCookies(ctx, jar)
+	if err != nil {
+		return nil, err
+	}
+	return toEntities(rows), nil
+}
+
+func (r *cookieRepository) FindByHost(ctx context.Context, jar, host string) ([]*entity.Cookie, error) {
+	rows, err := r.queries.ListCookiesByDomain(ctx, db.ListCookiesByDomainParams{
+		Jar:    jar,
+		Domain: canonicalDomain(host),
+	})
 	if err != nil {
-		// レコードが存在しない場合は空配列として扱う
-		existingCookies = []*entity.Cookie{}
+		return nil, err
 	}
+	return toEntities(rows), nil
+}
 
-	// 同じ名前のCookieを探してマージ（更新）、なければ追加
-	found := false
-	for i, existing := range existingCookies {
-		if existing.Name == cookie.Name {
-			existingCookies[i] = cookie
-			found = true
-			break
+func (r *cookieRepository) FindByDomainMatch(ctx context.Context, jar, host string) ([]*entity.Cookie, error) {
+	// ホストに domain-match し得るすべての Domain のCookieを取得
+	rows, err := r.queries.ListCookiesByDomains(ctx, db.ListCookiesByDomainsParams{
+		Jar:     jar,
+		Domains: entity.DomainCandidates(host),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// host-only Cookie は親ドメインから取得された場合に除外する
+	result := make([]*entity.Cookie, 0, len(rows))
+	for _, cookie := range toEntities(rows) {
+		if cookie.MatchesHost(host) {
+			result = append(result, cookie)
 		}
 	}
-	if !found {
-		existingCookies = append(existingCookies, cookie)
+	return result, nil
+}
+
+func (r *cookieRepository) Touch(ctx context.Context, jar string, keys []entity.CookieKey, accessedAt time.Time) (int, error) {
+	if len(keys) == 0 {
+		return 0, nil
+	}
+	params := db.TouchCookiesParams{
+		AccessedAt:    accessedAt,
+		Names:         make([]string, len(keys)),
+		Domains:       make([]string, len(keys)),
+		HostOnlys:     make([]bool, len(keys)),
+		Paths:         make([]string, len(keys)),
+		PartitionKeys: make([]string, len(keys)),
+		Jar:           jar,
+	}
+	for i, key := range keys {
+		params.Names[i] = key.Name
+		params.Domains[i] = key.Domain
+		params.HostOnlys[i] = key.HostOnly
+		params.Paths[i] = key.Path
+		params.PartitionKeys[i] = key.PartitionKey
 	}
+	touched, err := r.queries.TouchCookies(ctx, params)
+	if err != nil {
+		return 0, err
+	}
+	return int(touched), nil
+}
 
-	// Cookie配列をJSON化
-	cookiesJSON, err := json.Marshal(existingCookies)
+func (r *cookieRepository) Delete(ctx context.Context, jar string, key entity.CookieKey) (int, error) {
+	deleted, err := r.queries.DeleteCookie(ctx, db.DeleteCookieParams{
+		Jar:          jar,
+		Name:         key.Name,
+		Domain:       key.Domain,
+		HostOnly:     key.HostOnly,
+		Path:         key.Path,
+		PartitionKey: key.PartitionKey,
+	})
 	if err != nil {
-		return err
+		return 0, err
 	}
+	return int(deleted), nil
+}
 
-	return r.queries.UpsertCookies(ctx, db.UpsertCookiesParams{
-		Host:      cookie.Domain,
-		Cookies:   string(cookiesJSON),
-		UpdatedAt: updatedAt,
+func (r *cookieRepository) DeleteByHost(ctx context.Context, jar, host string) (int, error) {
+	deleted, err := r.queries.DeleteCookiesByDomain(ctx, db.DeleteCookiesByDomainParams{
+		Jar:    jar,
+		Domain: canonicalDomain(host),
 	})
+	if err != nil {
+		return 0, err
+	}
+	return int(deleted), nil
 }
 
-func (r *cookieRepository) UpsertMany(ctx context.Context, host string, cookies []*entity.Cookie, updatedAt time.Time) error {
-	// 既存のCookieを取得
-	existingCookies, err := r.FindByHost(ctx, host)
+func (r *cookieRepository) DeleteByFilter(ctx context.Context, jar string, filter repository.CookieFilter, now time.Time) (int, error) {
+	deleted, err := r.queries.DeleteCookiesByFilter(ctx, db.DeleteCookiesByFilterParams{
+		Jar:              jar,
+		Domain:           toDomainParam(filter.Domain),
+		Site:             toDomainParam(filter.Site),
+		SubdomainPattern: toSubdomainPattern(filter.Site),
+		NamePrefix:       sql.NullString{String: filter.NamePrefix, Valid: filter.NamePrefix != ""},
+		ExpiredOnly:      filter.ExpiredOnly,
+		Now:              now,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return int(deleted), nil
+}
+
+func (r *cookieRepository) Count(ctx context.Context, jar, site string) (int, error) {
+	count, err := r.queries.CountCookies(ctx, db.CountCookiesParams{
+		Jar:              jar,
+		Site:             toDomainParam(site),
+		SubdomainPattern: toSubdomainPattern(site),
+	})
 	if err != nil {
-		// レコードが存在しない場合は空配列として扱う
-		existingCookies = []*entity.Cookie{}
+		return 0, err
 	}
+	return int(count), nil
+}
 
-	// 既存のCookieをマップ化（名前をキーに）
-	existingMap := make(map[string]*entity.Cookie)
-	for _, existing := range existingCookies {
-		existingMap[existing.Name] = existing
+func (r *cookieRepository) EvictLeastRecentlyUsed(ctx context.Context, jar, site string, n int) (int, error) {
+	if n <= 0 {
+		return 0, nil
+	}
+	evicted, err := r.queries.EvictLeastRecentlyUsedCookies(ctx, db.EvictLeastRecentlyUsedCookiesParams{
+		Jar:              jar,
+		Site:             toDomainParam(site),
+		SubdomainPattern: toSubdomainPattern(site),
+		Count:            int32(n),
+	})
+	if err != nil {
+		return 0, err
 	}
+	return int(evicted), nil
+}
 
-	// 新しいCookieでマップを更新
-	for _, cookie := range cookies {
-		existingMap[cookie.Name] = cookie
+func (r *cookieRepository) PurgeExpired(ctx context.Context, now time.Time, batchSize int) (int, error) {
+	purged, err := r.queries.DeleteExpiredCookies(ctx, db.DeleteExpiredCookiesParams{
+		Now:       now,
+		BatchSize: int32(batchSize),
+	})
+	if err != nil {
+		return 0, err
 	}
+	return int(purged), nil
+}
 
-	// マップから配列を再構築
-	mergedCookies := make([]*entity.Cookie, 0, len(existingMap))
-	for _, cookie := range existingMap {
-		mergedCookies = append(mergedCookies, cookie)
+func (r *cookieRepository) WithinTx(ctx context.Context, fn func(repo repository.CookieRepository) error) error {
+	// 外側のトランザクションに参加する
+	if r.tx != nil {
+		return fn(r)
 	}
 
-	// Cookie配列をJSON化
-	cookiesJSON, err := json.Marshal(mergedCookies)
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
+	defer func() {
+		// Commit 済みの場合は sql.ErrTxDone となるため無視する
+		_ = tx.Rollback()
+	}()
+
+	if err := fn(&cookieRepository{
+		db:      r.db,
+		queries: r.queries.WithTx(tx),
+		tx:      tx,
+	}); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// upsertCookie は INSERT ... ON CONFLICT で1つのCookieを原子的に追加・更新します
+func upsertCookie(ctx context.Context, q *db.Queries, jar string, cookie *entity.Cookie, updatedAt time.Time) error {
+	// 失効したCookie（MaxAge < 0 による削除を含む）は同じ識別キーの既存Cookieを削除する
+	if cookie.IsExpired(updatedAt) {
+		key := cookie.Key()
+		_, err := q.DeleteCookie(ctx, db.DeleteCookieParams{
+			Jar:          jar,
+			Name:         key.Name,
+			Domain:       key.Domain,
+			HostOnly:     key.HostOnly,
+			Path:         key.Path,
+			PartitionKey: key.PartitionKey,
+		})
+		return err
+	}
 
-	return r.queries.UpsertCookies(ctx, db.UpsertCookiesParams{
-		Host:      host,
-		Cookies:   string(cookiesJSON),
-		UpdatedAt: updatedAt,
+	// 同じ識別キー（名前・ドメイン・host-only・パス・パーティションキー）のCookieを置き換え、なければ追加
+	return q.UpsertCookie(ctx, toUpsertCookieParams(jar, cookie, updatedAt))
+}
+
+// canonicalDomain は domain カラムと比較できるよう、先頭のドットを取り除いてドメインを正規化します
+func canonicalDomain(domain string) string {
+	return entity.CanonicalizeHost(strings.TrimPrefix(domain, "."))
+}
+
+// toDomainParam はドメインを正規化し、空の場合は条件なし（NULL）として返します
+func toDomainParam(domain string) sql.NullString {
+	domain = canonicalDomain(domain)
+	return sql.NullString{String: domain, Valid: domain != ""}
+}
+
+// likeEscaper は LIKE パターンのメタ文字をエスケープします
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// toSubdomainPattern は site のサブドメインに一致する、reverse(domain) に対する前方一致の LIKE パターンに変換します（site が空の場合は NULL）
+// 末尾一致を逆順の前方一致にすることで、cookie_jar_reversed_domain_idx を使って検索できる
+func toSubdomainPattern(site string) sql.NullString {
+	site = canonicalDomain(site)
+	if site == "" {
+		return sql.NullString{}
+	}
+	reversed := []rune("." + site)
+	slices.Reverse(reversed)
+	return sql.NullString{String: likeEscaper.Replace(string(reversed)) + "%", Valid: true}
+}
+
+// upsertOrder は同一リクエスト内の重複を後勝ちでまとめ、識別キー順に並べたCookieを返します
+// 並行するトランザクション間で行ロックの取得順序を揃え、デッドロックを防ぐ
+func upsertOrder(cookies []*entity.Cookie) []*entity.Cookie {
+	merged := entity.MergeCookies(nil, cookies)
+	slices.SortFunc(merged, func(a, b *entity.Cookie) int {
+		return compareKeys(a.Key(), b.Key())
 	})
+	return merged
 }
 
-func (r *cookieRepository) FindAll(ctx context.Context) ([]*entity.Cookie, error) {
-	cookies, err := r.queries.ListCookies(ctx)
-	if err != nil {
-		return nil, err
+// compareKeys は識別キーの順序を比較します（ロック取得順序の決定に使用）
+func compareKeys(a, b entity.CookieKey) int {
+	if c := strings.Compare(a.Domain, b.Domain); c != 0 {
+		return c
+	}
+	if c := strings.Compare(a.Path, b.Path); c != 0 {
+		return c
+	}
+	if c := strings.Compare(a.Name, b.Name); c != 0 {
+		return c
 	}
+	if c := strings.Compare(a.PartitionKey, b.PartitionKey); c != 0 {
+		return c
+	}
+	switch {
+	case a.HostOnly == b.HostOnly:
+		return 0
+	case a.HostOnly:
+		return 1
+	}
+	return -1
+}
 
-	result := make([]*entity.Cookie, 0)
-	for _, c := range cookies {
-		var cookieList []*entity.Cookie
-		if err := json.Unmarshal([]byte(c.Cookies), &cookieList); err != nil {
-			// 配列としてのアンマーシャルが失敗した場合、単一のCookieとして試す（後方互換性）
-			var cookie entity.Cookie
-			if err := json.Unmarshal([]byte(c.Cookies), &cookie); err != nil {
-				// アンマーシャルが失敗した場合、このCookieをスキップ
-				continue
-			}
-			result = append(result, &cookie)
-		} else {
-			result = append(result, cookieList...)
-		}
+func toUpsertCookieParams(jar string, cookie *entity.Cookie, updatedAt time.Time) db.UpsertCookieParams {
+	key := cookie.Key()
+	return db.UpsertCookieParams{
+		Jar:          jar,
+		Name:         key.Name,
+		Value:        cookie.Value,
+		Domain:       key.Domain,
+		HostOnly:     key.HostOnly,
+		Path:         key.Path,
+		ExpiresAt:    sql.NullTime{Time: cookie.Expires, Valid: !cookie.Expires.IsZero()},
+		Secure:       cookie.Secure,
+		HttpOnly:     cookie.HttpOnly,
+		SameSite:     sameSiteToColumn(cookie.SameSite),
+		Partitioned:  cookie.Partitioned,
+		PartitionKey: key.PartitionKey,
+		UpdatedAt:    updatedAt,
 	}
-	return result, nil
 }
 
-func (r *cookieRepository) FindByHost(ctx context.Context, host string) ([]*entity.Cookie, error) {
-	c, err := r.queries.GetCookiesByHost(ctx, host)
-	if err != nil {
-		return nil, err
+func toEntities(rows []db.Cookie) []*entity.Cookie {
+	result := make([]*entity.Cookie, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, toEntity(row))
 	}
+	return result
+}
 
-	result := make([]*entity.Cookie, 0)
-	var cookieList []*entity.Cookie
-	if err := json.Unmarshal([]byte(c.Cookies), &cookieList); err != nil {
-		// 配列としてのアンマーシャルが失敗した場合、単一のCookieとして試す（後方互換性）
-		var cookie entity.Cookie
-		if err := json.Unmarshal([]byte(c.Cookies), &cookie); err != nil {
-			return nil, err
-		}
-		result = append(result, &cookie)
-	} else {
-		result = append(result, cookieList...)
+func toEntity(row db.Cookie) *entity.Cookie {
+	cookie := &entity.Cookie{
+		Name:           row.Name,
+		Value:          row.Value,
+		Domain:         row.Domain,
+		Path:           row.Path,
+		Secure:         row.Secure,
+		HttpOnly:       row.HttpOnly,
+		SameSite:       sameSiteFromColumn(row.SameSite),
+		HostOnly:       row.HostOnly,
+		Partitioned:    row.Partitioned,
+		PartitionKey:   row.PartitionKey,
+		CreatedAt:      row.CreatedAt,
+		LastAccessedAt: row.LastAccessedAt,
 	}
-	return result, nil
+	if row.ExpiresAt.Valid {
+		cookie.Expires = row.ExpiresAt.Time
+	}
+	return cookie
+}
+
+// sameSiteToColumn は SameSite を same_site カラムの値に変換します（属性値なしの SameSite は未設定として扱う）
+func sameSiteToColumn(sameSite http.SameSite) string {
+	switch sameSite {
+	case http.SameSiteLaxMode:
+		return "Lax"
+	case http.SameSiteStrictMode:
+		return "Strict"
+	case http.SameSiteNoneMode:
+		return "None"
+	}
+	return ""
+}
+
+// sameSiteFromColumn は same_site カラムの値を SameSite に変換します
+func sameSiteFromColumn(sameSite string) http.SameSite {
+	switch sameSite {
+	case "Lax":
+		return http.SameSiteLaxMode
+	case "Strict":
+		return http.SameSiteStrictMode
+	case "None":
+		return http.SameSiteNoneMode
+	}
+	return 0
 }
@@ -0,0 +1,370 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	_ "github.com/lib/pq"
+	"github.com/takumi3488/cookiejar-server/db"
+	"github.com/takumi3488/cookiejar-server/internal/domain/entity"
+	"github.com/takumi3488/cookiejar-server/internal/domain/repository"
+	"github.com/takumi3488/cookiejar-server/internal/usecase"
+)
+
+// openTestDB はテスト専用のスキーマに schema.sql を適用したデータベース接続を返します
+// POSTGRES_HOST が設定されていない場合はテストをスキップします
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	if os.Getenv("POSTGRES_HOST") == "" {
+		t.Skip("POSTGRES_HOST is not set")
+	}
+
+	schema, err := os.ReadFile("../../../schema.sql")
+	if err != nil {
+		t.Fatalf("Failed to read schema.sql: %v", err)
+	}
+
+	dsn := fmt.Sprintf(
+		"host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
+		os.Getenv("POSTGRES_HOST"),
+		os.Getenv("POSTGRES_PORT"),
+		os.Getenv("POSTGRES_USER"),
+		os.Getenv("POSTGRES_PASSWORD"),
+		os.Getenv("POSTGRES_DB"),
+	)
+	admin, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() { _ = admin.Close() })
+
+	schemaName := fmt.Sprintf("cookiejar_test_%d", time.Now().UnixNano())
+	if _, err := admin.Exec("CREATE SCHEMA " + schemaName); err != nil {
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+	t.Cleanup(func() {
+		if _, err := admin.Exec("DROP SCHEMA " + schemaName + " CASCADE"); err != nil {
+			t.Errorf("Failed to drop schema: %v", err)
+		}
+	})
+
+	dbConn, err := sql.Open("postgres", dsn+" search_path="+schemaName)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() { _ = dbConn.Close() })
+
+	if _, err := dbConn.Exec(string(schema)); err != nil {
+		t.Fatalf("Failed to apply schema: %v", err)
+	}
+	return dbConn
+}
+
+func TestCookieRepository_UpsertMany_Concurrent(t *testing.T) {
+	dbConn := openTestDB(t)
+	repo := NewCookieRepository(dbConn, db.New(dbConn))
+	uc := usecase.NewCookieUsecase(repo, nil, usecase.DefaultCookieLimits())
+
+	const workers = 50
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	for i := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// 全リクエストで共通のCookieと、リクエストごとに異なるCookieを同じホストへ保存
+			errs <- uc.StoreCookies(context.Background(), "", []*entity.Cookie{
+				{Name: "shared", Value: fmt.Sprintf("value%d", i), Domain: "example.com", Path: "/"},
+				{Name: fmt.Sprintf("cookie%d", i), Value: "value", Domain: "example.com", Path: "/"},
+			})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("StoreCookies() error = %v", err)
+		}
+	}
+
+	cookies, err := repo.FindByHost(context.Background(), entity.DefaultJar, "example.com")
+	if err != nil {
+		t.Fatalf("FindByHost() error = %v", err)
+	}
+	if len(cookies) != workers+1 {
+		t.Fatalf("FindByHost() len = %v, want %v", len(cookies), workers+1)
+	}
+
+	names := make(map[string]bool, len(cookies))
+	for _, cookie := range cookies {
+		names[cookie.Name] = true
+	}
+	for i := range workers {
+		if name := fmt.Sprintf("cookie%d", i); !names[name] {
+			t.Errorf("cookie %s was lost", name)
+		}
+	}
+}
+
+func TestUpsertOrder(t *testing.T) {
+	got := upsertOrder([]*entity.Cookie{
+		{Name: "sid", Value: "1", Domain: ".b.com", Path: "/"},
+		{Name: "sid", Value: "2", Domain: "a.com", Path: "/"},
+		{Name: "sid", Value: "3", Domain: "X.com", Path: "/"},
+		{Name: "lang", Value: "4", Domain: "a.com", Path: "/"},
+		{Name: "sid", Value: "5", Domain: ".x.com", Path: "/"},
+		{Name: "sid", Value: "6", Domain: "b.com", Path: "/", HostOnly: true},
+	})
+
+	// 正規化したドメイン・パス・名前・host-only の順に並び、同じ識別キーは後勝ちでまとめられる
+	want := []struct {
+		domain string
+		name   string
+		value  string
+	}{
+		{domain: "a.com", name: "lang", value: "4"},
+		{domain: "a.com", name: "sid", value: "2"},
+		{domain: "b.com", name: "sid", value: "1"},
+		{domain: "b.com", name: "sid", value: "6"},
+		{domain: "x.com", name: "sid", value: "5"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("upsertOrder() len = %v, want %v", len(got), len(want))
+	}
+	for i, w := range want {
+		if got[i].CanonicalDomain() != w.domain || got[i].Name != w.name || got[i].Value != w.value {
+			t.Errorf("upsertOrder()[%d] = %s %s=%s, want %s %s=%s", i, got[i].Domain, got[i].Name, got[i].Value, w.domain, w.name, w.value)
+		}
+	}
+}
+
+func TestCookieRepository_WithinTx_Rollback(t *testing.T) {
+	dbConn := openTestDB(t)
+	repo := NewCookieRepository(dbConn, db.New(dbConn))
+	ctx := context.Background()
+	now := time.Now()
+
+	wantErr := errors.New("rollback")
+	err := repo.WithinTx(ctx, func(txRepo repository.CookieRepository) error {
+		if err := txRepo.UpsertMany(ctx, entity.DefaultJar, []*entity.Cookie{
+			{Name: "a", Value: "value", Domain: "a.example.com", Path: "/"},
+		}, now); err != nil {
+			return err
+		}
+		if err := txRepo.UpsertMany(ctx, entity.DefaultJar, []*entity.Cookie{
+			{Name: "b", Value: "value", Domain: "b.example.com", Path: "/"},
+		}, now); err != nil {
+			return err
+		}
+		return wantErr
+	})
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("WithinTx() error = %v, want %v", err, wantErr)
+	}
+
+	cookies, err := repo.FindAll(ctx, entity.DefaultJar)
+	if err != nil {
+		t.Fatalf("FindAll() error = %v", err)
+	}
+	if len(cookies) != 0 {
+		t.Errorf("FindAll() len = %v, want 0 (all writes should be rolled back)", len(cookies))
+	}
+}
+
+func TestCookieRepository_EvictLeastRecentlyUsed(t *testing.T) {
+	dbConn := openTestDB(t)
+	repo := NewCookieRepository(dbConn, db.New(dbConn))
+	ctx := context.Background()
+	base := time.Now()
+
+	// old → new の順に設定されたCookie
+	for i, name := range []string{"old", "middle", "new"} {
+		if err := repo.Upsert(ctx, entity.DefaultJar, &entity.Cookie{Name: name, Value: "value", Domain: "example.com", Path: "/"}, base.Add(time.Duration(i)*time.Minute)); err != nil {
+			t.Fatalf("Upsert() error = %v", err)
+		}
+	}
+	// サブドメインの Cookie は登録可能ドメインの件数に含まれ、末尾が一致するだけの別ドメインは含まれない
+	for _, cookie := range []*entity.Cookie{
+		{Name: "sub", Value: "value", Domain: "www.example.com", Path: "/"},
+		{Name: "lookalike", Value: "value", Domain: "notexample.com", Path: "/"},
+		{Name: "other", Value: "value", Domain: "other.com", Path: "/"},
+	} {
+		if err := repo.Upsert(ctx, entity.DefaultJar, cookie, base.Add(time.Hour)); err != nil {
+			t.Fatalf("Upsert() error = %v", err)
+		}
+	}
+
+	count, err := repo.Count(ctx, entity.DefaultJar, ".Example.com")
+	if err != nil {
+		t.Fatalf("Count() error = %v", err)
+	}
+	if count != 4 {
+		t.Fatalf("Count(example.com) = %v, want 4", count)
+	}
+	if total, err := repo.Count(ctx, entity.DefaultJar, ""); err != nil || total != 6 {
+		t.Fatalf("Count() = %v, %v, want 6", total, err)
+	}
+
+	// 最初に設定された old も、その後にアクセスされていれば最後に削除される
+	if _, err := repo.Touch(ctx, entity.DefaultJar, []entity.CookieKey{{Name: "old", Domain: "example.com", Path: "/"}}, base.Add(2*time.Hour)); err != nil {
+		t.Fatalf("Touch() error = %v", err)
+	}
+
+	evicted, err := repo.EvictLeastRecentlyUsed(ctx, entity.DefaultJar, "example.com", 2)
+	if err != nil {
+		t.Fatalf("EvictLeastRecentlyUsed() error = %v", err)
+	}
+	if evicted != 2 {
+		t.Errorf("EvictLeastRecentlyUsed() = %v, want 2", evicted)
+	}
+
+	cookies, err := repo.FindAll(ctx, entity.DefaultJar)
+	if err != nil {
+		t.Fatalf("FindAll() error = %v", err)
+	}
+	names := make(map[string]bool, len(cookies))
+	for _, cookie := range cookies {
+		names[cookie.Name] = true
+	}
+	if len(cookies) != 4 || !names["old"] || !names["sub"] || !names["lookalike"] || !names["other"] {
+		t.Errorf("FindAll() names = %v, want [old sub lookalike other]", names)
+	}
+}
+
+func TestCookieRepository_Timestamps(t *testing.T) {
+	dbConn := openTestDB(t)
+	repo := NewCookieRepository(dbConn, db.New(dbConn))
+	ctx := context.Background()
+	// PostgreSQL の TIMESTAMPTZ はマイクロ秒精度
+	created := time.Now().Truncate(time.Microsecond)
+	updated := created.Add(time.Minute)
+	accessed := created.Add(time.Hour)
+
+	cookie := &entity.Cookie{Name: "sid", Value: "v1", Domain: "example.com", Path: "/"}
+	if err := repo.Upsert(ctx, entity.DefaultJar, cookie, created); err != nil {
+		t.Fatalf("Upsert() error = %v", err)
+	}
+	// 同じ識別キーの Cookie で上書きしても作成時刻は変わらない（RFC 6265 §5.3 step 11）
+	if err := repo.Upsert(ctx, entity.DefaultJar, &entity.Cookie{Name: "sid", Value: "v2", Domain: "example.com", Path: "/"}, updated); err != nil {
+		t.Fatalf("Upsert() error = %v", err)
+	}
+
+	cookies, err := repo.FindByHost(ctx, entity.DefaultJar, "example.com")
+	if err != nil || len(cookies) != 1 {
+		t.Fatalf("FindByHost() = %v, %v, want 1 cookie", cookies, err)
+	}
+	if !cookies[0].CreatedAt.Equal(created) {
+		t.Errorf("CreatedAt = %v, want %v", cookies[0].CreatedAt, created)
+	}
+	if !cookies[0].LastAccessedAt.Equal(updated) {
+		t.Errorf("LastAccessedAt = %v, want %v", cookies[0].LastAccessedAt, updated)
+	}
+
+	touched, err := repo.Touch(ctx, entity.DefaultJar, []entity.CookieKey{cookie.Key(), {Name: "missing", Domain: "example.com", Path: "/"}}, accessed)
+	if err != nil {
+		t.Fatalf("Touch() error = %v", err)
+	}
+	if touched != 1 {
+		t.Errorf("Touch() = %v, want 1", touched)
+	}
+
+	cookies, err = repo.FindByHost(ctx, entity.DefaultJar, "example.com")
+	if err != nil || len(cookies) != 1 {
+		t.Fatalf("FindByHost() = %v, %v, want 1 cookie", cookies, err)
+	}
+	if !cookies[0].CreatedAt.Equal(created) {
+		t.Errorf("CreatedAt = %v, want %v", cookies[0].CreatedAt, created)
+	}
+	if !cookies[0].LastAccessedAt.Equal(accessed) {
+		t.Errorf("LastAccessedAt = %v, want %v", cookies[0].LastAccessedAt, accessed)
+	}
+}
+
+func TestCanonicalDomain(t *testing.T) {
+	tests := []struct {
+		name   string
+		domain string
+		want   string
+	}{
+		{name: "正規化済みのドメイン", domain: "example.com", want: "example.com"},
+		{name: "先頭のドット", domain: ".example.com", want: "example.com"},
+		{name: "大文字と末尾のドット", domain: ".Example.COM.", want: "example.com"},
+		{name: "前後の空白", domain: " example.com ", want: "example.com"},
+		{name: "空文字列", domain: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := canonicalDomain(tt.domain); got != tt.want {
+				t.Errorf("canonicalDomain(%q) = %q, want %q", tt.domain, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToSubdomainPattern(t *testing.T) {
+	tests := []struct {
+		name  string
+		site  string
+		want  string
+		valid bool
+	}{
+		{name: "逆順にしたサブドメインの前方一致", site: "example.com", want: "moc.elpmaxe.%", valid: true},
+		{name: "正規化してから変換", site: ".Example.COM.", want: "moc.elpmaxe.%", valid: true},
+		{name: "LIKEのメタ文字をエスケープ", site: "my_site.example", want: `elpmaxe.etis\_ym.%`, valid: true},
+		{name: "空文字列はNULL", site: "", valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := toSubdomainPattern(tt.site)
+			if got.Valid != tt.valid || got.String != tt.want {
+				t.Errorf("toSubdomainPattern(%q) = %+v, want {String:%s Valid:%v}", tt.site, got, tt.want, tt.valid)
+			}
+		})
+	}
+}
+
+func TestCookieRepository_ByHost_LeadingDot(t *testing.T) {
+	dbConn := openTestDB(t)
+	repo := NewCookieRepository(dbConn, db.New(dbConn))
+	ctx := context.Background()
+
+	for _, cookie := range []*entity.Cookie{
+		{Name: "sid", Value: "value", Domain: ".example.com", Path: "/"},
+		{Name: "lang", Value: "value", Domain: "example.com", Path: "/", HostOnly: true},
+		{Name: "sid", Value: "value", Domain: "www.example.com", Path: "/"},
+	} {
+		if err := repo.Upsert(ctx, entity.DefaultJar, cookie, time.Now()); err != nil {
+			t.Fatalf("Upsert() error = %v", err)
+		}
+	}
+
+	// 先頭にドットのあるホストも Domain 属性と同様に正規化して一致させる
+	cookies, err := repo.FindByHost(ctx, entity.DefaultJar, ".Example.com")
+	if err != nil {
+		t.Fatalf("FindByHost() error = %v", err)
+	}
+	if len(cookies) != 2 {
+		t.Errorf("FindByHost(.Example.com) len = %v, want 2", len(cookies))
+	}
+
+	deleted, err := repo.DeleteByHost(ctx, entity.DefaultJar, ".example.com")
+	if err != nil {
+		t.Fatalf("DeleteByHost() error = %v", err)
+	}
+	if deleted != 2 {
+		t.Errorf("DeleteByHost(.example.com) = %v, want 2", deleted)
+	}
+	if remaining, err := repo.Count(ctx, entity.DefaultJar, ""); err != nil || remaining != 1 {
+		t.Errorf("Count() = %v, %v, want 1", remaining, err)
+	}
+}
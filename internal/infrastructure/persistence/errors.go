@@ -0,0 +1,31 @@
+package persistence
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/takumi3488/cookiejar-server/internal/domain/entity"
+)
+
+// PostgreSQL のエラーコード（https://www.postgresql.org/docs/current/errcodes-appendix.html）
+const (
+	foreignKeyViolation pq.ErrorCode = "23503"
+	uniqueViolation     pq.ErrorCode = "23505"
+)
+
+// translateJarError は jar に関する制約違反を entity のエラーに変換します
+// 存在しない jar への Cookie の保存は entity.ErrJarNotFound、既存の jar と同じ名前の jar の作成は entity.ErrJarExists になります
+func translateJarError(err error, jar string) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return err
+	}
+	switch {
+	case pqErr.Code == foreignKeyViolation && pqErr.Constraint == "cookie_jar_fkey":
+		return fmt.Errorf("%w: %q", entity.ErrJarNotFound, jar)
+	case pqErr.Code == uniqueViolation && pqErr.Constraint == "jar_pkey":
+		return fmt.Errorf("%w: %q", entity.ErrJarExists, jar)
+	}
+	return err
+}
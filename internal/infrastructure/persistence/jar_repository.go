@@ -0,0 +1,121 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/takumi3488/cookiejar-server/db"
+	"github.com/takumi3488/cookiejar-server/internal/domain/entity"
+	"github.com/takumi3488/cookiejar-server/internal/domain/repository"
+)
+
+type jarRepository struct {
+	db      *sql.DB
+	queries *db.Queries
+}
+
+func NewJarRepository(dbConn *sql.DB, queries *db.Queries) repository.JarRepository {
+	return &jarRepository{
+		db:      dbConn,
+		queries: queries,
+	}
+}
+
+func (r *jarRepository) Create(ctx context.Context, name string, createdAt time.Time) (*entity.Jar, error) {
+	row, err := r.queries.CreateJar(ctx, db.CreateJarParams{
+		Name:      name,
+		CreatedAt: createdAt,
+	})
+	if err != nil {
+		return nil, translateJarError(err, name)
+	}
+	return toJarEntity(row), nil
+}
+
+func (r *jarRepository) Find(ctx context.Context, name string) (*entity.Jar, error) {
+	row, err := r.queries.GetJar(ctx, name)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return toJarEntity(row), nil
+}
+
+func (r *jarRepository) FindAll(ctx context.Context) ([]*entity.Jar, error) {
+	rows, err := r.queries.ListJars(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*entity.Jar, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, &entity.Jar{
+			Name:        row.Name,
+			CreatedAt:   row.CreatedAt,
+			CookieCount: int(row.CookieCount),
+		})
+	}
+	return result, nil
+}
+
+func (r *jarRepository) Delete(ctx context.Context, name string) (int, error) {
+	// Cookie は外部キーの ON DELETE CASCADE により削除される
+	deleted, err := r.queries.DeleteJar(ctx, name)
+	if err != nil {
+		return 0, err
+	}
+	return int(deleted), nil
+}
+
+func (r *jarRepository) Clone(ctx context.Context, src, dst string, createdAt time.Time) (*entity.Jar, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		// Commit 済みの場合は sql.ErrTxDone となるため無視する
+		_ = tx.Rollback()
+	}()
+	q := r.queries.WithTx(tx)
+
+	if _, err := q.GetJar(ctx, src); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %q", entity.ErrJarNotFound, src)
+		}
+		return nil, err
+	}
+
+	row, err := q.CreateJar(ctx, db.CreateJarParams{
+		Name:      dst,
+		CreatedAt: createdAt,
+	})
+	if err != nil {
+		return nil, translateJarError(err, dst)
+	}
+
+	cloned, err := q.CloneJarCookies(ctx, db.CloneJarCookiesParams{
+		Dst: dst,
+		Src: src,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	jar := toJarEntity(row)
+	jar.CookieCount = int(cloned)
+	return jar, nil
+}
+
+func toJarEntity(row db.Jar) *entity.Jar {
+	return &entity.Jar{
+		Name:      row.Name,
+		CreatedAt: row.CreatedAt,
+	}
+}
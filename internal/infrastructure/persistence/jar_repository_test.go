@@ -0,0 +1,83 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/takumi3488/cookiejar-server/db"
+	"github.com/takumi3488/cookiejar-server/internal/domain/entity"
+)
+
+func TestJarRepository_Isolation(t *testing.T) {
+	dbConn := openTestDB(t)
+	queries := db.New(dbConn)
+	cookieRepo := NewCookieRepository(dbConn, queries)
+	jarRepo := NewJarRepository(dbConn, queries)
+	ctx := context.Background()
+	now := time.Now()
+
+	if _, err := jarRepo.Create(ctx, "bot-1", now); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if _, err := jarRepo.Create(ctx, "bot-1", now); !errors.Is(err, entity.ErrJarExists) {
+		t.Fatalf("Create() error = %v, want %v", err, entity.ErrJarExists)
+	}
+
+	// 同じ識別キーの Cookie を jar ごとに別々に保存できる
+	for _, jar := range []string{entity.DefaultJar, "bot-1"} {
+		if err := cookieRepo.Upsert(ctx, jar, &entity.Cookie{Name: "sid", Value: jar, Domain: "example.com", Path: "/"}, now); err != nil {
+			t.Fatalf("Upsert(%s) error = %v", jar, err)
+		}
+	}
+	if err := cookieRepo.Upsert(ctx, "missing", &entity.Cookie{Name: "sid", Value: "v", Domain: "example.com", Path: "/"}, now); !errors.Is(err, entity.ErrJarNotFound) {
+		t.Fatalf("Upsert(missing) error = %v, want %v", err, entity.ErrJarNotFound)
+	}
+
+	cookies, err := cookieRepo.FindByHost(ctx, "bot-1", "example.com")
+	if err != nil || len(cookies) != 1 || cookies[0].Value != "bot-1" {
+		t.Fatalf("FindByHost(bot-1) = %v, %v, want 1 cookie with value bot-1", cookies, err)
+	}
+
+	// 複製した jar は複製元と独立している
+	clone, err := jarRepo.Clone(ctx, "bot-1", "bot-2", now)
+	if err != nil {
+		t.Fatalf("Clone() error = %v", err)
+	}
+	if clone.CookieCount != 1 {
+		t.Errorf("Clone() CookieCount = %v, want 1", clone.CookieCount)
+	}
+	if _, err := jarRepo.Clone(ctx, "missing", "bot-3", now); !errors.Is(err, entity.ErrJarNotFound) {
+		t.Errorf("Clone(missing) error = %v, want %v", err, entity.ErrJarNotFound)
+	}
+	if _, err := jarRepo.Clone(ctx, "bot-1", entity.DefaultJar, now); !errors.Is(err, entity.ErrJarExists) {
+		t.Errorf("Clone(default) error = %v, want %v", err, entity.ErrJarExists)
+	}
+	if _, err := cookieRepo.DeleteByHost(ctx, "bot-1", "example.com"); err != nil {
+		t.Fatalf("DeleteByHost() error = %v", err)
+	}
+	if count, err := cookieRepo.Count(ctx, "bot-2", ""); err != nil || count != 1 {
+		t.Errorf("Count(bot-2) = %v, %v, want 1", count, err)
+	}
+
+	// jar を削除すると Cookie も削除される
+	if deleted, err := jarRepo.Delete(ctx, "bot-2"); err != nil || deleted != 1 {
+		t.Fatalf("Delete() = %v, %v, want 1", deleted, err)
+	}
+	if count, err := cookieRepo.Count(ctx, "bot-2", ""); err != nil || count != 0 {
+		t.Errorf("Count(bot-2) = %v, %v, want 0", count, err)
+	}
+
+	jars, err := jarRepo.FindAll(ctx)
+	if err != nil {
+		t.Fatalf("FindAll() error = %v", err)
+	}
+	counts := make(map[string]int, len(jars))
+	for _, jar := range jars {
+		counts[jar.Name] = jar.CookieCount
+	}
+	if len(counts) != 2 || counts[entity.DefaultJar] != 1 || counts["bot-1"] != 0 {
+		t.Errorf("FindAll() = %v, want map[bot-1:0 default:1]", counts)
+	}
+}
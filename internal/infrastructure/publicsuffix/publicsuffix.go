@@ -0,0 +1,185 @@
+// Package publicsuffix は Public Suffix List（https://publicsuffix.org/）を読み込み、ドメインの公開サフィックスを求めます
+//
+// 埋め込みのリストは public_suffix_list.dat を差し替えて再ビルドすることで更新できます
+// 起動時に Load でローカルファイルから読み込むこともできるため、ネットワークに接続できない環境でも動作します
+package publicsuffix
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/idna"
+)
+
+//go:embed public_suffix_list.dat
+var embeddedList string
+
+// ルールの種類（同じドメインに複数の種類のルールが存在しうるためビットで表す）
+const (
+	// 通常のルール（例: "co.uk"）
+	ruleNormal uint8 = 1 << iota
+	// ワイルドカードのルール（例: "*.ck" は "ck" に記録する）
+	ruleWildcard
+	// 例外のルール（例: "!www.ck"）
+	ruleException
+	// ICANN セクションのルール（なければプライベートセクション）
+	ruleICANN
+)
+
+const (
+	beginICANN   = "===BEGIN ICANN DOMAINS==="
+	endICANN     = "===END ICANN DOMAINS==="
+	beginPrivate = "===BEGIN PRIVATE DOMAINS==="
+	endPrivate   = "===END PRIVATE DOMAINS==="
+)
+
+// List は Public Suffix List のルールの集合です
+type List struct {
+	rules map[string]uint8
+}
+
+// Parse は Public Suffix List の形式（public_suffix_list.dat）のルールを読み込みます
+// 国際化ドメイン名のルールは Punycode に変換して保持します
+func Parse(r io.Reader) (*List, error) {
+	l := &List{rules: make(map[string]uint8)}
+	scanner := bufio.NewScanner(r)
+	icann := false
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if comment, ok := strings.CutPrefix(line, "//"); ok {
+			// セクションの境界はコメントで示される
+			switch strings.TrimSpace(comment) {
+			case beginICANN:
+				icann = true
+			case endICANN, beginPrivate, endPrivate:
+				icann = false
+			}
+			continue
+		}
+
+		// ルールは行頭から最初の空白までで、それ以降は無視する
+		rule := strings.Fields(line)[0]
+		kind := ruleNormal
+		if rest, ok := strings.CutPrefix(rule, "!"); ok {
+			rule, kind = rest, ruleException
+		} else if rest, ok := strings.CutPrefix(rule, "*."); ok {
+			rule, kind = rest, ruleWildcard
+		}
+		domain, err := toASCII(rule)
+		if err != nil || domain == "" || strings.Contains(domain, "*") {
+			return nil, fmt.Errorf("line %d: invalid rule %q", lineNum, line)
+		}
+		if icann {
+			kind |= ruleICANN
+		}
+		l.rules[domain] |= kind
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(l.rules) == 0 {
+		return nil, fmt.Errorf("no rules found")
+	}
+	return l, nil
+}
+
+// Load はローカルファイルから Public Suffix List を読み込みます
+func Load(path string) (*List, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	l, err := Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public suffix list %s: %w", path, err)
+	}
+	return l, nil
+}
+
+var defaultList = sync.OnceValue(func() *List {
+	l, err := Parse(strings.NewReader(embeddedList))
+	if err != nil {
+		panic(fmt.Sprintf("publicsuffix: invalid embedded list: %v", err))
+	}
+	return l
+})
+
+// Default は埋め込みの Public Suffix List を返します
+func Default() *List {
+	return defaultList()
+}
+
+// PublicSuffix は domain の公開サフィックスを返します
+// どのルールにも一致しない場合は既定のルール "*" に従い最上位のラベルを返します
+func (l *List) PublicSuffix(domain string) string {
+	suffix, _ := l.Lookup(domain)
+	return suffix
+}
+
+// Lookup は domain の公開サフィックスと、それが ICANN セクションのルールによるものかを返します
+func (l *List) Lookup(domain string) (suffix string, icann bool) {
+	domain = normalize(domain)
+	labels := strings.Split(domain, ".")
+
+	// 例外のルールはほかのルールより優先され、その親ドメインが公開サフィックスになる
+	for i := range labels {
+		if kind := l.rules[strings.Join(labels[i:], ".")]; kind&ruleException != 0 && i+1 < len(labels) {
+			return strings.Join(labels[i+1:], "."), kind&ruleICANN != 0
+		}
+	}
+
+	// 最も多くのラベルに一致するルールを採用する
+	for i := range labels {
+		candidate := strings.Join(labels[i:], ".")
+		if kind := l.rules[candidate]; kind&ruleNormal != 0 {
+			return candidate, kind&ruleICANN != 0
+		}
+		if i+1 < len(labels) {
+			if kind := l.rules[strings.Join(labels[i+1:], ".")]; kind&ruleWildcard != 0 {
+				return candidate, kind&ruleICANN != 0
+			}
+		}
+	}
+	return labels[len(labels)-1], false
+}
+
+// IsPublicSuffix は domain 自体が公開サフィックスかを判定します
+func (l *List) IsPublicSuffix(domain string) bool {
+	domain = normalize(domain)
+	return domain != "" && l.PublicSuffix(domain) == domain
+}
+
+// Len は読み込んだルールのドメイン数を返します
+func (l *List) Len() int {
+	return len(l.rules)
+}
+
+// normalize は domain を小文字にして末尾のドットを取り除き、Punycode に変換します
+func normalize(domain string) string {
+	domain = strings.TrimSuffix(strings.ToLower(domain), ".")
+	if ascii, err := toASCII(domain); err == nil {
+		return ascii
+	}
+	return domain
+}
+
+func toASCII(domain string) (string, error) {
+	for i := 0; i < len(domain); i++ {
+		if domain[i] >= 0x80 {
+			return idna.ToASCII(domain)
+		}
+	}
+	return domain, nil
+}
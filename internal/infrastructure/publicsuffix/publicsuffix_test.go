@@ -0,0 +1,164 @@
+package publicsuffix
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testList = `// ===BEGIN ICANN DOMAINS===
+
+// ルールの後の文字列は無視される
+com
+uk
+co.uk
+*.ck
+!www.ck
+jp
+*.kobe.jp
+!city.kobe.jp
+公司.cn
+
+// ===END ICANN DOMAINS===
+// ===BEGIN PRIVATE DOMAINS===
+
+github.io
+*.compute.amazonaws.com
+
+// ===END PRIVATE DOMAINS===
+`
+
+func TestList_Lookup(t *testing.T) {
+	l, err := Parse(strings.NewReader(testList))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		domain     string
+		wantSuffix string
+		wantICANN  bool
+	}{
+		{name: "ICANN: 単一ラベルのルール", domain: "www.example.com", wantSuffix: "com", wantICANN: true},
+		{name: "ICANN: 公開サフィックス自体", domain: "com", wantSuffix: "com", wantICANN: true},
+		{name: "ICANN: 複数ラベルのルールが優先", domain: "www.example.co.uk", wantSuffix: "co.uk", wantICANN: true},
+		{name: "ICANN: ワイルドカード", domain: "www.example.ck", wantSuffix: "example.ck", wantICANN: true},
+		{name: "ICANN: 例外", domain: "www.ck", wantSuffix: "ck", wantICANN: true},
+		{name: "ICANN: 例外のサブドメイン", domain: "a.www.ck", wantSuffix: "ck", wantICANN: true},
+		{name: "ICANN: 多段のワイルドカード", domain: "a.b.kobe.jp", wantSuffix: "b.kobe.jp", wantICANN: true},
+		{name: "ICANN: 多段の例外", domain: "www.city.kobe.jp", wantSuffix: "kobe.jp", wantICANN: true},
+		{name: "ICANN: 国際化ドメイン名はPunycodeで扱う", domain: "example.公司.cn", wantSuffix: "xn--55qx5d.cn", wantICANN: true},
+		{name: "ICANN: Punycodeの入力", domain: "example.xn--55qx5d.cn", wantSuffix: "xn--55qx5d.cn", wantICANN: true},
+		{name: "プライベート: 単一ルール", domain: "user.github.io", wantSuffix: "github.io"},
+		{name: "プライベート: ワイルドカード", domain: "ec2.us-east-1.compute.amazonaws.com", wantSuffix: "us-east-1.compute.amazonaws.com"},
+		{name: "プライベートのルールがなければICANNのルール", domain: "amazonaws.com", wantSuffix: "com", wantICANN: true},
+		{name: "大文字と末尾のドットは正規化する", domain: "WWW.Example.CO.UK.", wantSuffix: "co.uk", wantICANN: true},
+		{name: "一致するルールがなければ最上位のラベル", domain: "example.invalid", wantSuffix: "invalid"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			suffix, icann := l.Lookup(tt.domain)
+			if suffix != tt.wantSuffix {
+				t.Errorf("Lookup(%q) suffix = %q, want %q", tt.domain, suffix, tt.wantSuffix)
+			}
+			if icann != tt.wantICANN {
+				t.Errorf("Lookup(%q) icann = %v, want %v", tt.domain, icann, tt.wantICANN)
+			}
+		})
+	}
+}
+
+func TestList_IsPublicSuffix(t *testing.T) {
+	l, err := Parse(strings.NewReader(testList))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	tests := []struct {
+		domain string
+		want   bool
+	}{
+		{domain: "com", want: true},
+		{domain: "co.uk", want: true},
+		{domain: "example.co.uk", want: false},
+		{domain: "example.ck", want: true},
+		{domain: "www.ck", want: false},
+		{domain: "github.io", want: true},
+		{domain: "user.github.io", want: false},
+		{domain: "", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.domain, func(t *testing.T) {
+			if got := l.IsPublicSuffix(tt.domain); got != tt.want {
+				t.Errorf("IsPublicSuffix(%q) = %v, want %v", tt.domain, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse_Error(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "ルールがない", input: "// comment only\n"},
+		{name: "ワイルドカードが途中にある", input: "a.*.com\n"},
+		{name: "例外のルールが空", input: "!\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(strings.NewReader(tt.input)); err == nil {
+				t.Errorf("Parse(%q) error = nil, want error", tt.input)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "public_suffix_list.dat")
+	if err := os.WriteFile(path, []byte(testList), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !l.IsPublicSuffix("github.io") {
+		t.Errorf(`IsPublicSuffix("github.io") = false, want true`)
+	}
+
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.dat")); err == nil {
+		t.Error("Load() error = nil for missing file, want error")
+	}
+}
+
+func TestDefault(t *testing.T) {
+	l := Default()
+
+	tests := []struct {
+		domain     string
+		wantSuffix string
+		wantICANN  bool
+	}{
+		{domain: "www.example.com", wantSuffix: "com", wantICANN: true},
+		{domain: "www.example.co.uk", wantSuffix: "co.uk", wantICANN: true},
+		{domain: "www.ck", wantSuffix: "ck", wantICANN: true},
+		{domain: "user.github.io", wantSuffix: "github.io"},
+		{domain: "app.blogspot.com", wantSuffix: "blogspot.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.domain, func(t *testing.T) {
+			suffix, icann := l.Lookup(tt.domain)
+			if suffix != tt.wantSuffix || icann != tt.wantICANN {
+				t.Errorf("Lookup(%q) = (%q, %v), want (%q, %v)", tt.domain, suffix, icann, tt.wantSuffix, tt.wantICANN)
+			}
+		})
+	}
+}
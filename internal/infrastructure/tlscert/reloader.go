@@ -0,0 +1,163 @@
+// Package tlscert はディスク上の TLS 証明書を読み込み、ローテーションされた証明書をサーバーを再起動せずに反映します
+package tlscert
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"sync"
+	"time"
+)
+
+// ErrNoClientCertificate はクライアント証明書が提示されなかったことを示すエラーです
+var ErrNoClientCertificate = errors.New("client certificate is required")
+
+// Reloader はサーバー証明書・秘密鍵と、任意のクライアント証明書の CA を読み込みます
+// Reload でファイルの更新を検知した場合は読み込み直し、以降の TLS ハンドシェイクで新しい証明書を使います
+type Reloader struct {
+	certFile string
+	keyFile  string
+	// 空の場合はクライアント証明書を要求しない
+	clientCAFile string
+
+	mu        sync.RWMutex
+	cert      *tls.Certificate
+	clientCAs *x509.CertPool
+	// 最後に読み込んだ時点の各ファイルの更新時刻
+	modTimes []time.Time
+}
+
+// NewReloader は証明書を読み込んだ Reloader を返します
+// clientCAFile を指定した場合は、その CA が署名したクライアント証明書を要求します（mTLS）
+func NewReloader(certFile, keyFile, clientCAFile string) (*Reloader, error) {
+	r := &Reloader{
+		certFile:     certFile,
+		keyFile:      keyFile,
+		clientCAFile: clientCAFile,
+	}
+	if _, err := r.reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Reload はファイルの更新時刻が前回の読み込みから変わっている場合に証明書を読み込み直します（scheduler.Job として定期実行する）
+// 読み込みに失敗した場合（証明書と秘密鍵の片方だけが更新された途中の状態など）はエラーを返し、以前の証明書を使い続けます
+func (r *Reloader) Reload(ctx context.Context) error {
+	reloaded, err := r.reload()
+	if err != nil {
+		return err
+	}
+	if reloaded {
+		cert := r.certificate()
+		log.Printf("Reloaded TLS certificate from %s (subject=%q, expires=%s)", r.certFile, cert.Leaf.Subject, cert.Leaf.NotAfter.Format(time.RFC3339))
+	}
+	return nil
+}
+
+func (r *Reloader) reload() (bool, error) {
+	modTimes, err := r.stat()
+	if err != nil {
+		return false, err
+	}
+	r.mu.RLock()
+	unchanged := slices.EqualFunc(modTimes, r.modTimes, time.Time.Equal)
+	r.mu.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return false, fmt.Errorf("failed to load TLS key pair from %s and %s: %w", r.certFile, r.keyFile, err)
+	}
+	var clientCAs *x509.CertPool
+	if r.clientCAFile != "" {
+		pem, err := os.ReadFile(r.clientCAFile)
+		if err != nil {
+			return false, fmt.Errorf("failed to read client CA file: %w", err)
+		}
+		clientCAs = x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(pem) {
+			return false, fmt.Errorf("no certificates found in client CA file %s", r.clientCAFile)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cert = &cert
+	r.clientCAs = clientCAs
+	r.modTimes = modTimes
+	return true, nil
+}
+
+// stat は読み込むファイルの更新時刻を返します
+func (r *Reloader) stat() ([]time.Time, error) {
+	files := []string{r.certFile, r.keyFile}
+	if r.clientCAFile != "" {
+		files = append(files, r.clientCAFile)
+	}
+	modTimes := make([]time.Time, len(files))
+	for i, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return nil, err
+		}
+		modTimes[i] = info.ModTime()
+	}
+	return modTimes, nil
+}
+
+func (r *Reloader) certificate() *tls.Certificate {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert
+}
+
+// TLSConfig は最新の証明書を使うサーバーの tls.Config を返します
+// クライアント CA を指定した場合は、セッション再開を含むすべての接続で、その時点の CA でクライアント証明書を検証します
+func (r *Reloader) TLSConfig() *tls.Config {
+	cfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return r.certificate(), nil
+		},
+	}
+	if r.clientCAFile != "" {
+		// ClientCAs は読み込み直せないため、標準の検証の代わりに VerifyConnection で検証する
+		// VerifyPeerCertificate と異なり、VerifyConnection はセッション再開時にも呼ばれるため、
+		// CA のローテーションで外した CA のクライアント証明書をセッションチケットで使い続けることはできない
+		cfg.ClientAuth = tls.RequireAnyClientCert
+		cfg.VerifyConnection = func(state tls.ConnectionState) error {
+			return r.verifyClientCertificate(state.PeerCertificates)
+		}
+	}
+	return cfg
+}
+
+// verifyClientCertificate はクライアント証明書がクライアント CA の署名したクライアント認証用の証明書であるかを検証します
+func (r *Reloader) verifyClientCertificate(certs []*x509.Certificate) error {
+	if len(certs) == 0 {
+		return ErrNoClientCertificate
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+	r.mu.RLock()
+	roots := r.clientCAs
+	r.mu.RUnlock()
+
+	_, err := certs[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	return err
+}
@@ -0,0 +1,289 @@
+package tlscert
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// testCA はテスト用の証明書を発行する CA です
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newTestCA(t *testing.T, name string) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CreateCertificate() error = %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("ParseCertificate() error = %v", err)
+	}
+	return &testCA{cert: cert, key: key}
+}
+
+// issue は CA が署名した証明書と秘密鍵を返します
+func (ca *testCA) issue(t *testing.T, commonName string, usage x509.ExtKeyUsage) tls.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatalf("CreateCertificate() error = %v", err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+// writeKeyPair は証明書と秘密鍵を PEM で書き込み、更新を検知できるよう更新時刻を modTime にします
+func writeKeyPair(t *testing.T, certFile, keyFile string, cert tls.Certificate, modTime time.Time) {
+	t.Helper()
+	keyDER, err := x509.MarshalECPrivateKey(cert.PrivateKey.(*ecdsa.PrivateKey))
+	if err != nil {
+		t.Fatalf("MarshalECPrivateKey() error = %v", err)
+	}
+	writePEM(t, certFile, "CERTIFICATE", cert.Certificate[0], modTime)
+	writePEM(t, keyFile, "EC PRIVATE KEY", keyDER, modTime)
+}
+
+func writePEM(t *testing.T, file, blockType string, der []byte, modTime time.Time) {
+	t.Helper()
+	if err := os.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	if err := os.Chtimes(file, modTime, modTime); err != nil {
+		t.Fatalf("Chtimes() error = %v", err)
+	}
+}
+
+// handshake は serverConfig のサーバーに clientConfig で接続し、クライアント側の接続の状態とサーバー側のエラーを返します
+func handshake(t *testing.T, serverConfig, clientConfig *tls.Config) (tls.ConnectionState, error) {
+	t.Helper()
+	lis, err := tls.Listen("tcp", "127.0.0.1:0", serverConfig)
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	defer lis.Close()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		conn, err := lis.Accept()
+		if err != nil {
+			serverErr <- err
+			return
+		}
+		defer conn.Close()
+		_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+		// TLS 1.3 ではクライアント証明書の検証はクライアントのハンドシェイク完了後に行われるため、1 バイト読んで結果を待つ
+		_, err = conn.Read(make([]byte, 1))
+		serverErr <- err
+	}()
+
+	conn, err := tls.DialWithDialer(&net.Dialer{Timeout: 5 * time.Second}, "tcp", lis.Addr().String(), clientConfig)
+	var state tls.ConnectionState
+	if err == nil {
+		defer conn.Close()
+		state = conn.ConnectionState()
+		_, _ = conn.Write([]byte{0})
+		// サーバーが接続を閉じるまで読み、TLS 1.3 のセッションチケットを受け取る
+		_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		_, _ = conn.Read(make([]byte, 1))
+	}
+	return state, <-serverErr
+}
+
+func TestReloader_Reload(t *testing.T) {
+	ca := newTestCA(t, "test-ca")
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "tls.crt")
+	keyFile := filepath.Join(dir, "tls.key")
+	modTime := time.Now().Add(-time.Minute)
+	writeKeyPair(t, certFile, keyFile, ca.issue(t, "server-1", x509.ExtKeyUsageServerAuth), modTime)
+
+	reloader, err := NewReloader(certFile, keyFile, "")
+	if err != nil {
+		t.Fatalf("NewReloader() error = %v", err)
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	clientConfig := &tls.Config{RootCAs: roots, ServerName: "localhost"}
+
+	assertServerCert := func(want string) {
+		t.Helper()
+		state, err := handshake(t, reloader.TLSConfig(), clientConfig)
+		if err != nil {
+			t.Fatalf("handshake() error = %v", err)
+		}
+		if cert := state.PeerCertificates[0]; cert.Subject.CommonName != want {
+			t.Errorf("server certificate CN = %q, want %q", cert.Subject.CommonName, want)
+		}
+	}
+	assertServerCert("server-1")
+
+	// 証明書をローテーションすると、再起動せずに新しい証明書を使う
+	writeKeyPair(t, certFile, keyFile, ca.issue(t, "server-2", x509.ExtKeyUsageServerAuth), modTime.Add(time.Second))
+	if err := reloader.Reload(context.Background()); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	assertServerCert("server-2")
+
+	// 読み込みに失敗した場合は以前の証明書を使い続ける
+	writePEM(t, keyFile, "EC PRIVATE KEY", []byte("broken"), modTime.Add(2*time.Second))
+	if err := reloader.Reload(context.Background()); err == nil {
+		t.Error("Reload() error = nil, want error for broken key")
+	}
+	assertServerCert("server-2")
+
+	// 更新時刻が変わっていない場合は読み込み直さない
+	if reloaded, err := reloader.reload(); err == nil && reloaded {
+		t.Error("reload() = true, want false for unchanged files")
+	}
+}
+
+func TestReloader_ClientCertificate(t *testing.T) {
+	ca := newTestCA(t, "test-ca")
+	otherCA := newTestCA(t, "other-ca")
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "tls.crt")
+	keyFile := filepath.Join(dir, "tls.key")
+	clientCAFile := filepath.Join(dir, "ca.crt")
+	modTime := time.Now().Add(-time.Minute)
+	writeKeyPair(t, certFile, keyFile, ca.issue(t, "server", x509.ExtKeyUsageServerAuth), modTime)
+	writePEM(t, clientCAFile, "CERTIFICATE", ca.cert.Raw, modTime)
+
+	reloader, err := NewReloader(certFile, keyFile, clientCAFile)
+	if err != nil {
+		t.Fatalf("NewReloader() error = %v", err)
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+
+	tests := []struct {
+		name    string
+		certs   []tls.Certificate
+		wantErr bool
+	}{
+		{
+			name:  "CA が署名したクライアント証明書",
+			certs: []tls.Certificate{ca.issue(t, "bot-1", x509.ExtKeyUsageClientAuth)},
+		},
+		{
+			name:    "クライアント証明書なし",
+			wantErr: true,
+		},
+		{
+			name:    "別の CA が署名したクライアント証明書",
+			certs:   []tls.Certificate{otherCA.issue(t, "bot-1", x509.ExtKeyUsageClientAuth)},
+			wantErr: true,
+		},
+		{
+			name:    "サーバー認証用の証明書",
+			certs:   []tls.Certificate{ca.issue(t, "bot-1", x509.ExtKeyUsageServerAuth)},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := handshake(t, reloader.TLSConfig(), &tls.Config{RootCAs: roots, ServerName: "localhost", Certificates: tt.certs})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("handshake() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// クライアント CA をローテーションすると、新しい CA が署名したクライアント証明書を受け付ける
+	writePEM(t, clientCAFile, "CERTIFICATE", otherCA.cert.Raw, modTime.Add(time.Second))
+	if err := reloader.Reload(context.Background()); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	clientConfig := &tls.Config{RootCAs: roots, ServerName: "localhost", Certificates: []tls.Certificate{otherCA.issue(t, "bot-1", x509.ExtKeyUsageClientAuth)}}
+	if _, err := handshake(t, reloader.TLSConfig(), clientConfig); err != nil {
+		t.Errorf("handshake() after CA rotation error = %v", err)
+	}
+}
+
+func TestReloader_ClientCARotation_Resumption(t *testing.T) {
+	ca := newTestCA(t, "test-ca")
+	newCA := newTestCA(t, "new-ca")
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "tls.crt")
+	keyFile := filepath.Join(dir, "tls.key")
+	clientCAFile := filepath.Join(dir, "ca.crt")
+	modTime := time.Now().Add(-time.Minute)
+	writeKeyPair(t, certFile, keyFile, ca.issue(t, "server", x509.ExtKeyUsageServerAuth), modTime)
+	writePEM(t, clientCAFile, "CERTIFICATE", ca.cert.Raw, modTime)
+
+	reloader, err := NewReloader(certFile, keyFile, clientCAFile)
+	if err != nil {
+		t.Fatalf("NewReloader() error = %v", err)
+	}
+	// セッションチケットの鍵を共有するため、サーバーの tls.Config は本番と同じく1つだけ使う
+	serverConfig := reloader.TLSConfig()
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	clientConfig := &tls.Config{
+		RootCAs:            roots,
+		ServerName:         "localhost",
+		Certificates:       []tls.Certificate{ca.issue(t, "bot-1", x509.ExtKeyUsageClientAuth)},
+		ClientSessionCache: tls.NewLRUClientSessionCache(1),
+	}
+
+	if _, err := handshake(t, serverConfig, clientConfig); err != nil {
+		t.Fatalf("handshake() error = %v", err)
+	}
+	// ローテーション前はセッションを再開できる
+	state, err := handshake(t, serverConfig, clientConfig)
+	if err != nil {
+		t.Fatalf("handshake() error = %v", err)
+	}
+	if !state.DidResume {
+		t.Fatal("handshake() did not resume session, want resumed")
+	}
+
+	// クライアント CA から外した CA のクライアント証明書は、セッションを再開しても拒否する
+	writePEM(t, clientCAFile, "CERTIFICATE", newCA.cert.Raw, modTime.Add(time.Second))
+	if err := reloader.Reload(context.Background()); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if _, err := handshake(t, serverConfig, clientConfig); err == nil {
+		t.Error("handshake() after CA rotation error = nil, want rejected")
+	}
+}
@@ -0,0 +1,264 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/takumi3488/cookiejar-server/internal/domain/entity"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// BrowserCookie は Playwright の storageState と Puppeteer の page.cookies() が共通で使う Cookie の形式です
+type BrowserCookie struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	// 先頭がドットの場合はサブドメインにも送信される Cookie、それ以外は host-only Cookie
+	Domain string `json:"domain"`
+	Path   string `json:"path"`
+	// Unix 時刻（秒）。セッション Cookie は -1
+	Expires  float64 `json:"expires"`
+	HttpOnly bool    `json:"httpOnly"`
+	Secure   bool    `json:"secure"`
+	// Strict / Lax / None（Chrome 拡張の no_restriction / unspecified なども受け付ける）
+	SameSite string `json:"sameSite,omitempty"`
+	// Partitioned Cookie のトップレベルサイト。Playwright は文字列、Puppeteer は puppeteerPartitionKey のオブジェクト
+	PartitionKey json.RawMessage `json:"partitionKey,omitempty"`
+}
+
+// puppeteerPartitionKey は Puppeteer の CookiePartitionKey です（hasCrossSiteAncestor は保存しません）
+type puppeteerPartitionKey struct {
+	SourceOrigin string `json:"sourceOrigin"`
+}
+
+// StorageState は Playwright の storageState です（origins の localStorage は保存しません）
+type StorageState struct {
+	Cookies []*BrowserCookie  `json:"cookies"`
+	Origins []json.RawMessage `json:"origins"`
+}
+
+// ToCookie は BrowserCookie を entity.Cookie に変換します
+func (b *BrowserCookie) ToCookie() (*entity.Cookie, error) {
+	if b.Name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+	domain := entity.CanonicalizeHost(strings.TrimPrefix(b.Domain, "."))
+	if domain == "" {
+		return nil, fmt.Errorf("domain is required")
+	}
+	sameSite, err := parseBrowserSameSite(b.SameSite)
+	if err != nil {
+		return nil, err
+	}
+	partitionKey, err := parsePartitionKey(b.PartitionKey)
+	if err != nil {
+		return nil, err
+	}
+
+	cookie := &entity.Cookie{
+		Name:         b.Name,
+		Value:        b.Value,
+		Domain:       domain,
+		Path:         b.Path,
+		Secure:       b.Secure,
+		HttpOnly:     b.HttpOnly,
+		SameSite:     sameSite,
+		HostOnly:     !strings.HasPrefix(b.Domain, "."),
+		Partitioned:  partitionKey != "",
+		PartitionKey: partitionKey,
+	}
+	// 0 以下はセッション Cookie
+	if b.Expires > 0 {
+		sec, frac := math.Modf(b.Expires)
+		cookie.Expires = time.Unix(int64(sec), int64(frac*1e9)).UTC()
+	}
+	if err := cookie.ValidatePrefix(); err != nil {
+		return nil, err
+	}
+	return cookie, nil
+}
+
+// NewBrowserCookie は entity.Cookie を Playwright の形式の BrowserCookie に変換します（partitionKey は文字列）
+// パーティションキーが不明な Partitioned Cookie（Set-Cookie ヘッダーから取り込んだもの）は partitionKey を出力しません
+func NewBrowserCookie(cookie *entity.Cookie) *BrowserCookie {
+	domain := cookie.CanonicalDomain()
+	if !cookie.HostOnly {
+		domain = "." + domain
+	}
+	expires := float64(-1)
+	if !cookie.Expires.IsZero() {
+		expires = float64(cookie.Expires.Unix())
+	}
+	b := &BrowserCookie{
+		Name:     cookie.Name,
+		Value:    cookie.Value,
+		Domain:   domain,
+		Path:     cookie.CanonicalPath(),
+		Expires:  expires,
+		HttpOnly: cookie.HttpOnly,
+		Secure:   cookie.Secure,
+		SameSite: formatBrowserSameSite(cookie.SameSite),
+	}
+	if cookie.PartitionKey != "" {
+		b.PartitionKey, _ = json.Marshal(cookie.PartitionKey)
+	}
+	return b
+}
+
+// NewPuppeteerCookie は entity.Cookie を Puppeteer の形式の BrowserCookie に変換します（partitionKey はオブジェクト）
+func NewPuppeteerCookie(cookie *entity.Cookie) *BrowserCookie {
+	b := NewBrowserCookie(cookie)
+	if cookie.PartitionKey != "" {
+		b.PartitionKey, _ = json.Marshal(puppeteerPartitionKey{SourceOrigin: cookie.PartitionKey})
+	}
+	return b
+}
+
+func parseBrowserSameSite(s string) (http.SameSite, error) {
+	switch strings.ToLower(s) {
+	case "", "unspecified":
+		return 0, nil
+	case "lax":
+		return http.SameSiteLaxMode, nil
+	case "strict":
+		return http.SameSiteStrictMode, nil
+	case "none", "no_restriction":
+		return http.SameSiteNoneMode, nil
+	default:
+		return 0, fmt.Errorf("invalid sameSite %q", s)
+	}
+}
+
+func formatBrowserSameSite(sameSite http.SameSite) string {
+	switch sameSite {
+	case http.SameSiteLaxMode:
+		return "Lax"
+	case http.SameSiteStrictMode:
+		return "Strict"
+	case http.SameSiteNoneMode:
+		return "None"
+	default:
+		return ""
+	}
+}
+
+// parsePartitionKey は Playwright の文字列または Puppeteer のオブジェクトの partitionKey からトップレベルサイトを取り出します
+// 未設定・null・空文字列の場合は空文字列（パーティションなし）を返します
+func parsePartitionKey(raw json.RawMessage) (string, error) {
+	trimmed := bytes.TrimSpace(raw)
+	if len(trimmed) == 0 || bytes.Equal(trimmed, []byte("null")) {
+		return "", nil
+	}
+
+	var site string
+	if err := json.Unmarshal(trimmed, &site); err == nil {
+		return site, nil
+	}
+	var key puppeteerPartitionKey
+	if err := json.Unmarshal(trimmed, &key); err != nil || key.SourceOrigin == "" {
+		return "", fmt.Errorf("invalid partitionKey: must be a string or an object with sourceOrigin")
+	}
+	return key.SourceOrigin, nil
+}
+
+// ImportStorageState は Playwright の storageState を読み込み、Cookie を保存します
+func (h *CookieHandler) ImportStorageState(c fiber.Ctx) error {
+	ctx := c.Context()
+	span := trace.SpanFromContext(ctx)
+
+	var state StorageState
+	if err := json.Unmarshal(c.Body(), &state); err != nil {
+		log.Printf("Failed to parse JSON request body: %v", err)
+		return respondError(c, span, fiber.StatusBadRequest, "Invalid JSON format or storageState structure", err)
+	}
+	return h.importBrowserCookies(c, span, state.Cookies)
+}
+
+// ExportStorageState は保存されているCookieを Playwright の storageState 形式で返します
+func (h *CookieHandler) ExportStorageState(c fiber.Ctx) error {
+	return h.exportBrowserCookies(c, NewBrowserCookie, func(cookies []*BrowserCookie) error {
+		return c.JSON(StorageState{
+			Cookies: cookies,
+			Origins: []json.RawMessage{},
+		})
+	})
+}
+
+// ImportPuppeteerCookies は Puppeteer の page.cookies() の配列を読み込み、Cookie を保存します
+func (h *CookieHandler) ImportPuppeteerCookies(c fiber.Ctx) error {
+	ctx := c.Context()
+	span := trace.SpanFromContext(ctx)
+
+	var browserCookies []*BrowserCookie
+	if err := json.Unmarshal(c.Body(), &browserCookies); err != nil {
+		log.Printf("Failed to parse JSON request body: %v", err)
+		return respondError(c, span, fiber.StatusBadRequest, "Invalid JSON format or cookie structure", err)
+	}
+	return h.importBrowserCookies(c, span, browserCookies)
+}
+
+// ExportPuppeteerCookies は保存されているCookieを Puppeteer の page.setCookie() に渡せる配列で返します
+func (h *CookieHandler) ExportPuppeteerCookies(c fiber.Ctx) error {
+	return h.exportBrowserCookies(c, NewPuppeteerCookie, func(cookies []*BrowserCookie) error {
+		return c.JSON(cookies)
+	})
+}
+
+func (h *CookieHandler) importBrowserCookies(c fiber.Ctx, span trace.Span, browserCookies []*BrowserCookie) error {
+	cookies := make([]*entity.Cookie, len(browserCookies))
+	for i, b := range browserCookies {
+		cookie, err := b.ToCookie()
+		if err != nil {
+			return respondError(c, span, fiber.StatusBadRequest, fmt.Sprintf("Invalid cookie at index %d: %v", i, err), err)
+		}
+		cookies[i] = cookie
+	}
+
+	if err := h.cookieUsecase.StoreCookies(c.Context(), jarParam(c), cookies); err != nil {
+		return respondStoreError(c, span, err)
+	}
+
+	span.SetStatus(codes.Ok, "Successfully imported cookies")
+	span.SetAttributes(attribute.Int("http.response.status_code", fiber.StatusOK))
+	return c.JSON(fiber.Map{
+		"status": "success",
+		"count":  len(cookies),
+	})
+}
+
+// exportBrowserCookies はクエリパラメータ host / domain で絞り込んだCookieを convert で変換し、render でレスポンスに書き込みます
+func (h *CookieHandler) exportBrowserCookies(c fiber.Ctx, convert func(cookie *entity.Cookie) *BrowserCookie, render func(cookies []*BrowserCookie) error) error {
+	ctx := c.Context()
+	span := trace.SpanFromContext(ctx)
+
+	filter, err := parseExportFilter(c)
+	if err != nil {
+		return respondError(c, span, fiber.StatusBadRequest, err.Error(), err)
+	}
+
+	cookies, err := h.cookieUsecase.GetAllCookies(ctx, jarParam(c))
+	if err != nil {
+		return respondUsecaseError(c, span, "Failed to get cookies", err)
+	}
+
+	exported := filter.apply(cookies)
+	result := make([]*BrowserCookie, len(exported))
+	for i, cookie := range exported {
+		result[i] = convert(cookie)
+	}
+
+	span.SetStatus(codes.Ok, "Successfully exported cookies")
+	span.SetAttributes(
+		attribute.Int("cookie.count", len(result)),
+		attribute.Int("http.response.status_code", fiber.StatusOK),
+	)
+	return render(result)
+}
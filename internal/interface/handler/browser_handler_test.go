@@ -0,0 +1,313 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/takumi3488/cookiejar-server/internal/domain/entity"
+)
+
+func TestBrowserCookie_ToCookie(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    *entity.Cookie
+		wantErr bool
+	}{
+		{
+			name:  "先頭がドットのドメインはサブドメインにも送信される",
+			input: `{"name":"sid","value":"abc","domain":".Example.com","path":"/","expires":1924992000.5,"httpOnly":true,"secure":true,"sameSite":"Lax"}`,
+			want: &entity.Cookie{
+				Name: "sid", Value: "abc", Domain: "example.com", Path: "/",
+				Expires:  time.Unix(1924992000, 500000000).UTC(),
+				HttpOnly: true, Secure: true, SameSite: http.SameSiteLaxMode,
+			},
+		},
+		{
+			name:  "ドットなしのドメインはhost-onlyでexpires=-1はセッションCookie",
+			input: `{"name":"lang","value":"ja","domain":"www.example.com","path":"/","expires":-1,"httpOnly":false,"secure":false,"sameSite":"Strict"}`,
+			want: &entity.Cookie{
+				Name: "lang", Value: "ja", Domain: "www.example.com", Path: "/",
+				HostOnly: true, SameSite: http.SameSiteStrictMode,
+			},
+		},
+		{
+			name:  "Playwrightの文字列のpartitionKeyはPartitionedになる",
+			input: `{"name":"chips","value":"1","domain":"example.com","path":"/","expires":-1,"secure":true,"sameSite":"None","partitionKey":"https://site.example"}`,
+			want: &entity.Cookie{
+				Name: "chips", Value: "1", Domain: "example.com", Path: "/",
+				HostOnly: true, Secure: true, SameSite: http.SameSiteNoneMode, Partitioned: true, PartitionKey: "https://site.example",
+			},
+		},
+		{
+			name:  "PuppeteerのオブジェクトのpartitionKeyとno_restrictionを解釈する",
+			input: `{"name":"chips","value":"1","domain":"example.com","path":"/","expires":-1,"secure":true,"sameSite":"no_restriction","partitionKey":{"sourceOrigin":"https://site.example"}}`,
+			want: &entity.Cookie{
+				Name: "chips", Value: "1", Domain: "example.com", Path: "/",
+				HostOnly: true, Secure: true, SameSite: http.SameSiteNoneMode, Partitioned: true, PartitionKey: "https://site.example",
+			},
+		},
+		{
+			name:  "nullのpartitionKeyは無視する",
+			input: `{"name":"sid","value":"1","domain":"example.com","path":"/","expires":-1,"partitionKey":null}`,
+			want:  &entity.Cookie{Name: "sid", Value: "1", Domain: "example.com", Path: "/", HostOnly: true},
+		},
+		{
+			name:  "空文字列のpartitionKeyは無視する",
+			input: `{"name":"sid","value":"1","domain":"example.com","path":"/","expires":-1,"partitionKey":""}`,
+			want:  &entity.Cookie{Name: "sid", Value: "1", Domain: "example.com", Path: "/", HostOnly: true},
+		},
+		{
+			name:    "sourceOriginのないpartitionKeyのオブジェクト",
+			input:   `{"name":"chips","value":"1","domain":"example.com","secure":true,"partitionKey":{"hasCrossSiteAncestor":false}}`,
+			wantErr: true,
+		},
+		{
+			name:    "sameSiteが不正",
+			input:   `{"name":"sid","value":"1","domain":"example.com","sameSite":"Relaxed"}`,
+			wantErr: true,
+		},
+		{
+			name:    "domainがない",
+			input:   `{"name":"sid","value":"1"}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b BrowserCookie
+			if err := json.Unmarshal([]byte(tt.input), &b); err != nil {
+				t.Fatalf("Failed to unmarshal input: %v", err)
+			}
+
+			got, err := b.ToCookie()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ToCookie() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if *got != *tt.want {
+				t.Errorf("ToCookie() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewBrowserCookie(t *testing.T) {
+	tests := []struct {
+		name   string
+		cookie *entity.Cookie
+		want   BrowserCookie
+	}{
+		{
+			name:   "ドメインCookieは先頭にドットを付ける",
+			cookie: &entity.Cookie{Name: "sid", Value: "abc", Domain: "example.com", Path: "/app", Expires: time.Unix(1924992000, 0), Secure: true, HttpOnly: true, SameSite: http.SameSiteLaxMode},
+			want:   BrowserCookie{Name: "sid", Value: "abc", Domain: ".example.com", Path: "/app", Expires: 1924992000, Secure: true, HttpOnly: true, SameSite: "Lax"},
+		},
+		{
+			name:   "host-onlyのセッションCookieはexpires=-1",
+			cookie: &entity.Cookie{Name: "lang", Value: "ja", Domain: "www.example.com", HostOnly: true},
+			want:   BrowserCookie{Name: "lang", Value: "ja", Domain: "www.example.com", Path: "/", Expires: -1},
+		},
+		{
+			name:   "パーティションキーは文字列で出力する",
+			cookie: &entity.Cookie{Name: "chips", Value: "1", Domain: "example.com", HostOnly: true, Secure: true, Partitioned: true, PartitionKey: "https://site.example"},
+			want:   BrowserCookie{Name: "chips", Value: "1", Domain: "example.com", Path: "/", Expires: -1, Secure: true, PartitionKey: json.RawMessage(`"https://site.example"`)},
+		},
+		{
+			name:   "パーティションキーが不明なPartitioned CookieはpartitionKeyを出力しない",
+			cookie: &entity.Cookie{Name: "chips", Value: "1", Domain: "example.com", HostOnly: true, Secure: true, Partitioned: true},
+			want:   BrowserCookie{Name: "chips", Value: "1", Domain: "example.com", Path: "/", Expires: -1, Secure: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewBrowserCookie(tt.cookie)
+			if got.Name != tt.want.Name || got.Value != tt.want.Value || got.Domain != tt.want.Domain || got.Path != tt.want.Path ||
+				got.Expires != tt.want.Expires || got.Secure != tt.want.Secure || got.HttpOnly != tt.want.HttpOnly || got.SameSite != tt.want.SameSite ||
+				!bytes.Equal(got.PartitionKey, tt.want.PartitionKey) {
+				t.Errorf("NewBrowserCookie() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBrowserCookie_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		convert func(cookie *entity.Cookie) *BrowserCookie
+	}{
+		{
+			name:    "PlaywrightのCHIPS Cookie",
+			input:   `{"name":"chips","value":"1","domain":"example.com","path":"/","expires":1924992000,"httpOnly":true,"secure":true,"sameSite":"None","partitionKey":"https://site.example"}`,
+			convert: NewBrowserCookie,
+		},
+		{
+			name:    "PuppeteerのCHIPS Cookie",
+			input:   `{"name":"chips","value":"1","domain":".example.com","path":"/","expires":-1,"httpOnly":false,"secure":true,"sameSite":"None","partitionKey":{"sourceOrigin":"https://site.example"}}`,
+			convert: NewPuppeteerCookie,
+		},
+		{
+			name:    "パーティションなしのCookie",
+			input:   `{"name":"sid","value":"abc","domain":".example.com","path":"/app","expires":-1,"httpOnly":true,"secure":false,"sameSite":"Lax"}`,
+			convert: NewPuppeteerCookie,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b BrowserCookie
+			if err := json.Unmarshal([]byte(tt.input), &b); err != nil {
+				t.Fatalf("Failed to unmarshal input: %v", err)
+			}
+			cookie, err := b.ToCookie()
+			if err != nil {
+				t.Fatalf("ToCookie() error = %v", err)
+			}
+
+			got, err := json.Marshal(tt.convert(cookie))
+			if err != nil {
+				t.Fatalf("Failed to marshal cookie: %v", err)
+			}
+			var want bytes.Buffer
+			if err := json.Compact(&want, []byte(tt.input)); err != nil {
+				t.Fatalf("Failed to compact input: %v", err)
+			}
+			if string(got) != want.String() {
+				t.Errorf("round trip = %s, want %s", got, want.String())
+			}
+		})
+	}
+}
+
+func TestCookieHandler_ImportStorageState(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		importErr    error
+		wantStatus   int
+		wantResponse map[string]interface{}
+	}{
+		{
+			name:       "storageStateのCookieを保存できる",
+			body:       `{"cookies":[{"name":"sid","value":"abc","domain":".example.com","path":"/","expires":-1,"httpOnly":true,"secure":true,"sameSite":"Lax"}],"origins":[{"origin":"https://example.com","localStorage":[]}]}`,
+			wantStatus: 200,
+			wantResponse: map[string]interface{}{
+				"status": "success",
+				"count":  float64(1),
+			},
+		},
+		{
+			name:       "不正なCookieはインデックスとともに拒否する",
+			body:       `{"cookies":[{"name":"sid","value":"abc","domain":"example.com"},{"name":"","value":"x","domain":"example.com"}]}`,
+			wantStatus: 400,
+			wantResponse: map[string]interface{}{
+				"error": "Invalid cookie at index 1: name is required",
+			},
+		},
+		{
+			name:       "不正なJSON形式",
+			body:       "invalid json",
+			wantStatus: 400,
+			wantResponse: map[string]interface{}{
+				"error": "Invalid JSON format or storageState structure",
+			},
+		},
+		{
+			name:       "StoreCookiesでエラーが発生",
+			body:       `{"cookies":[{"name":"sid","value":"abc","domain":"example.com"}]}`,
+			importErr:  errors.New("import error"),
+			wantStatus: 500,
+			wantResponse: map[string]interface{}{
+				"error": "Failed to store cookies",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUsecase := &mockCookieUsecase{
+				storeCookiesFunc: func(ctx context.Context, cookies []*entity.Cookie) error {
+					return tt.importErr
+				},
+			}
+
+			app := fiber.New()
+			app.Post("/playwright/storage-state", NewCookieHandler(mockUsecase, nil).ImportStorageState)
+
+			req, _ := http.NewRequest("POST", "/playwright/storage-state", bytes.NewReader([]byte(tt.body)))
+			req.Header.Set("Content-Type", "application/json")
+			assertJSONResponse(t, app, req, tt.wantStatus, tt.wantResponse)
+		})
+	}
+}
+
+func TestCookieHandler_ExportBrowserCookies(t *testing.T) {
+	stored := []*entity.Cookie{
+		{Name: "root", Value: "1", Domain: "example.com", Path: "/"},
+		{Name: "other", Value: "2", Domain: "other.com", Path: "/", HostOnly: true},
+		{Name: "chips", Value: "3", Domain: "example.com", Path: "/", HostOnly: true, Secure: true, Partitioned: true, PartitionKey: "https://site.example"},
+	}
+	mockUsecase := &mockCookieUsecase{
+		getAllCookiesFunc: func(ctx context.Context) ([]*entity.Cookie, error) {
+			return stored, nil
+		},
+	}
+	handler := NewCookieHandler(mockUsecase, nil)
+
+	app := fiber.New()
+	app.Get("/playwright/storage-state", handler.ExportStorageState)
+	app.Get("/puppeteer/cookies", handler.ExportPuppeteerCookies)
+
+	// storageState はドメインで絞り込み、origins を空配列で返す
+	req, _ := http.NewRequest("GET", "/playwright/storage-state?domain=example.com", nil)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Failed to execute request: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	var state StorageState
+	if err := json.Unmarshal(body, &state); err != nil {
+		t.Fatalf("Failed to unmarshal response body: %v", err)
+	}
+	if len(state.Cookies) != 2 || state.Cookies[0].Name != "root" || state.Cookies[0].Domain != ".example.com" {
+		t.Errorf("storageState cookies = %s, want root and chips for example.com", body)
+	}
+	// Playwright の partitionKey は文字列
+	if len(state.Cookies) == 2 && string(state.Cookies[1].PartitionKey) != `"https://site.example"` {
+		t.Errorf("storageState partitionKey = %s, want string", state.Cookies[1].PartitionKey)
+	}
+	if state.Origins == nil {
+		t.Errorf("storageState origins = nil, want empty array")
+	}
+
+	// Puppeteer はすべてのCookieを配列で返す
+	req, _ = http.NewRequest("GET", "/puppeteer/cookies", nil)
+	resp, err = app.Test(req)
+	if err != nil {
+		t.Fatalf("Failed to execute request: %v", err)
+	}
+	body, _ = io.ReadAll(resp.Body)
+	var cookies []*BrowserCookie
+	if err := json.Unmarshal(body, &cookies); err != nil {
+		t.Fatalf("Failed to unmarshal response body: %v", err)
+	}
+	if len(cookies) != 3 || cookies[1].Domain != "other.com" || cookies[1].Expires != -1 {
+		t.Fatalf("puppeteer cookies = %s, want 3 cookies with host-only other.com", body)
+	}
+	// Puppeteer の partitionKey はオブジェクト
+	if got := string(cookies[2].PartitionKey); got != `{"sourceOrigin":"https://site.example"}` {
+		t.Errorf("puppeteer partitionKey = %s, want object", got)
+	}
+}
@@ -2,58 +2,84 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v3"
+	"github.com/takumi3488/cookiejar-server/internal/domain/entity"
+	"github.com/takumi3488/cookiejar-server/internal/domain/repository"
 	"github.com/takumi3488/cookiejar-server/internal/usecase"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
 )
 
+// CookieHandler は /jars/:jar 以下のルートではパスパラメータの jar、それ以外のルートでは既定の jar の Cookie を操作します
 type CookieHandler struct {
 	cookieUsecase usecase.CookieUsecase
+	// リクエストの検証で公開サフィックスの判定に使う（nil の場合は検証しない）
+	psl entity.PublicSuffixList
 }
 
-func NewCookieHandler(cookieUsecase usecase.CookieUsecase) *CookieHandler {
+func NewCookieHandler(cookieUsecase usecase.CookieUsecase, psl entity.PublicSuffixList) *CookieHandler {
 	return &CookieHandler{
 		cookieUsecase: cookieUsecase,
+		psl:           psl,
 	}
 }
 
 type CookieRequest struct {
-	Name     string `json:"name"`
-	Value    string `json:"value"`
-	Path     string `json:"path,omitempty"`
-	Domain   string `json:"domain,omitempty"`
-	MaxAge   int    `json:"maxAge,omitempty"`
-	Secure   bool   `json:"secure,omitempty"`
-	HttpOnly bool   `json:"httpOnly,omitempty"`
-	SameSite string `json:"sameSite,omitempty"`
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Path   string `json:"path,omitempty"`
+	Domain string `json:"domain,omitempty"`
+	// Expires と MaxAge の両方が指定された場合は MaxAge が優先される
+	Expires  time.Time `json:"expires,omitzero"`
+	MaxAge   int       `json:"maxAge,omitempty"`
+	Secure   bool      `json:"secure,omitempty"`
+	HttpOnly bool      `json:"httpOnly,omitempty"`
+	SameSite string    `json:"sameSite,omitempty"`
+	// Partitioned は Secure 属性が必要（CHIPS）
+	Partitioned bool `json:"partitioned,omitempty"`
+	// HostOnly が true の場合は Domain と完全に一致するホストにのみ送信される（Domain 属性なしの Cookie）
+	HostOnly bool `json:"hostOnly,omitempty"`
 }
 
 func (c *CookieRequest) ToCookie() *http.Cookie {
 	cookie := &http.Cookie{
-		Name:     c.Name,
-		Value:    c.Value,
-		Path:     c.Path,
-		Domain:   c.Domain,
-		MaxAge:   c.MaxAge,
-		Secure:   c.Secure,
-		HttpOnly: c.HttpOnly,
-	}
-	switch c.SameSite {
-	case "None":
+		Name:        c.Name,
+		Value:       c.Value,
+		Path:        c.Path,
+		Domain:      c.Domain,
+		Expires:     c.Expires,
+		MaxAge:      c.MaxAge,
+		Secure:      c.Secure,
+		HttpOnly:    c.HttpOnly,
+		Partitioned: c.Partitioned,
+	}
+	switch {
+	case strings.EqualFold(c.SameSite, "None"):
 		cookie.SameSite = http.SameSiteNoneMode
-	case "Lax":
+	case strings.EqualFold(c.SameSite, "Lax"):
 		cookie.SameSite = http.SameSiteLaxMode
-	case "Strict":
+	case strings.EqualFold(c.SameSite, "Strict"):
 		cookie.SameSite = http.SameSiteStrictMode
 	}
 	return cookie
 }
 
+// ToEntity は now を基準に MaxAge を Expires へ変換して entity.Cookie を生成します
+func (c *CookieRequest) ToEntity(now time.Time) *entity.Cookie {
+	cookie := entity.NewCookieAt(c.ToCookie(), now)
+	cookie.HostOnly = c.HostOnly
+	return cookie
+}
+
 func (h *CookieHandler) StoreCookies(c fiber.Ctx) error {
 	ctx := c.Context()
 	span := trace.SpanFromContext(ctx)
@@ -70,25 +96,228 @@ func (h *CookieHandler) StoreCookies(c fiber.Ctx) error {
 		})
 	}
 
-	cookies := make([]*http.Cookie, len(cookieReqs))
-	for i, req := range cookieReqs {
-		cookies[i] = req.ToCookie()
+	// partial=true の場合は検証に失敗したCookieを除いて保存する
+	partial, err := parseBoolQuery(c, "partial")
+	if err != nil {
+		return respondError(c, span, fiber.StatusBadRequest, "partial must be a boolean", err)
 	}
-
-	if err := h.cookieUsecase.StoreCookies(ctx, cookies); err != nil {
-		log.Printf("Failed to store cookies: %v", err)
-		span.RecordError(err)
-		span.SetStatus(codes.Error, "Failed to store cookies")
-		span.SetAttributes(attribute.Int("http.response.status_code", fiber.StatusInternalServerError))
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to store cookies",
+	validReqs, validationErrs := validateCookieRequests(cookieReqs, h.psl)
+	if len(validationErrs) > 0 && !partial {
+		span.SetStatus(codes.Error, "Validation failed")
+		span.SetAttributes(attribute.Int("http.response.status_code", fiber.StatusUnprocessableEntity))
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"error":  "Validation failed",
+			"errors": validationErrs,
 		})
 	}
 
+	now := time.Now()
+	cookies := make([]*entity.Cookie, len(validReqs))
+	for i, req := range validReqs {
+		cookies[i] = req.ToEntity(now)
+	}
+
+	if err := h.cookieUsecase.StoreCookies(ctx, jarParam(c), cookies); err != nil {
+		return respondStoreError(c, span, err)
+	}
+
 	span.SetStatus(codes.Ok, "Successfully stored cookies")
 	span.SetAttributes(attribute.Int("http.response.status_code", fiber.StatusOK))
-	return c.JSON(fiber.Map{
+	resp := fiber.Map{
 		"status": "success",
 		"count":  len(cookies),
+	}
+	if partial {
+		if validationErrs == nil {
+			validationErrs = []CookieValidationError{}
+		}
+		resp["errors"] = validationErrs
+	}
+	return c.JSON(resp)
+}
+
+// SetCookieRequest は生の Set-Cookie ヘッダーを取り込むリクエストです
+type SetCookieRequest struct {
+	// URL は Set-Cookie ヘッダーを受け取ったリクエストの URL
+	URL       string   `json:"url"`
+	SetCookie []string `json:"setCookie"`
+}
+
+type rejectedSetCookieResponse struct {
+	Index  int    `json:"index"`
+	Header string `json:"header"`
+	Error  string `json:"error"`
+}
+
+// StoreSetCookies は生の Set-Cookie ヘッダーを解析して保存します
+// ブラウザが拒否する Cookie は保存せず、rejected に理由とともに返します
+func (h *CookieHandler) StoreSetCookies(c fiber.Ctx) error {
+	ctx := c.Context()
+	span := trace.SpanFromContext(ctx)
+
+	var req SetCookieRequest
+	if err := json.Unmarshal(c.Body(), &req); err != nil {
+		log.Printf("Failed to parse JSON request body: %v", err)
+		return respondError(c, span, fiber.StatusBadRequest, "Invalid JSON format or request structure", err)
+	}
+
+	requestURL, err := url.Parse(req.URL)
+	if err != nil || !requestURL.IsAbs() || requestURL.Hostname() == "" {
+		return respondError(c, span, fiber.StatusBadRequest, "url must be an absolute URL", err)
+	}
+
+	result, err := h.cookieUsecase.StoreSetCookies(ctx, jarParam(c), requestURL, req.SetCookie)
+	if err != nil {
+		return respondUsecaseError(c, span, "Failed to store cookies", err)
+	}
+
+	span.SetStatus(codes.Ok, "Successfully stored cookies")
+	span.SetAttributes(attribute.Int("http.response.status_code", fiber.StatusOK))
+	return c.JSON(fiber.Map{
+		"status":   "success",
+		"count":    result.Accepted,
+		"rejected": toRejectedSetCookieResponses(result.Rejected),
+	})
+}
+
+func toRejectedSetCookieResponses(rejected []usecase.RejectedSetCookie) []rejectedSetCookieResponse {
+	result := make([]rejectedSetCookieResponse, len(rejected))
+	for i, r := range rejected {
+		result[i] = rejectedSetCookieResponse{Index: r.Index, Header: r.Header, Error: r.Err.Error()}
+	}
+	return result
+}
+
+// DeleteCookie は識別キー（name / domain / path / hostOnly / partitionKey）に一致する1つのCookieを削除します
+func (h *CookieHandler) DeleteCookie(c fiber.Ctx) error {
+	ctx := c.Context()
+	span := trace.SpanFromContext(ctx)
+
+	name := c.Query("name")
+	domain := c.Query("domain")
+	if name == "" || domain == "" {
+		return respondError(c, span, fiber.StatusBadRequest, "name and domain are required", nil)
+	}
+	hostOnly, err := parseBoolQuery(c, "hostOnly")
+	if err != nil {
+		return respondError(c, span, fiber.StatusBadRequest, "hostOnly must be a boolean", err)
+	}
+
+	cookie := &entity.Cookie{Name: name, Domain: domain, Path: c.Query("path"), HostOnly: hostOnly, PartitionKey: c.Query("partitionKey")}
+	deleted, err := h.cookieUsecase.DeleteCookie(ctx, jarParam(c), cookie.Key())
+	if err != nil {
+		return respondUsecaseError(c, span, "Failed to delete cookie", err)
+	}
+	if deleted == 0 {
+		return respondError(c, span, fiber.StatusNotFound, "Cookie not found", nil)
+	}
+
+	return respondDeleted(c, span, deleted)
+}
+
+// DeleteHostCookies は Domain がパスパラメータのホストと一致するCookieをすべて削除します
+func (h *CookieHandler) DeleteHostCookies(c fiber.Ctx) error {
+	ctx := c.Context()
+	span := trace.SpanFromContext(ctx)
+
+	host := c.Params("host")
+	if host == "" {
+		return respondError(c, span, fiber.StatusBadRequest, "host is required", nil)
+	}
+
+	deleted, err := h.cookieUsecase.DeleteCookiesByHost(ctx, jarParam(c), host)
+	if err != nil {
+		return respondUsecaseError(c, span, "Failed to delete cookies", err)
+	}
+
+	return respondDeleted(c, span, deleted)
+}
+
+// DeleteCookies はクエリパラメータ（domain / namePrefix / expired）の条件に一致するCookieを一括削除します
+func (h *CookieHandler) DeleteCookies(c fiber.Ctx) error {
+	ctx := c.Context()
+	span := trace.SpanFromContext(ctx)
+
+	expired, err := parseBoolQuery(c, "expired")
+	if err != nil {
+		return respondError(c, span, fiber.StatusBadRequest, "expired must be a boolean", err)
+	}
+	filter := repository.CookieFilter{
+		Domain:      c.Query("domain"),
+		NamePrefix:  c.Query("namePrefix"),
+		ExpiredOnly: expired,
+	}
+
+	deleted, err := h.cookieUsecase.DeleteCookies(ctx, jarParam(c), filter)
+	if errors.Is(err, usecase.ErrEmptyFilter) {
+		return respondError(c, span, fiber.StatusBadRequest, "At least one of domain, namePrefix or expired is required", err)
+	}
+	if err != nil {
+		return respondUsecaseError(c, span, "Failed to delete cookies", err)
+	}
+
+	return respondDeleted(c, span, deleted)
+}
+
+// jarParam はパスパラメータの jar の名前を返します（/jars/:jar 以外のルートでは空となり、既定の jar を使う）
+func jarParam(c fiber.Ctx) string {
+	return c.Params("jar")
+}
+
+// parseBoolQuery は真偽値のクエリパラメータを解釈します（未指定の場合は false）
+func parseBoolQuery(c fiber.Ctx, key string) (bool, error) {
+	v := c.Query(key)
+	if v == "" {
+		return false, nil
+	}
+	return strconv.ParseBool(v)
+}
+
+func respondDeleted(c fiber.Ctx, span trace.Span, deleted int) error {
+	span.SetStatus(codes.Ok, "Successfully deleted cookies")
+	span.SetAttributes(attribute.Int("http.response.status_code", fiber.StatusOK))
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"deleted": deleted,
+	})
+}
+
+// respondStoreError は StoreCookies のエラーを、保存できない Cookie（公開サフィックス・サイズの上限超過）が含まれる場合は 400、
+// それ以外は respondUsecaseError と同様にレスポンスにします
+func respondStoreError(c fiber.Ctx, span trace.Span, err error) error {
+	if errors.Is(err, entity.ErrPublicSuffix) || errors.Is(err, usecase.ErrCookieTooLarge) {
+		return respondError(c, span, fiber.StatusBadRequest, "Invalid cookie: "+err.Error(), err)
+	}
+	return respondUsecaseError(c, span, "Failed to store cookies", err)
+}
+
+// respondUsecaseError は CookieUsecase のエラーを、API キーのスコープで許可されていない操作の場合は 403、
+// jar の名前が不正な場合は 400、jar が存在しない場合は 404、それ以外は message とともに 500 のレスポンスにします
+func respondUsecaseError(c fiber.Ctx, span trace.Span, message string, err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrForbidden):
+		return respondForbidden(c, span, err)
+	case errors.Is(err, entity.ErrInvalidJarName):
+		return respondError(c, span, fiber.StatusBadRequest, "Invalid jar: "+err.Error(), err)
+	case errors.Is(err, entity.ErrJarNotFound):
+		return respondError(c, span, fiber.StatusNotFound, "Jar not found", err)
+	}
+	log.Printf("%s: %v", message, err)
+	return respondError(c, span, fiber.StatusInternalServerError, message, err)
+}
+
+// respondForbidden は API キーのスコープで許可されていない操作のエラーを 403 のレスポンスにします
+func respondForbidden(c fiber.Ctx, span trace.Span, err error) error {
+	return respondError(c, span, fiber.StatusForbidden, "Forbidden: "+err.Error(), err)
+}
+
+func respondError(c fiber.Ctx, span trace.Span, status int, message string, err error) error {
+	if err != nil {
+		span.RecordError(err)
+	}
+	span.SetStatus(codes.Error, message)
+	span.SetAttributes(attribute.Int("http.response.status_code", status))
+	return c.Status(status).JSON(fiber.Map{
+		"error": message,
 	})
 }
@@ -5,36 +5,87 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"testing"
+	"time"
 
 	"github.com/gofiber/fiber/v3"
 	"github.com/takumi3488/cookiejar-server/internal/domain/entity"
+	"github.com/takumi3488/cookiejar-server/internal/domain/repository"
+	"github.com/takumi3488/cookiejar-server/internal/infrastructure/publicsuffix"
+	"github.com/takumi3488/cookiejar-server/internal/usecase"
 )
 
 // モックユースケース
 type mockCookieUsecase struct {
-	storeCookiesFunc     func(ctx context.Context, cookies []*http.Cookie) error
+	storeCookiesFunc     func(ctx context.Context, cookies []*entity.Cookie) error
 	getAllCookiesFunc    func(ctx context.Context) ([]*entity.Cookie, error)
 	getCookiesByHostFunc func(ctx context.Context, host string) ([]*entity.Cookie, error)
+	getCookiesForURLFunc func(ctx context.Context, requestURL *url.URL) ([]*entity.Cookie, error)
+	storeSetCookiesFunc  func(ctx context.Context, requestURL *url.URL, headers []string) (*usecase.SetCookieResult, error)
+	replaySetCookiesFunc func(ctx context.Context, responses []usecase.SetCookieResponse) ([]*entity.Cookie, []*usecase.SetCookieResult)
+
+	deleteCookieFunc        func(ctx context.Context, key entity.CookieKey) (int, error)
+	deleteCookiesByHostFunc func(ctx context.Context, host string) (int, error)
+	deleteCookiesFunc       func(ctx context.Context, filter repository.CookieFilter) (int, error)
+
+	// 最後に呼び出された操作の jar
+	jar string
 }
 
-func (m *mockCookieUsecase) StoreCookies(ctx context.Context, cookies []*http.Cookie) error {
+func (m *mockCookieUsecase) StoreCookies(ctx context.Context, jar string, cookies []*entity.Cookie) error {
+	m.jar = jar
 	return m.storeCookiesFunc(ctx, cookies)
 }
 
-func (m *mockCookieUsecase) GetAllCookies(ctx context.Context) ([]*entity.Cookie, error) {
+func (m *mockCookieUsecase) StoreSetCookies(ctx context.Context, jar string, requestURL *url.URL, headers []string) (*usecase.SetCookieResult, error) {
+	m.jar = jar
+	return m.storeSetCookiesFunc(ctx, requestURL, headers)
+}
+
+func (m *mockCookieUsecase) ReplaySetCookies(ctx context.Context, responses []usecase.SetCookieResponse) ([]*entity.Cookie, []*usecase.SetCookieResult) {
+	return m.replaySetCookiesFunc(ctx, responses)
+}
+
+func (m *mockCookieUsecase) GetAllCookies(ctx context.Context, jar string) ([]*entity.Cookie, error) {
+	m.jar = jar
 	return m.getAllCookiesFunc(ctx)
 }
 
-func (m *mockCookieUsecase) GetCookiesByHost(ctx context.Context, host string) ([]*entity.Cookie, error) {
+func (m *mockCookieUsecase) GetCookiesByHost(ctx context.Context, jar, host, topLevelSite string) ([]*entity.Cookie, error) {
+	m.jar = jar
 	if m.getCookiesByHostFunc != nil {
 		return m.getCookiesByHostFunc(ctx, host)
 	}
 	return nil, nil
 }
 
+func (m *mockCookieUsecase) GetCookiesForURL(ctx context.Context, jar string, requestURL *url.URL, topLevelSite string) ([]*entity.Cookie, error) {
+	m.jar = jar
+	if m.getCookiesForURLFunc != nil {
+		return m.getCookiesForURLFunc(ctx, requestURL)
+	}
+	return nil, nil
+}
+
+func (m *mockCookieUsecase) DeleteCookie(ctx context.Context, jar string, key entity.CookieKey) (int, error) {
+	m.jar = jar
+	return m.deleteCookieFunc(ctx, key)
+}
+
+func (m *mockCookieUsecase) DeleteCookiesByHost(ctx context.Context, jar, host string) (int, error) {
+	m.jar = jar
+	return m.deleteCookiesByHostFunc(ctx, host)
+}
+
+func (m *mockCookieUsecase) DeleteCookies(ctx context.Context, jar string, filter repository.CookieFilter) (int, error) {
+	m.jar = jar
+	return m.deleteCookiesFunc(ctx, filter)
+}
+
 func TestCookieHandler_StoreCookies(t *testing.T) {
 	tests := []struct {
 		name            string
@@ -66,9 +117,9 @@ func TestCookieHandler_StoreCookies(t *testing.T) {
 		{
 			name: "複数のCookieを保存できる",
 			requestBody: []*CookieRequest{
-				{Name: "cookie1", Value: "value1"},
-				{Name: "cookie2", Value: "value2"},
-				{Name: "cookie3", Value: "value3"},
+				{Name: "cookie1", Value: "value1", Domain: "example.com"},
+				{Name: "cookie2", Value: "value2", Domain: "example.com"},
+				{Name: "cookie3", Value: "value3", Domain: "example.com"},
 			},
 			storeCookiesErr: nil,
 			wantStatus:      200,
@@ -89,7 +140,7 @@ func TestCookieHandler_StoreCookies(t *testing.T) {
 		{
 			name: "StoreCookiesでエラーが発生",
 			requestBody: []*CookieRequest{
-				{Name: "test_cookie", Value: "test_value"},
+				{Name: "test_cookie", Value: "test_value", Domain: "example.com"},
 			},
 			storeCookiesErr: errors.New("store error"),
 			wantStatus:      500,
@@ -97,6 +148,28 @@ func TestCookieHandler_StoreCookies(t *testing.T) {
 				"error": "Failed to store cookies",
 			},
 		},
+		{
+			name: "サイズの上限を超えるCookie",
+			requestBody: []*CookieRequest{
+				{Name: "test_cookie", Value: "test_value", Domain: "example.com"},
+			},
+			storeCookiesErr: fmt.Errorf("cookie at index 0: %w: 21 bytes exceeds limit of 16 bytes", usecase.ErrCookieTooLarge),
+			wantStatus:      400,
+			wantResponse: map[string]interface{}{
+				"error": "Invalid cookie: cookie at index 0: cookie too large: 21 bytes exceeds limit of 16 bytes",
+			},
+		},
+		{
+			name: "書き込みが許可されていないホストのCookie",
+			requestBody: []*CookieRequest{
+				{Name: "test_cookie", Value: "test_value", Domain: "admin.example.com"},
+			},
+			storeCookiesErr: fmt.Errorf("cookie at index 0: %w: write cookies for host %q", usecase.ErrForbidden, "admin.example.com"),
+			wantStatus:      403,
+			wantResponse: map[string]interface{}{
+				"error": `Forbidden: cookie at index 0: permission denied: write cookies for host "admin.example.com"`,
+			},
+		},
 		{
 			name:            "空のCookieリスト",
 			requestBody:     []*CookieRequest{},
@@ -113,13 +186,13 @@ func TestCookieHandler_StoreCookies(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			// モックユースケース作成
 			mockUsecase := &mockCookieUsecase{
-				storeCookiesFunc: func(ctx context.Context, cookies []*http.Cookie) error {
+				storeCookiesFunc: func(ctx context.Context, cookies []*entity.Cookie) error {
 					return tt.storeCookiesErr
 				},
 			}
 
 			// ハンドラー作成
-			handler := NewCookieHandler(mockUsecase)
+			handler := NewCookieHandler(mockUsecase, nil)
 
 			// Fiberアプリケーション作成
 			app := fiber.New()
@@ -166,6 +239,193 @@ func TestCookieHandler_StoreCookies(t *testing.T) {
 	}
 }
 
+func TestCookieHandler_StoreCookies_Validation(t *testing.T) {
+	body := `[
+		{"name": "valid", "value": "ok", "domain": "example.com"},
+		{"name": "bad;name", "value": "ok", "domain": "example.com"},
+		{"name": "ctl", "value": "a\u0001b"},
+		{"name": "samesite", "value": "ok", "domain": "example.com", "sameSite": "Relaxed"}
+	]`
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantStored int
+		wantErrors []CookieValidationError
+	}{
+		{
+			name:       "検証エラーがあれば1件も保存せず422を返す",
+			query:      "",
+			wantStatus: 422,
+			wantErrors: []CookieValidationError{
+				{Index: 1, Field: "name", Message: `name contains invalid character ';'`},
+				{Index: 2, Field: "value", Message: `value contains invalid character '\x01'`},
+				{Index: 2, Field: "domain", Message: "domain is required"},
+				{Index: 3, Field: "sameSite", Message: `sameSite must be one of "Lax", "Strict" or "None"`},
+			},
+		},
+		{
+			name:       "partial=trueなら妥当なCookieのみ保存する",
+			query:      "?partial=true",
+			wantStatus: 200,
+			wantStored: 1,
+			wantErrors: []CookieValidationError{
+				{Index: 1, Field: "name", Message: `name contains invalid character ';'`},
+				{Index: 2, Field: "value", Message: `value contains invalid character '\x01'`},
+				{Index: 2, Field: "domain", Message: "domain is required"},
+				{Index: 3, Field: "sameSite", Message: `sameSite must be one of "Lax", "Strict" or "None"`},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stored := -1
+			mockUsecase := &mockCookieUsecase{
+				storeCookiesFunc: func(ctx context.Context, cookies []*entity.Cookie) error {
+					stored = len(cookies)
+					return nil
+				},
+			}
+
+			app := fiber.New()
+			app.Post("/", NewCookieHandler(mockUsecase, nil).StoreCookies)
+
+			req, _ := http.NewRequest("POST", "/"+tt.query, bytes.NewReader([]byte(body)))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("Failed to execute request: %v", err)
+			}
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("Status code = %v, want %v", resp.StatusCode, tt.wantStatus)
+			}
+			if tt.wantStatus == 200 && stored != tt.wantStored {
+				t.Errorf("StoreCookies() len = %v, want %v", stored, tt.wantStored)
+			}
+			if tt.wantStatus != 200 && stored != -1 {
+				t.Errorf("StoreCookies() should not be called")
+			}
+
+			respBody, _ := io.ReadAll(resp.Body)
+			var got struct {
+				Errors []CookieValidationError `json:"errors"`
+			}
+			if err := json.Unmarshal(respBody, &got); err != nil {
+				t.Fatalf("Failed to unmarshal response body: %v", err)
+			}
+			if len(got.Errors) != len(tt.wantErrors) {
+				t.Fatalf("errors = %+v, want %+v", got.Errors, tt.wantErrors)
+			}
+			for i := range got.Errors {
+				if got.Errors[i] != tt.wantErrors[i] {
+					t.Errorf("errors[%d] = %+v, want %+v", i, got.Errors[i], tt.wantErrors[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCookieRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name       string
+		req        *CookieRequest
+		wantFields []string
+	}{
+		{
+			name: "妥当なCookie",
+			req:  &CookieRequest{Name: "sid", Value: `"quoted"`, Domain: ".Example.com", Path: "/app", SameSite: "none", Secure: true},
+		},
+		{
+			name:       "名前が空",
+			req:        &CookieRequest{Name: "", Value: "v", Domain: "example.com"},
+			wantFields: []string{"name"},
+		},
+		{
+			name:       "値にセミコロンと空白を含む",
+			req:        &CookieRequest{Name: "sid", Value: "a; b", Domain: "example.com"},
+			wantFields: []string{"value"},
+		},
+		{
+			name:       "ドメインにポートを含む",
+			req:        &CookieRequest{Name: "sid", Value: "v", Domain: "example.com:8080"},
+			wantFields: []string{"domain"},
+		},
+		{
+			name:       "パスが/で始まらない",
+			req:        &CookieRequest{Name: "sid", Value: "v", Domain: "example.com", Path: "app"},
+			wantFields: []string{"path"},
+		},
+		{
+			name:       "SecureなしのSameSite=NoneとPartitioned",
+			req:        &CookieRequest{Name: "sid", Value: "v", Domain: "example.com", SameSite: "None", Partitioned: true},
+			wantFields: []string{"sameSite", "partitioned"},
+		},
+		{
+			name:       "__Host-プレフィックスでhost-onlyでない",
+			req:        &CookieRequest{Name: "__Host-sid", Value: "v", Domain: "example.com", Path: "/", Secure: true},
+			wantFields: []string{"name"},
+		},
+		{
+			name: "__Host-プレフィックスの要件を満たす",
+			req:  &CookieRequest{Name: "__Host-sid", Value: "v", Domain: "example.com", Secure: true, HostOnly: true},
+		},
+		{
+			name:       "__Secure-プレフィックスでSecureなし",
+			req:        &CookieRequest{Name: "__Secure-sid", Value: "v", Domain: "example.com"},
+			wantFields: []string{"name"},
+		},
+		{
+			name:       "ICANNセクションの公開サフィックス",
+			req:        &CookieRequest{Name: "sid", Value: "v", Domain: "co.uk"},
+			wantFields: []string{"domain"},
+		},
+		{
+			name:       "プライベートセクションの公開サフィックス",
+			req:        &CookieRequest{Name: "sid", Value: "v", Domain: ".github.io"},
+			wantFields: []string{"domain"},
+		},
+		{
+			name: "公開サフィックスのhost-only Cookie",
+			req:  &CookieRequest{Name: "sid", Value: "v", Domain: "github.io", HostOnly: true},
+		},
+		{
+			name:       "nullの要素",
+			req:        nil,
+			wantFields: []string{""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := tt.req.Validate(publicsuffix.Default())
+			if len(errs) != len(tt.wantFields) {
+				t.Fatalf("Validate() = %+v, want fields %v", errs, tt.wantFields)
+			}
+			for i, err := range errs {
+				if err.Field != tt.wantFields[i] {
+					t.Errorf("Validate()[%d].Field = %v, want %v", i, err.Field, tt.wantFields[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCookieRequest_ToEntity(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	req := &CookieRequest{Name: "sid", Value: "v", Domain: "www.example.com", MaxAge: 3600, HostOnly: true}
+	got := req.ToEntity(now)
+
+	if want := now.Add(time.Hour); !got.Expires.Equal(want) {
+		t.Errorf("Expires = %v, want %v", got.Expires, want)
+	}
+	if !got.HostOnly {
+		t.Errorf("HostOnly = false, want true")
+	}
+}
+
 func TestCookieRequest_ToCookie(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -261,3 +521,369 @@ func TestCookieRequest_ToCookie(t *testing.T) {
 		})
 	}
 }
+
+func TestCookieHandler_StoreSetCookies(t *testing.T) {
+	tests := []struct {
+		name         string
+		requestBody  string
+		result       *usecase.SetCookieResult
+		storeErr     error
+		wantURL      string
+		wantStatus   int
+		wantResponse map[string]interface{}
+	}{
+		{
+			name:        "Set-Cookieヘッダーを保存できる",
+			requestBody: `{"url":"https://example.com/a/b","setCookie":["sid=abc; Path=/; Secure","lang=ja"]}`,
+			result:      &usecase.SetCookieResult{Accepted: 2},
+			wantURL:     "https://example.com/a/b",
+			wantStatus:  200,
+			wantResponse: map[string]interface{}{
+				"status": "success",
+				"count":  float64(2),
+			},
+		},
+		{
+			name:        "拒否されたヘッダーがあっても成功する",
+			requestBody: `{"url":"http://example.com/","setCookie":["sid=abc; Secure","lang=ja"]}`,
+			result: &usecase.SetCookieResult{
+				Accepted: 1,
+				Rejected: []usecase.RejectedSetCookie{{Index: 0, Header: "sid=abc; Secure", Err: entity.ErrCookieRejected}},
+			},
+			wantURL:    "http://example.com/",
+			wantStatus: 200,
+			wantResponse: map[string]interface{}{
+				"status": "success",
+				"count":  float64(1),
+			},
+		},
+		{
+			name:        "不正なJSON形式",
+			requestBody: "invalid json",
+			wantStatus:  400,
+			wantResponse: map[string]interface{}{
+				"error": "Invalid JSON format or request structure",
+			},
+		},
+		{
+			name:        "URLが絶対URLでない",
+			requestBody: `{"url":"/path","setCookie":["sid=abc"]}`,
+			wantStatus:  400,
+			wantResponse: map[string]interface{}{
+				"error": "url must be an absolute URL",
+			},
+		},
+		{
+			name:        "StoreSetCookiesでエラーが発生",
+			requestBody: `{"url":"https://example.com/","setCookie":["sid=abc"]}`,
+			storeErr:    errors.New("store error"),
+			wantURL:     "https://example.com/",
+			wantStatus:  500,
+			wantResponse: map[string]interface{}{
+				"error": "Failed to store cookies",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUsecase := &mockCookieUsecase{
+				storeSetCookiesFunc: func(ctx context.Context, requestURL *url.URL, headers []string) (*usecase.SetCookieResult, error) {
+					if requestURL.String() != tt.wantURL {
+						t.Errorf("StoreSetCookies() url = %v, want %v", requestURL, tt.wantURL)
+					}
+					return tt.result, tt.storeErr
+				},
+			}
+
+			app := fiber.New()
+			app.Post("/set-cookie", NewCookieHandler(mockUsecase, nil).StoreSetCookies)
+
+			req, _ := http.NewRequest("POST", "/set-cookie", bytes.NewReader([]byte(tt.requestBody)))
+			req.Header.Set("Content-Type", "application/json")
+			assertJSONResponse(t, app, req, tt.wantStatus, tt.wantResponse)
+		})
+	}
+}
+
+func TestCookieHandler_DeleteCookie(t *testing.T) {
+	tests := []struct {
+		name         string
+		query        string
+		deleted      int
+		deleteErr    error
+		wantKey      *entity.CookieKey
+		wantStatus   int
+		wantResponse map[string]interface{}
+	}{
+		{
+			name:       "識別キーを正規化して削除する",
+			query:      "?name=sid&domain=.Example.com&path=/admin&hostOnly=true",
+			deleted:    1,
+			wantKey:    &entity.CookieKey{Name: "sid", Domain: "example.com", Path: "/admin", HostOnly: true},
+			wantStatus: 200,
+			wantResponse: map[string]interface{}{
+				"status":  "success",
+				"deleted": float64(1),
+			},
+		},
+		{
+			name:       "pathを省略した場合はルートパス",
+			query:      "?name=sid&domain=example.com",
+			deleted:    1,
+			wantKey:    &entity.CookieKey{Name: "sid", Domain: "example.com", Path: "/"},
+			wantStatus: 200,
+			wantResponse: map[string]interface{}{
+				"deleted": float64(1),
+			},
+		},
+		{
+			name:       "存在しないCookie",
+			query:      "?name=sid&domain=example.com",
+			deleted:    0,
+			wantStatus: 404,
+			wantResponse: map[string]interface{}{
+				"error": "Cookie not found",
+			},
+		},
+		{
+			name:       "nameが未指定",
+			query:      "?domain=example.com",
+			wantStatus: 400,
+			wantResponse: map[string]interface{}{
+				"error": "name and domain are required",
+			},
+		},
+		{
+			name:       "hostOnlyが不正",
+			query:      "?name=sid&domain=example.com&hostOnly=maybe",
+			wantStatus: 400,
+			wantResponse: map[string]interface{}{
+				"error": "hostOnly must be a boolean",
+			},
+		},
+		{
+			name:       "DeleteCookieでエラーが発生",
+			query:      "?name=sid&domain=example.com",
+			deleteErr:  errors.New("delete error"),
+			wantStatus: 500,
+			wantResponse: map[string]interface{}{
+				"error": "Failed to delete cookie",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUsecase := &mockCookieUsecase{
+				deleteCookieFunc: func(ctx context.Context, key entity.CookieKey) (int, error) {
+					if tt.wantKey != nil && key != *tt.wantKey {
+						t.Errorf("DeleteCookie() key = %+v, want %+v", key, *tt.wantKey)
+					}
+					return tt.deleted, tt.deleteErr
+				},
+			}
+
+			app := fiber.New()
+			app.Delete("/cookie", NewCookieHandler(mockUsecase, nil).DeleteCookie)
+
+			req, _ := http.NewRequest("DELETE", "/cookie"+tt.query, nil)
+			assertJSONResponse(t, app, req, tt.wantStatus, tt.wantResponse)
+		})
+	}
+}
+
+func TestCookieHandler_DeleteHostCookies(t *testing.T) {
+	var gotHost string
+	mockUsecase := &mockCookieUsecase{
+		deleteCookiesByHostFunc: func(ctx context.Context, host string) (int, error) {
+			gotHost = host
+			return 3, nil
+		},
+	}
+
+	app := fiber.New()
+	app.Delete("/hosts/:host", NewCookieHandler(mockUsecase, nil).DeleteHostCookies)
+
+	req, _ := http.NewRequest("DELETE", "/hosts/example.com", nil)
+	assertJSONResponse(t, app, req, 200, map[string]interface{}{
+		"status":  "success",
+		"deleted": float64(3),
+	})
+	if gotHost != "example.com" {
+		t.Errorf("DeleteCookiesByHost() host = %v, want example.com", gotHost)
+	}
+}
+
+func TestCookieHandler_DeleteCookies(t *testing.T) {
+	tests := []struct {
+		name         string
+		query        string
+		deleteErr    error
+		wantFilter   repository.CookieFilter
+		wantStatus   int
+		wantResponse map[string]interface{}
+	}{
+		{
+			name:       "条件を指定して一括削除する",
+			query:      "?domain=example.com&namePrefix=_ga&expired=true",
+			wantFilter: repository.CookieFilter{Domain: "example.com", NamePrefix: "_ga", ExpiredOnly: true},
+			wantStatus: 200,
+			wantResponse: map[string]interface{}{
+				"status":  "success",
+				"deleted": float64(2),
+			},
+		},
+		{
+			name:       "条件なしは拒否する",
+			query:      "",
+			deleteErr:  usecase.ErrEmptyFilter,
+			wantStatus: 400,
+			wantResponse: map[string]interface{}{
+				"error": "At least one of domain, namePrefix or expired is required",
+			},
+		},
+		{
+			name:       "expiredが不正",
+			query:      "?expired=yes",
+			wantStatus: 400,
+			wantResponse: map[string]interface{}{
+				"error": "expired must be a boolean",
+			},
+		},
+		{
+			name:       "削除が許可されていない",
+			query:      "?expired=true",
+			wantFilter: repository.CookieFilter{ExpiredOnly: true},
+			deleteErr:  fmt.Errorf("%w: delete cookies for host %q", usecase.ErrForbidden, ""),
+			wantStatus: 403,
+			wantResponse: map[string]interface{}{
+				"error": `Forbidden: permission denied: delete cookies for host ""`,
+			},
+		},
+		{
+			name:       "DeleteCookiesでエラーが発生",
+			query:      "?expired=true",
+			wantFilter: repository.CookieFilter{ExpiredOnly: true},
+			deleteErr:  errors.New("delete error"),
+			wantStatus: 500,
+			wantResponse: map[string]interface{}{
+				"error": "Failed to delete cookies",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUsecase := &mockCookieUsecase{
+				deleteCookiesFunc: func(ctx context.Context, filter repository.CookieFilter) (int, error) {
+					if filter != tt.wantFilter {
+						t.Errorf("DeleteCookies() filter = %+v, want %+v", filter, tt.wantFilter)
+					}
+					return 2, tt.deleteErr
+				},
+			}
+
+			app := fiber.New()
+			app.Delete("/cookies", NewCookieHandler(mockUsecase, nil).DeleteCookies)
+
+			req, _ := http.NewRequest("DELETE", "/cookies"+tt.query, nil)
+			assertJSONResponse(t, app, req, tt.wantStatus, tt.wantResponse)
+		})
+	}
+}
+
+func TestCookieHandler_Jar(t *testing.T) {
+	tests := []struct {
+		name         string
+		path         string
+		storeErr     error
+		wantJar      string
+		wantStatus   int
+		wantResponse map[string]interface{}
+	}{
+		{
+			name:       "jar を指定しない場合は空の jar 名を渡す",
+			path:       "/",
+			wantJar:    "",
+			wantStatus: 200,
+		},
+		{
+			name:       "パスパラメータの jar を渡す",
+			path:       "/jars/bot-1/",
+			wantJar:    "bot-1",
+			wantStatus: 200,
+		},
+		{
+			name:       "存在しない jar",
+			path:       "/jars/missing/",
+			storeErr:   fmt.Errorf("%w: %q", entity.ErrJarNotFound, "missing"),
+			wantJar:    "missing",
+			wantStatus: 404,
+			wantResponse: map[string]interface{}{
+				"error": "Jar not found",
+			},
+		},
+		{
+			name:       "不正な jar の名前",
+			path:       "/jars/-bot/",
+			storeErr:   fmt.Errorf("%w: %q", entity.ErrInvalidJarName, "-bot"),
+			wantJar:    "-bot",
+			wantStatus: 400,
+			wantResponse: map[string]interface{}{
+				"error": `Invalid jar: invalid jar name: "-bot"`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUsecase := &mockCookieUsecase{
+				storeCookiesFunc: func(ctx context.Context, cookies []*entity.Cookie) error {
+					return tt.storeErr
+				},
+			}
+
+			h := NewCookieHandler(mockUsecase, nil)
+			app := fiber.New()
+			app.Post("/", h.StoreCookies)
+			app.Group("/jars/:jar").Post("/", h.StoreCookies)
+
+			body, _ := json.Marshal([]*CookieRequest{{Name: "sid", Value: "v", Domain: "example.com"}})
+			req, _ := http.NewRequest("POST", tt.path, bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			assertJSONResponse(t, app, req, tt.wantStatus, tt.wantResponse)
+			if mockUsecase.jar != tt.wantJar {
+				t.Errorf("StoreCookies() jar = %q, want %q", mockUsecase.jar, tt.wantJar)
+			}
+		})
+	}
+}
+
+// assertJSONResponse はリクエストを実行し、ステータスコードとJSONレスポンスの値を確認します
+func assertJSONResponse(t *testing.T, app *fiber.App, req *http.Request, wantStatus int, wantResponse map[string]interface{}) {
+	t.Helper()
+
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Failed to execute request: %v", err)
+	}
+
+	if resp.StatusCode != wantStatus {
+		t.Errorf("Status code = %v, want %v", resp.StatusCode, wantStatus)
+	}
+
+	respBody, _ := io.ReadAll(resp.Body)
+	var gotResponse map[string]interface{}
+	if err := json.Unmarshal(respBody, &gotResponse); err != nil {
+		t.Fatalf("Failed to unmarshal response body: %v", err)
+	}
+
+	for key, wantValue := range wantResponse {
+		if gotValue, ok := gotResponse[key]; !ok {
+			t.Errorf("Response missing key %v", key)
+		} else if gotValue != wantValue {
+			t.Errorf("Response[%v] = %v, want %v", key, gotValue, wantValue)
+		}
+	}
+}
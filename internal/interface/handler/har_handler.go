@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/takumi3488/cookiejar-server/internal/usecase"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// HAR は HAR 1.2 のうち Cookie の取り込みに必要な項目です
+type HAR struct {
+	Log struct {
+		Entries []HAREntry `json:"entries"`
+	} `json:"log"`
+}
+
+type HAREntry struct {
+	StartedDateTime string `json:"startedDateTime"`
+	Request         struct {
+		URL string `json:"url"`
+	} `json:"request"`
+	Response struct {
+		Headers []HARHeader `json:"headers"`
+	} `json:"response"`
+}
+
+type HARHeader struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// SetCookieHeaders はレスポンスの Set-Cookie ヘッダーを返します
+// 1つのヘッダーに改行区切りで複数の値を格納するブラウザにも対応します
+func (e *HAREntry) SetCookieHeaders() []string {
+	var headers []string
+	for _, h := range e.Response.Headers {
+		if !strings.EqualFold(h.Name, "Set-Cookie") {
+			continue
+		}
+		for _, v := range strings.Split(h.Value, "\n") {
+			if v = strings.TrimSpace(v); v != "" {
+				headers = append(headers, v)
+			}
+		}
+	}
+	return headers
+}
+
+type harEntryReport struct {
+	// Entry は HAR の log.entries 内のインデックス
+	Entry    int                         `json:"entry"`
+	URL      string                      `json:"url"`
+	Accepted int                         `json:"accepted"`
+	Rejected []rejectedSetCookieResponse `json:"rejected"`
+}
+
+// harResponse は Set-Cookie ヘッダーを含む HAR エントリーです
+type harResponse struct {
+	entry      int
+	startedAt  time.Time
+	requestURL *url.URL
+	headers    []string
+}
+
+// ImportHAR は HAR 1.2 のレスポンスの Set-Cookie ヘッダーを時系列順に適用し、最終的な Cookie を StoreCookies で保存します
+func (h *CookieHandler) ImportHAR(c fiber.Ctx) error {
+	ctx := c.Context()
+	span := trace.SpanFromContext(ctx)
+
+	var har HAR
+	if err := json.Unmarshal(c.Body(), &har); err != nil {
+		log.Printf("Failed to parse JSON request body: %v", err)
+		return respondError(c, span, fiber.StatusBadRequest, "Invalid JSON format or HAR structure", err)
+	}
+
+	var responses []harResponse
+	for i, entry := range har.Log.Entries {
+		headers := entry.SetCookieHeaders()
+		if len(headers) == 0 {
+			continue
+		}
+		startedAt, err := time.Parse(time.RFC3339Nano, entry.StartedDateTime)
+		if err != nil {
+			return respondError(c, span, fiber.StatusBadRequest, fmt.Sprintf("Invalid startedDateTime at entry %d", i), err)
+		}
+		// URL が不正なエントリーは Cookie ごとに拒否される
+		requestURL, err := url.Parse(entry.Request.URL)
+		if err != nil {
+			requestURL = &url.URL{}
+		}
+		responses = append(responses, harResponse{entry: i, startedAt: startedAt, requestURL: requestURL, headers: headers})
+	}
+
+	// ブラウザが受信した順に適用する
+	slices.SortStableFunc(responses, func(a, b harResponse) int {
+		return a.startedAt.Compare(b.startedAt)
+	})
+
+	replay := make([]usecase.SetCookieResponse, len(responses))
+	for i, r := range responses {
+		replay[i] = usecase.SetCookieResponse{URL: r.requestURL, ReceivedAt: r.startedAt, Headers: r.headers}
+	}
+	// 適用後の状態は他の取り込みと同じく StoreCookies で保存し、検証と上限の適用を共通にする
+	cookies, results := h.cookieUsecase.ReplaySetCookies(ctx, replay)
+	if err := h.cookieUsecase.StoreCookies(ctx, jarParam(c), cookies); err != nil {
+		return respondUsecaseError(c, span, "Failed to store cookies", err)
+	}
+
+	accepted := 0
+	reports := make([]harEntryReport, len(responses))
+	for i, r := range responses {
+		accepted += results[i].Accepted
+		reports[i] = harEntryReport{
+			Entry:    r.entry,
+			URL:      r.requestURL.Redacted(),
+			Accepted: results[i].Accepted,
+			Rejected: toRejectedSetCookieResponses(results[i].Rejected),
+		}
+	}
+
+	span.SetStatus(codes.Ok, "Successfully imported HAR")
+	span.SetAttributes(
+		attribute.Int("cookie.count", accepted),
+		attribute.Int("http.response.status_code", fiber.StatusOK),
+	)
+	return c.JSON(fiber.Map{
+		"status":  "success",
+		"count":   accepted,
+		"entries": reports,
+	})
+}
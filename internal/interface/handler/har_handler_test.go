@@ -0,0 +1,196 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/takumi3488/cookiejar-server/internal/domain/entity"
+	"github.com/takumi3488/cookiejar-server/internal/usecase"
+)
+
+const testHAR = `{
+  "log": {
+    "version": "1.2",
+    "entries": [
+      {
+        "startedDateTime": "2025-01-01T00:00:10.000Z",
+        "request": {"method": "GET", "url": "https://www.example.com/logout"},
+        "response": {"status": 200, "headers": [{"name": "set-cookie", "value": "sid=; Max-Age=0"}]}
+      },
+      {
+        "startedDateTime": "2025-01-01T00:00:00.000Z",
+        "request": {"method": "POST", "url": "https://www.example.com/login"},
+        "response": {"status": 302, "headers": [
+          {"name": "Content-Type", "value": "text/html"},
+          {"name": "Set-Cookie", "value": "sid=abc; Secure\nlang=ja; Domain=other.com"}
+        ]}
+      },
+      {
+        "startedDateTime": "2025-01-01T00:00:05.000Z",
+        "request": {"method": "GET", "url": "https://www.example.com/"},
+        "response": {"status": 200, "headers": [{"name": "Content-Type", "value": "text/html"}]}
+      }
+    ]
+  }
+}`
+
+func TestHAREntry_SetCookieHeaders(t *testing.T) {
+	var entry HAREntry
+	entry.Response.Headers = []HARHeader{
+		{Name: "set-cookie", Value: "a=1"},
+		{Name: "Content-Type", Value: "text/html"},
+		{Name: "Set-Cookie", Value: "b=2\n\nc=3"},
+	}
+
+	got := entry.SetCookieHeaders()
+	want := []string{"a=1", "b=2", "c=3"}
+	if len(got) != len(want) {
+		t.Fatalf("SetCookieHeaders() = %v, want %v", got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Errorf("SetCookieHeaders()[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestCookieHandler_ImportHAR(t *testing.T) {
+	var responses []usecase.SetCookieResponse
+	var stored []*entity.Cookie
+	replayed := []*entity.Cookie{{Name: "sid", Value: "", Domain: "www.example.com", Path: "/", HostOnly: true, Expires: time.Unix(0, 0)}}
+	mockUsecase := &mockCookieUsecase{
+		replaySetCookiesFunc: func(ctx context.Context, r []usecase.SetCookieResponse) ([]*entity.Cookie, []*usecase.SetCookieResult) {
+			responses = r
+			return replayed, []*usecase.SetCookieResult{
+				{Accepted: 1, Rejected: []usecase.RejectedSetCookie{{Index: 1, Header: "lang=ja; Domain=other.com", Err: entity.ErrCookieRejected}}},
+				{Accepted: 1},
+			}
+		},
+		storeCookiesFunc: func(ctx context.Context, cookies []*entity.Cookie) error {
+			stored = cookies
+			return nil
+		},
+	}
+
+	app := fiber.New()
+	app.Post("/jars/:jar/har", NewCookieHandler(mockUsecase, nil).ImportHAR)
+
+	req, _ := http.NewRequest("POST", "/jars/bot-1/har", bytes.NewReader([]byte(testHAR)))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Failed to execute request: %v", err)
+	}
+	if resp.StatusCode != 200 {
+		t.Fatalf("Status code = %v, want 200", resp.StatusCode)
+	}
+
+	// Set-Cookie を含むエントリーのみを時系列順に適用する
+	if len(responses) != 2 {
+		t.Fatalf("ReplaySetCookies() responses len = %v, want 2", len(responses))
+	}
+	if responses[0].URL.Path != "/login" || len(responses[0].Headers) != 2 {
+		t.Errorf("responses[0] = %+v, want login with 2 headers", responses[0])
+	}
+	if want := time.Date(2025, 1, 1, 0, 0, 10, 0, time.UTC); responses[1].URL.Path != "/logout" || !responses[1].ReceivedAt.Equal(want) {
+		t.Errorf("responses[1] = %+v, want logout at %v", responses[1], want)
+	}
+
+	// 適用後の状態は StoreCookies でパスの jar に保存する
+	if len(stored) != 1 || stored[0] != replayed[0] {
+		t.Errorf("StoreCookies() cookies = %+v, want replayed cookies %+v", stored, replayed)
+	}
+	if mockUsecase.jar != "bot-1" {
+		t.Errorf("StoreCookies() jar = %q, want %q", mockUsecase.jar, "bot-1")
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	var got struct {
+		Count   int              `json:"count"`
+		Entries []harEntryReport `json:"entries"`
+	}
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatalf("Failed to unmarshal response body: %v", err)
+	}
+	if got.Count != 2 {
+		t.Errorf("count = %v, want 2", got.Count)
+	}
+	if len(got.Entries) != 2 || got.Entries[0].Entry != 1 || got.Entries[1].Entry != 0 {
+		t.Fatalf("entries = %s, want entries 1 and 0", body)
+	}
+	if len(got.Entries[0].Rejected) != 1 || got.Entries[0].Rejected[0].Index != 1 {
+		t.Errorf("entries[0].rejected = %+v, want index 1", got.Entries[0].Rejected)
+	}
+}
+
+func TestCookieHandler_ImportHAR_Error(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		storeErr     error
+		wantStatus   int
+		wantResponse map[string]interface{}
+	}{
+		{
+			name:       "不正なJSON形式",
+			body:       "invalid json",
+			wantStatus: 400,
+			wantResponse: map[string]interface{}{
+				"error": "Invalid JSON format or HAR structure",
+			},
+		},
+		{
+			name:       "startedDateTimeが不正",
+			body:       `{"log":{"entries":[{"startedDateTime":"yesterday","request":{"url":"https://example.com/"},"response":{"headers":[{"name":"Set-Cookie","value":"a=1"}]}}]}}`,
+			wantStatus: 400,
+			wantResponse: map[string]interface{}{
+				"error": "Invalid startedDateTime at entry 0",
+			},
+		},
+		{
+			name:       "StoreCookiesでエラーが発生",
+			body:       testHAR,
+			storeErr:   errors.New("store error"),
+			wantStatus: 500,
+			wantResponse: map[string]interface{}{
+				"error": "Failed to store cookies",
+			},
+		},
+		{
+			name:       "存在しないjar",
+			body:       testHAR,
+			storeErr:   entity.ErrJarNotFound,
+			wantStatus: 404,
+			wantResponse: map[string]interface{}{
+				"error": "Jar not found",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUsecase := &mockCookieUsecase{
+				replaySetCookiesFunc: func(ctx context.Context, r []usecase.SetCookieResponse) ([]*entity.Cookie, []*usecase.SetCookieResult) {
+					return nil, make([]*usecase.SetCookieResult, len(r))
+				},
+				storeCookiesFunc: func(ctx context.Context, cookies []*entity.Cookie) error {
+					return tt.storeErr
+				},
+			}
+
+			app := fiber.New()
+			app.Post("/har", NewCookieHandler(mockUsecase, nil).ImportHAR)
+
+			req, _ := http.NewRequest("POST", "/har", bytes.NewReader([]byte(tt.body)))
+			req.Header.Set("Content-Type", "application/json")
+			assertJSONResponse(t, app, req, tt.wantStatus, tt.wantResponse)
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/takumi3488/cookiejar-server/internal/domain/entity"
+	"github.com/takumi3488/cookiejar-server/internal/usecase"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type JarHandler struct {
+	jarUsecase usecase.JarUsecase
+}
+
+func NewJarHandler(jarUsecase usecase.JarUsecase) *JarHandler {
+	return &JarHandler{
+		jarUsecase: jarUsecase,
+	}
+}
+
+// JarRequest は jar の作成・複製のリクエストです
+type JarRequest struct {
+	Name string `json:"name"`
+}
+
+type JarResponse struct {
+	Name        string    `json:"name"`
+	CreatedAt   time.Time `json:"createdAt"`
+	CookieCount int       `json:"cookieCount"`
+}
+
+func toJarResponse(jar *entity.Jar) *JarResponse {
+	return &JarResponse{
+		Name:        jar.Name,
+		CreatedAt:   jar.CreatedAt,
+		CookieCount: jar.CookieCount,
+	}
+}
+
+// ListJars はすべての jar を Cookie の数とともに返します
+func (h *JarHandler) ListJars(c fiber.Ctx) error {
+	ctx := c.Context()
+	span := trace.SpanFromContext(ctx)
+
+	jars, err := h.jarUsecase.ListJars(ctx)
+	if err != nil {
+		return respondJarError(c, span, "Failed to list jars", err)
+	}
+
+	result := make([]*JarResponse, len(jars))
+	for i, jar := range jars {
+		result[i] = toJarResponse(jar)
+	}
+
+	span.SetStatus(codes.Ok, "Successfully listed jars")
+	span.SetAttributes(attribute.Int("http.response.status_code", fiber.StatusOK))
+	return c.JSON(result)
+}
+
+// CreateJar はリクエストボディの名前で空の jar を作成します
+func (h *JarHandler) CreateJar(c fiber.Ctx) error {
+	ctx := c.Context()
+	span := trace.SpanFromContext(ctx)
+
+	var req JarRequest
+	if err := json.Unmarshal(c.Body(), &req); err != nil {
+		log.Printf("Failed to parse JSON request body: %v", err)
+		return respondError(c, span, fiber.StatusBadRequest, "Invalid JSON format or request structure", err)
+	}
+
+	jar, err := h.jarUsecase.CreateJar(ctx, req.Name)
+	if err != nil {
+		return respondJarError(c, span, "Failed to create jar", err)
+	}
+
+	span.SetStatus(codes.Ok, "Successfully created jar")
+	span.SetAttributes(attribute.Int("http.response.status_code", fiber.StatusCreated))
+	return c.Status(fiber.StatusCreated).JSON(toJarResponse(jar))
+}
+
+// CloneJar はパスパラメータの jar の Cookie をすべて複製した、リクエストボディの名前の jar を作成します
+func (h *JarHandler) CloneJar(c fiber.Ctx) error {
+	ctx := c.Context()
+	span := trace.SpanFromContext(ctx)
+
+	var req JarRequest
+	if err := json.Unmarshal(c.Body(), &req); err != nil {
+		log.Printf("Failed to parse JSON request body: %v", err)
+		return respondError(c, span, fiber.StatusBadRequest, "Invalid JSON format or request structure", err)
+	}
+
+	jar, err := h.jarUsecase.CloneJar(ctx, c.Params("jar"), req.Name)
+	if err != nil {
+		return respondJarError(c, span, "Failed to clone jar", err)
+	}
+
+	span.SetStatus(codes.Ok, "Successfully cloned jar")
+	span.SetAttributes(attribute.Int("http.response.status_code", fiber.StatusCreated))
+	return c.Status(fiber.StatusCreated).JSON(toJarResponse(jar))
+}
+
+// DeleteJar はパスパラメータの jar とその Cookie をすべて削除します
+func (h *JarHandler) DeleteJar(c fiber.Ctx) error {
+	ctx := c.Context()
+	span := trace.SpanFromContext(ctx)
+
+	if err := h.jarUsecase.DeleteJar(ctx, c.Params("jar")); err != nil {
+		return respondJarError(c, span, "Failed to delete jar", err)
+	}
+
+	span.SetStatus(codes.Ok, "Successfully deleted jar")
+	span.SetAttributes(attribute.Int("http.response.status_code", fiber.StatusOK))
+	return c.JSON(fiber.Map{
+		"status": "success",
+	})
+}
+
+// respondJarError は JarUsecase のエラーを、同じ名前の jar が存在する場合は 409、既定の jar を削除しようとした場合は 400、
+// それ以外は respondUsecaseError と同様にレスポンスにします
+func respondJarError(c fiber.Ctx, span trace.Span, message string, err error) error {
+	switch {
+	case errors.Is(err, entity.ErrJarExists):
+		return respondError(c, span, fiber.StatusConflict, "Jar already exists", err)
+	case errors.Is(err, usecase.ErrDeleteDefaultJar):
+		return respondError(c, span, fiber.StatusBadRequest, "The default jar cannot be deleted", err)
+	}
+	return respondUsecaseError(c, span, message, err)
+}
@@ -0,0 +1,216 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/takumi3488/cookiejar-server/internal/domain/entity"
+	"github.com/takumi3488/cookiejar-server/internal/usecase"
+)
+
+// モックユースケース
+type mockJarUsecase struct {
+	createJarFunc func(ctx context.Context, name string) (*entity.Jar, error)
+	listJarsFunc  func(ctx context.Context) ([]*entity.Jar, error)
+	cloneJarFunc  func(ctx context.Context, src, dst string) (*entity.Jar, error)
+	deleteJarFunc func(ctx context.Context, name string) error
+}
+
+func (m *mockJarUsecase) CreateJar(ctx context.Context, name string) (*entity.Jar, error) {
+	return m.createJarFunc(ctx, name)
+}
+
+func (m *mockJarUsecase) ListJars(ctx context.Context) ([]*entity.Jar, error) {
+	return m.listJarsFunc(ctx)
+}
+
+func (m *mockJarUsecase) CloneJar(ctx context.Context, src, dst string) (*entity.Jar, error) {
+	return m.cloneJarFunc(ctx, src, dst)
+}
+
+func (m *mockJarUsecase) DeleteJar(ctx context.Context, name string) error {
+	return m.deleteJarFunc(ctx, name)
+}
+
+func newJarTestApp(uc usecase.JarUsecase) *fiber.App {
+	h := NewJarHandler(uc)
+	app := fiber.New()
+	app.Get("/jars", h.ListJars)
+	app.Post("/jars", h.CreateJar)
+	app.Post("/jars/:jar/clone", h.CloneJar)
+	app.Delete("/jars/:jar", h.DeleteJar)
+	return app
+}
+
+func TestJarHandler_CreateJar(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		createErr    error
+		wantStatus   int
+		wantResponse map[string]interface{}
+	}{
+		{
+			name:       "jar を作成できる",
+			body:       `{"name":"bot-1"}`,
+			wantStatus: 201,
+			wantResponse: map[string]interface{}{
+				"name":        "bot-1",
+				"cookieCount": float64(0),
+			},
+		},
+		{
+			name:       "同じ名前の jar が存在する",
+			body:       `{"name":"bot-1"}`,
+			createErr:  fmt.Errorf("%w: %q", entity.ErrJarExists, "bot-1"),
+			wantStatus: 409,
+			wantResponse: map[string]interface{}{
+				"error": "Jar already exists",
+			},
+		},
+		{
+			name:       "不正な jar の名前",
+			body:       `{"name":""}`,
+			createErr:  fmt.Errorf("%w: %q", entity.ErrInvalidJarName, ""),
+			wantStatus: 400,
+		},
+		{
+			name:       "全ホストへの書き込みが許可されていない API キー",
+			body:       `{"name":"bot-1"}`,
+			createErr:  fmt.Errorf("%w: write cookies for host %q", usecase.ErrForbidden, "*"),
+			wantStatus: 403,
+		},
+		{
+			name:       "不正なJSON形式",
+			body:       `invalid`,
+			wantStatus: 400,
+			wantResponse: map[string]interface{}{
+				"error": "Invalid JSON format or request structure",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newJarTestApp(&mockJarUsecase{
+				createJarFunc: func(ctx context.Context, name string) (*entity.Jar, error) {
+					if tt.createErr != nil {
+						return nil, tt.createErr
+					}
+					return &entity.Jar{Name: name}, nil
+				},
+			})
+
+			req, _ := http.NewRequest("POST", "/jars", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			assertJSONResponse(t, app, req, tt.wantStatus, tt.wantResponse)
+		})
+	}
+}
+
+func TestJarHandler_CloneJar(t *testing.T) {
+	var gotSrc, gotDst string
+	app := newJarTestApp(&mockJarUsecase{
+		cloneJarFunc: func(ctx context.Context, src, dst string) (*entity.Jar, error) {
+			gotSrc, gotDst = src, dst
+			if src == "missing" {
+				return nil, fmt.Errorf("%w: %q", entity.ErrJarNotFound, src)
+			}
+			return &entity.Jar{Name: dst, CookieCount: 5}, nil
+		},
+	})
+
+	req, _ := http.NewRequest("POST", "/jars/bot-1/clone", bytes.NewBufferString(`{"name":"bot-2"}`))
+	req.Header.Set("Content-Type", "application/json")
+	assertJSONResponse(t, app, req, 201, map[string]interface{}{
+		"name":        "bot-2",
+		"cookieCount": float64(5),
+	})
+	if gotSrc != "bot-1" || gotDst != "bot-2" {
+		t.Errorf("CloneJar() src, dst = %v, %v, want bot-1, bot-2", gotSrc, gotDst)
+	}
+
+	req, _ = http.NewRequest("POST", "/jars/missing/clone", bytes.NewBufferString(`{"name":"bot-2"}`))
+	req.Header.Set("Content-Type", "application/json")
+	assertJSONResponse(t, app, req, 404, map[string]interface{}{
+		"error": "Jar not found",
+	})
+}
+
+func TestJarHandler_DeleteJar(t *testing.T) {
+	tests := []struct {
+		name       string
+		jar        string
+		deleteErr  error
+		wantStatus int
+	}{
+		{
+			name:       "jar を削除できる",
+			jar:        "bot-1",
+			wantStatus: 200,
+		},
+		{
+			name:       "既定の jar は削除できない",
+			jar:        entity.DefaultJar,
+			deleteErr:  usecase.ErrDeleteDefaultJar,
+			wantStatus: 400,
+		},
+		{
+			name:       "存在しない jar",
+			jar:        "missing",
+			deleteErr:  fmt.Errorf("%w: %q", entity.ErrJarNotFound, "missing"),
+			wantStatus: 404,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotJar string
+			app := newJarTestApp(&mockJarUsecase{
+				deleteJarFunc: func(ctx context.Context, name string) error {
+					gotJar = name
+					return tt.deleteErr
+				},
+			})
+
+			req, _ := http.NewRequest("DELETE", "/jars/"+tt.jar, nil)
+			assertJSONResponse(t, app, req, tt.wantStatus, nil)
+			if gotJar != tt.jar {
+				t.Errorf("DeleteJar() jar = %v, want %v", gotJar, tt.jar)
+			}
+		})
+	}
+}
+
+func TestJarHandler_ListJars(t *testing.T) {
+	app := newJarTestApp(&mockJarUsecase{
+		listJarsFunc: func(ctx context.Context) ([]*entity.Jar, error) {
+			return []*entity.Jar{
+				{Name: "bot-1", CookieCount: 2},
+				{Name: entity.DefaultJar, CookieCount: 10},
+			}, nil
+		},
+	})
+
+	req, _ := http.NewRequest("GET", "/jars", nil)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Failed to execute request: %v", err)
+	}
+	if resp.StatusCode != 200 {
+		t.Fatalf("Status code = %v, want 200", resp.StatusCode)
+	}
+
+	var got []JarResponse
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatalf("Failed to decode response body: %v", err)
+	}
+	if len(got) != 2 || got[0].Name != "bot-1" || got[0].CookieCount != 2 || got[1].Name != entity.DefaultJar {
+		t.Errorf("ListJars() = %+v", got)
+	}
+}
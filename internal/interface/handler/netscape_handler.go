@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/takumi3488/cookiejar-server/internal/domain/entity"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ImportNetscapeCookies は Netscape 形式（cookies.txt）のリクエストボディを読み込んで保存します
+func (h *CookieHandler) ImportNetscapeCookies(c fiber.Ctx) error {
+	ctx := c.Context()
+	span := trace.SpanFromContext(ctx)
+
+	cookies, err := entity.ParseNetscapeCookies(bytes.NewReader(c.Body()))
+	if err != nil {
+		var parseErr *entity.NetscapeParseError
+		if errors.As(err, &parseErr) {
+			return respondError(c, span, fiber.StatusBadRequest, "Invalid cookies.txt: "+parseErr.Error(), err)
+		}
+		return respondError(c, span, fiber.StatusBadRequest, "Invalid cookies.txt", err)
+	}
+
+	if err := h.cookieUsecase.StoreCookies(ctx, jarParam(c), cookies); err != nil {
+		return respondStoreError(c, span, err)
+	}
+
+	span.SetStatus(codes.Ok, "Successfully imported cookies")
+	span.SetAttributes(attribute.Int("http.response.status_code", fiber.StatusOK))
+	return c.JSON(fiber.Map{
+		"status": "success",
+		"count":  len(cookies),
+	})
+}
+
+// ExportNetscapeCookies は保存されているCookieを Netscape 形式（cookies.txt）で返します
+// host を指定した場合はそのホストに送信されるCookie、domain を指定した場合はそのドメインとサブドメインのCookie、
+// どちらも指定しない場合はすべてのCookieを返します
+func (h *CookieHandler) ExportNetscapeCookies(c fiber.Ctx) error {
+	ctx := c.Context()
+	span := trace.SpanFromContext(ctx)
+
+	filter, err := parseExportFilter(c)
+	if err != nil {
+		return respondError(c, span, fiber.StatusBadRequest, err.Error(), err)
+	}
+
+	cookies, err := h.cookieUsecase.GetAllCookies(ctx, jarParam(c))
+	if err != nil {
+		return respondUsecaseError(c, span, "Failed to get cookies", err)
+	}
+	exported := filter.apply(cookies)
+
+	var buf bytes.Buffer
+	if err := entity.WriteNetscapeCookies(&buf, exported); err != nil {
+		return respondError(c, span, fiber.StatusInternalServerError, "Failed to export cookies", err)
+	}
+
+	span.SetStatus(codes.Ok, "Successfully exported cookies")
+	span.SetAttributes(
+		attribute.Int("cookie.count", len(exported)),
+		attribute.Int("http.response.status_code", fiber.StatusOK),
+	)
+	c.Set(fiber.HeaderContentType, fiber.MIMETextPlainCharsetUTF8)
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="cookies.txt"`)
+	return c.Send(buf.Bytes())
+}
+
+// exportFilter はエクスポートするCookieの絞り込み条件です
+type exportFilter struct {
+	// host はそのホストに送信されるCookie（親ドメインのCookieを含む）
+	host string
+	// domain はそのドメインとサブドメインのCookie
+	domain string
+}
+
+// parseExportFilter はクエリパラメータ host / domain から絞り込み条件を読み取ります
+func parseExportFilter(c fiber.Ctx) (exportFilter, error) {
+	filter := exportFilter{
+		host:   entity.CanonicalizeHost(c.Query("host")),
+		domain: entity.CanonicalizeHost(strings.TrimPrefix(c.Query("domain"), ".")),
+	}
+	if filter.host != "" && filter.domain != "" {
+		return exportFilter{}, errors.New("host and domain cannot be specified together")
+	}
+	return filter, nil
+}
+
+func (f exportFilter) apply(cookies []*entity.Cookie) []*entity.Cookie {
+	result := make([]*entity.Cookie, 0, len(cookies))
+	for _, cookie := range cookies {
+		switch {
+		case f.host != "" && !cookie.MatchesHost(f.host):
+		case f.domain != "" && !entity.DomainMatch(cookie.CanonicalDomain(), f.domain):
+		default:
+			result = append(result, cookie)
+		}
+	}
+	return result
+}
@@ -0,0 +1,165 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/takumi3488/cookiejar-server/internal/domain/entity"
+)
+
+func TestCookieHandler_ImportNetscapeCookies(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		importErr    error
+		wantCount    int
+		wantStatus   int
+		wantResponse map[string]interface{}
+	}{
+		{
+			name: "cookies.txtを読み込んで保存できる",
+			body: "# Netscape HTTP Cookie File\n" +
+				".example.com\tTRUE\t/\tTRUE\t0\tsid\tabc\n" +
+				"#HttpOnly_www.example.com\tFALSE\t/\tFALSE\t0\tlang\tja\n",
+			wantCount:  2,
+			wantStatus: 200,
+			wantResponse: map[string]interface{}{
+				"status": "success",
+				"count":  float64(2),
+			},
+		},
+		{
+			name:       "不正な行があれば行番号とともに拒否する",
+			body:       "example.com\tFALSE\t/\n",
+			wantStatus: 400,
+			wantResponse: map[string]interface{}{
+				"error": "Invalid cookies.txt: line 1: expected 7 tab-separated fields, got 3",
+			},
+		},
+		{
+			name:       "公開サフィックスのCookieは拒否する",
+			body:       ".com\tTRUE\t/\tTRUE\t0\tsid\tabc\n",
+			importErr:  fmt.Errorf("cookie at index 0: %w: %q", entity.ErrPublicSuffix, "com"),
+			wantCount:  1,
+			wantStatus: 400,
+			wantResponse: map[string]interface{}{
+				"error": `Invalid cookie: cookie at index 0: cookie domain is a public suffix: "com"`,
+			},
+		},
+		{
+			name:       "StoreCookiesでエラーが発生",
+			body:       ".example.com\tTRUE\t/\tTRUE\t0\tsid\tabc\n",
+			importErr:  errors.New("import error"),
+			wantCount:  1,
+			wantStatus: 500,
+			wantResponse: map[string]interface{}{
+				"error": "Failed to store cookies",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUsecase := &mockCookieUsecase{
+				storeCookiesFunc: func(ctx context.Context, cookies []*entity.Cookie) error {
+					if len(cookies) != tt.wantCount {
+						t.Errorf("StoreCookies() len = %v, want %v", len(cookies), tt.wantCount)
+					}
+					return tt.importErr
+				},
+			}
+
+			app := fiber.New()
+			app.Post("/cookies.txt", NewCookieHandler(mockUsecase, nil).ImportNetscapeCookies)
+
+			req, _ := http.NewRequest("POST", "/cookies.txt", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "text/plain")
+			assertJSONResponse(t, app, req, tt.wantStatus, tt.wantResponse)
+		})
+	}
+}
+
+func TestCookieHandler_ExportNetscapeCookies(t *testing.T) {
+	stored := []*entity.Cookie{
+		{Name: "root", Value: "1", Domain: "example.com", Path: "/"},
+		{Name: "www", Value: "2", Domain: "www.example.com", Path: "/", HostOnly: true},
+		{Name: "api", Value: "3", Domain: "api.example.com", Path: "/", HostOnly: true},
+		{Name: "other", Value: "4", Domain: "other.com", Path: "/"},
+	}
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantNames  []string
+	}{
+		{
+			name:       "条件なしはすべてのCookieを書き出す",
+			query:      "",
+			wantStatus: 200,
+			wantNames:  []string{"root", "www", "api", "other"},
+		},
+		{
+			name:       "hostを指定するとそのホストに送信されるCookieを書き出す",
+			query:      "?host=www.example.com",
+			wantStatus: 200,
+			wantNames:  []string{"root", "www"},
+		},
+		{
+			name:       "domainを指定するとサブドメインを含めて書き出す",
+			query:      "?domain=.example.com",
+			wantStatus: 200,
+			wantNames:  []string{"root", "www", "api"},
+		},
+		{
+			name:       "hostとdomainの同時指定は拒否する",
+			query:      "?host=www.example.com&domain=example.com",
+			wantStatus: 400,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUsecase := &mockCookieUsecase{
+				getAllCookiesFunc: func(ctx context.Context) ([]*entity.Cookie, error) {
+					return stored, nil
+				},
+			}
+
+			app := fiber.New()
+			app.Get("/cookies.txt", NewCookieHandler(mockUsecase, nil).ExportNetscapeCookies)
+
+			req, _ := http.NewRequest("GET", "/cookies.txt"+tt.query, nil)
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("Failed to execute request: %v", err)
+			}
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("Status code = %v, want %v", resp.StatusCode, tt.wantStatus)
+			}
+			if tt.wantStatus != 200 {
+				return
+			}
+
+			body, _ := io.ReadAll(resp.Body)
+			cookies, err := entity.ParseNetscapeCookies(strings.NewReader(string(body)))
+			if err != nil {
+				t.Fatalf("ParseNetscapeCookies() error = %v", err)
+			}
+			if len(cookies) != len(tt.wantNames) {
+				t.Fatalf("exported len = %v, want %v", len(cookies), len(tt.wantNames))
+			}
+			for i, cookie := range cookies {
+				if cookie.Name != tt.wantNames[i] {
+					t.Errorf("exported[%d].Name = %v, want %v", i, cookie.Name, tt.wantNames[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"strings"
+
+	"github.com/takumi3488/cookiejar-server/internal/domain/entity"
+)
+
+// CookieValidationError はリクエストボディ内の1つの Cookie の検証エラーです
+type CookieValidationError struct {
+	// Index はリクエストボディの配列内のインデックス
+	Index   int    `json:"index"`
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Validate は CookieRequest を RFC 6265 の文法と保存時の規則で検証し、問題のあるフィールドごとにエラーを返します
+// psl が nil の場合は公開サフィックスの検証を行いません
+func (c *CookieRequest) Validate(psl entity.PublicSuffixList) []CookieValidationError {
+	if c == nil {
+		return []CookieValidationError{{Message: "cookie must be an object"}}
+	}
+
+	var errs []CookieValidationError
+	add := func(field string, err error) {
+		if err != nil {
+			errs = append(errs, CookieValidationError{Field: field, Message: err.Error()})
+		}
+	}
+	add("name", entity.ValidateCookieName(c.Name))
+	add("value", entity.ValidateCookieValue(c.Value))
+	add("domain", entity.ValidateCookieDomain(c.Domain))
+	add("path", entity.ValidateCookiePath(c.Path))
+	cookie := &entity.Cookie{Name: c.Name, Domain: c.Domain, Path: c.Path, Secure: c.Secure, HostOnly: c.HostOnly}
+	// 公開サフィックスを Domain 属性とする Cookie は保存できない
+	add("domain", cookie.ValidatePublicSuffix(psl))
+	// __Secure- / __Host- プレフィックスの要件
+	add("name", cookie.ValidatePrefix())
+
+	sameSite := c.SameSite
+	switch {
+	case sameSite == "", strings.EqualFold(sameSite, "Lax"), strings.EqualFold(sameSite, "Strict"):
+	case strings.EqualFold(sameSite, "None"):
+		if !c.Secure {
+			errs = append(errs, CookieValidationError{Field: "sameSite", Message: "sameSite None requires secure"})
+		}
+	default:
+		errs = append(errs, CookieValidationError{Field: "sameSite", Message: `sameSite must be one of "Lax", "Strict" or "None"`})
+	}
+	if c.Partitioned && !c.Secure {
+		errs = append(errs, CookieValidationError{Field: "partitioned", Message: "partitioned requires secure"})
+	}
+	return errs
+}
+
+// validateCookieRequests はすべての CookieRequest を検証し、妥当なものと検証エラーを返します
+func validateCookieRequests(reqs []*CookieRequest, psl entity.PublicSuffixList) ([]*CookieRequest, []CookieValidationError) {
+	valid := make([]*CookieRequest, 0, len(reqs))
+	var errs []CookieValidationError
+	for i, req := range reqs {
+		reqErrs := req.Validate(psl)
+		if len(reqErrs) == 0 {
+			valid = append(valid, req)
+			continue
+		}
+		for _, err := range reqErrs {
+			err.Index = i
+			errs = append(errs, err)
+		}
+	}
+	return valid, errs
+}
@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/takumi3488/cookiejar-server/internal/usecase"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// APIKeyHeader は Authorization ヘッダーの代わりに API キーを指定できるヘッダーです
+const APIKeyHeader = "X-API-Key"
+
+// APIKeyAuth は Authorization: Bearer ヘッダーまたは X-API-Key ヘッダーの API キーを検証する Fiber v3 用の middleware を返します
+// 認証に成功した場合は API キーをコンテキストに設定します（usecase.APIKeyFromContext で取得）
+// tokenAuth が nil でない場合は JWT のベアラートークンも受け付け、トークンのクレームをコンテキストに設定します（usecase.TokenClaimsFromContext で取得）
+func APIKeyAuth(auth usecase.AuthUsecase, tokenAuth usecase.TokenAuthUsecase) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		span := trace.SpanFromContext(c.Context())
+
+		credential := extractAPIKey(c.Get(fiber.HeaderAuthorization), c.Get(APIKeyHeader))
+		ctx, err := authenticate(c.Context(), auth, tokenAuth, credential)
+		if errors.Is(err, usecase.ErrUnauthenticated) {
+			span.SetStatus(codes.Error, "Unauthenticated")
+			if isToken(tokenAuth, credential) {
+				// RFC 6750 §3.1
+				c.Set(fiber.HeaderWWWAuthenticate, `Bearer error="invalid_token"`)
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+					"error": "Invalid or expired token",
+				})
+			}
+			c.Set(fiber.HeaderWWWAuthenticate, "Bearer")
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": "Missing or invalid API key",
+			})
+		}
+		if err != nil {
+			log.Printf("Failed to authenticate request: %v", err)
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "Failed to authenticate")
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to authenticate",
+			})
+		}
+
+		c.SetContext(ctx)
+		return c.Next()
+	}
+}
+
+// authenticate は API キーまたは JWT を検証し、認証した主体を設定したコンテキストを返します
+func authenticate(ctx context.Context, auth usecase.AuthUsecase, tokenAuth usecase.TokenAuthUsecase, credential string) (context.Context, error) {
+	span := trace.SpanFromContext(ctx)
+
+	if isToken(tokenAuth, credential) {
+		claims, err := tokenAuth.AuthenticateToken(ctx, credential)
+		if err != nil {
+			return nil, err
+		}
+		span.SetAttributes(attribute.String("auth.token_subject", claims.Subject))
+		return usecase.ContextWithTokenClaims(ctx, claims), nil
+	}
+
+	apiKey, err := auth.Authenticate(ctx, credential)
+	if err != nil {
+		return nil, err
+	}
+	span.SetAttributes(attribute.Int64("auth.api_key_id", apiKey.ID))
+	return usecase.ContextWithAPIKey(ctx, apiKey), nil
+}
+
+// isToken は JWT の検証が有効で、credential が JWT の形式（"." で区切られた3つの部分）であるかを返します
+// API キーは "." を含まないため、形式で区別できます
+func isToken(tokenAuth usecase.TokenAuthUsecase, credential string) bool {
+	return tokenAuth != nil && strings.Count(credential, ".") == 2
+}
+
+// extractAPIKey は Authorization ヘッダー（Bearer スキーム）を優先して API キーを取り出します
+func extractAPIKey(authorization, apiKeyHeader string) string {
+	scheme, token, ok := strings.Cut(strings.TrimSpace(authorization), " ")
+	if ok && strings.EqualFold(scheme, "Bearer") {
+		return strings.TrimSpace(token)
+	}
+	return strings.TrimSpace(apiKeyHeader)
+}
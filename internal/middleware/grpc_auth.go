@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+
+	"github.com/takumi3488/cookiejar-server/internal/usecase"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// healthCheckMethodPrefix は認証なしで呼び出せるヘルスチェックサービスのメソッドです
+const healthCheckMethodPrefix = "/grpc.health.v1.Health/"
+
+// UnaryAPIKeyAuth は metadata の authorization（Bearer スキーム）または x-api-key の API キーを検証する gRPC unary interceptor を返します
+// tokenAuth が nil でない場合は JWT のベアラートークンも受け付けます
+func UnaryAPIKeyAuth(auth usecase.AuthUsecase, tokenAuth usecase.TokenAuthUsecase) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if strings.HasPrefix(info.FullMethod, healthCheckMethodPrefix) {
+			return handler(ctx, req)
+		}
+		ctx, err := authenticateGRPC(ctx, auth, tokenAuth)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamAPIKeyAuth は UnaryAPIKeyAuth と同様に API キーまたは JWT を検証する gRPC stream interceptor を返します
+func StreamAPIKeyAuth(auth usecase.AuthUsecase, tokenAuth usecase.TokenAuthUsecase) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if strings.HasPrefix(info.FullMethod, healthCheckMethodPrefix) {
+			return handler(srv, ss)
+		}
+		ctx, err := authenticateGRPC(ss.Context(), auth, tokenAuth)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authenticatedStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// authenticatedStream は認証済みの主体やクライアント証明書のサブジェクトを設定したコンテキストを返す ServerStream です
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
+
+// authenticateGRPC は metadata の API キーまたは JWT を検証し、認証した主体を設定したコンテキストを返します
+func authenticateGRPC(ctx context.Context, auth usecase.AuthUsecase, tokenAuth usecase.TokenAuthUsecase) (context.Context, error) {
+	span := trace.SpanFromContext(ctx)
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	credential := extractAPIKey(firstMetadata(md, "authorization"), firstMetadata(md, "x-api-key"))
+	ctx, err := authenticate(ctx, auth, tokenAuth, credential)
+	if errors.Is(err, usecase.ErrUnauthenticated) {
+		span.SetStatus(otelcodes.Error, "Unauthenticated")
+		if isToken(tokenAuth, credential) {
+			return nil, status.Error(codes.Unauthenticated, "invalid or expired token")
+		}
+		return nil, status.Error(codes.Unauthenticated, "missing or invalid api key")
+	}
+	if err != nil {
+		log.Printf("Failed to authenticate request: %v", err)
+		span.RecordError(err)
+		span.SetStatus(otelcodes.Error, "Failed to authenticate")
+		return nil, status.Error(codes.Internal, "failed to authenticate")
+	}
+	return ctx, nil
+}
+
+func firstMetadata(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
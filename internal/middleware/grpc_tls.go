@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/takumi3488/cookiejar-server/internal/usecase"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// UnaryClientCertIdentity はクライアント証明書（mTLS）のサブジェクトをリクエストの識別情報としてコンテキストと span に設定する gRPC unary interceptor を返します
+// クライアント証明書の検証は TLS ハンドシェイクで行われるため、この interceptor はリクエストを拒否しません
+func UnaryClientCertIdentity() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(withClientCertIdentity(ctx), req)
+	}
+}
+
+// StreamClientCertIdentity は UnaryClientCertIdentity と同様にクライアント証明書のサブジェクトを設定する gRPC stream interceptor を返します
+func StreamClientCertIdentity() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &authenticatedStream{ServerStream: ss, ctx: withClientCertIdentity(ss.Context())})
+	}
+}
+
+// withClientCertIdentity はピアのクライアント証明書のサブジェクトを設定したコンテキストを返します（クライアント証明書がない場合は ctx をそのまま返す）
+func withClientCertIdentity(ctx context.Context) context.Context {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ctx
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.PeerCertificates) == 0 {
+		return ctx
+	}
+
+	subject := tlsInfo.State.PeerCertificates[0].Subject.String()
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("tls.client.subject", subject))
+	return usecase.ContextWithClientSubject(ctx, subject)
+}
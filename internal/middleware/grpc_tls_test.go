@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"testing"
+
+	"github.com/takumi3488/cookiejar-server/internal/usecase"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+func TestUnaryClientCertIdentity(t *testing.T) {
+	clientCert := &x509.Certificate{Subject: pkix.Name{CommonName: "bot-1", Organization: []string{"example"}}}
+
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		wantSubject string
+		wantOK      bool
+	}{
+		{
+			name: "クライアント証明書のサブジェクトを設定",
+			ctx: peer.NewContext(context.Background(), &peer.Peer{
+				AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{PeerCertificates: []*x509.Certificate{clientCert}}},
+			}),
+			wantSubject: "CN=bot-1,O=example",
+			wantOK:      true,
+		},
+		{
+			name: "クライアント証明書がない TLS 接続",
+			ctx: peer.NewContext(context.Background(), &peer.Peer{
+				AuthInfo: credentials.TLSInfo{},
+			}),
+		},
+		{
+			name: "平文の接続",
+			ctx:  peer.NewContext(context.Background(), &peer.Peer{}),
+		},
+		{
+			name: "ピア情報なし",
+			ctx:  context.Background(),
+		},
+	}
+
+	interceptor := UnaryClientCertIdentity()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			_, err := interceptor(tt.ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/cookiejar.v1.CookieService/GetCookies"}, func(ctx context.Context, req any) (any, error) {
+				called = true
+				subject, ok := usecase.ClientSubjectFromContext(ctx)
+				if ok != tt.wantOK || subject != tt.wantSubject {
+					t.Errorf("ClientSubjectFromContext() = %q, %v, want %q, %v", subject, ok, tt.wantSubject, tt.wantOK)
+				}
+				return nil, nil
+			})
+			if err != nil {
+				t.Errorf("interceptor() error = %v", err)
+			}
+			if !called {
+				t.Error("handler was not called")
+			}
+		})
+	}
+}
+
+func TestStreamClientCertIdentity(t *testing.T) {
+	ctx := peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{PeerCertificates: []*x509.Certificate{
+			{Subject: pkix.Name{CommonName: "bot-1"}},
+		}}},
+	})
+	interceptor := StreamClientCertIdentity()
+	info := &grpc.StreamServerInfo{FullMethod: "/cookiejar.v1.CookieService/WatchCookies"}
+
+	err := interceptor(nil, &testServerStream{ctx: ctx}, info, func(srv any, ss grpc.ServerStream) error {
+		if subject, ok := usecase.ClientSubjectFromContext(ss.Context()); !ok || subject != "CN=bot-1" {
+			t.Errorf("ClientSubjectFromContext() = %q, %v, want %q, true", subject, ok, "CN=bot-1")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Errorf("interceptor() error = %v", err)
+	}
+}
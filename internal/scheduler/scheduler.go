@@ -0,0 +1,31 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Job は定期実行される処理です
+type Job func(ctx context.Context) error
+
+// Run は ctx がキャンセルされるまで interval ごとに job を実行します
+// 起動直後に1回実行し、前回の実行が終わってから次の間隔を数えます
+func Run(ctx context.Context, name string, interval time.Duration, job Job) {
+	log.Printf("Scheduler started: job=%s interval=%s", name, interval)
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Printf("Scheduler stopped: job=%s", name)
+			return
+		case <-timer.C:
+			if err := job(ctx); err != nil {
+				log.Printf("Scheduled job %s failed: %v", name, err)
+			}
+			timer.Reset(interval)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/takumi3488/cookiejar-server/internal/domain/entity"
+	"github.com/takumi3488/cookiejar-server/internal/domain/repository"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	// ErrUnauthenticated は API キーが指定されていない、または無効であることを示すエラーです
+	ErrUnauthenticated = errors.New("unauthenticated")
+	// ErrAPIKeyNotFound は無効化する API キーが存在しない（または無効化済みである）ことを示すエラーです
+	ErrAPIKeyNotFound = errors.New("api key not found")
+	// ErrEmptyAPIKeyName は API キーの名前が指定されていないことを示すエラーです
+	ErrEmptyAPIKeyName = errors.New("api key name is required")
+	// ErrEmptyScopes は API キーのスコープが1つも指定されていないことを示すエラーです
+	ErrEmptyScopes = errors.New("at least one scope is required")
+)
+
+type AuthUsecase interface {
+	// Authenticate は API キーを検証し、有効な場合はその API キーを返します
+	Authenticate(ctx context.Context, key string) (*entity.APIKey, error)
+	// CreateAPIKey は scopes の操作を許可する新しい API キーを発行します（キー自体は保存しないため、この戻り値でのみ取得できます）
+	CreateAPIKey(ctx context.Context, name string, scopes []entity.Scope) (*entity.APIKey, string, error)
+	ListAPIKeys(ctx context.Context) ([]*entity.APIKey, error)
+	RevokeAPIKey(ctx context.Context, id int64) error
+}
+
+type authUsecase struct {
+	apiKeyRepo repository.APIKeyRepository
+	now        func() time.Time
+}
+
+func NewAuthUsecase(apiKeyRepo repository.APIKeyRepository) AuthUsecase {
+	return &authUsecase{
+		apiKeyRepo: apiKeyRepo,
+		now:        time.Now,
+	}
+}
+
+func (u *authUsecase) Authenticate(ctx context.Context, key string) (*entity.APIKey, error) {
+	tracer := otel.Tracer("cookiejar-server/usecase")
+	ctx, span := tracer.Start(ctx, "Authenticate", trace.WithSpanKind(trace.SpanKindInternal))
+	defer span.End()
+
+	if key == "" {
+		span.SetStatus(codes.Error, "API key is missing")
+		return nil, fmt.Errorf("%w: api key is missing", ErrUnauthenticated)
+	}
+
+	apiKey, err := u.apiKeyRepo.FindByHash(ctx, entity.HashAPIKey(key))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to find API key")
+		return nil, err
+	}
+	if apiKey == nil || apiKey.IsRevoked() {
+		span.SetStatus(codes.Error, "API key is invalid")
+		return nil, fmt.Errorf("%w: invalid api key", ErrUnauthenticated)
+	}
+
+	span.SetAttributes(attribute.Int64("auth.api_key_id", apiKey.ID))
+	span.SetStatus(codes.Ok, "Successfully authenticated")
+	return apiKey, nil
+}
+
+func (u *authUsecase) CreateAPIKey(ctx context.Context, name string, scopes []entity.Scope) (*entity.APIKey, string, error) {
+	tracer := otel.Tracer("cookiejar-server/usecase")
+	ctx, span := tracer.Start(ctx, "CreateAPIKey", trace.WithSpanKind(trace.SpanKindInternal))
+	defer span.End()
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		span.SetStatus(codes.Error, "API key name is empty")
+		return nil, "", ErrEmptyAPIKeyName
+	}
+	if len(scopes) == 0 {
+		span.SetStatus(codes.Error, "API key scopes are empty")
+		return nil, "", ErrEmptyScopes
+	}
+	span.SetAttributes(attribute.StringSlice("auth.scopes", entity.ScopeStrings(scopes)))
+
+	key, err := entity.GenerateAPIKey()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to generate API key")
+		return nil, "", err
+	}
+
+	apiKey, err := u.apiKeyRepo.Create(ctx, name, entity.APIKeyDisplayPrefix(key), entity.HashAPIKey(key), scopes, u.now())
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to create API key")
+		return nil, "", err
+	}
+
+	span.SetAttributes(attribute.Int64("auth.api_key_id", apiKey.ID))
+	span.SetStatus(codes.Ok, "Successfully created API key")
+	return apiKey, key, nil
+}
+
+func (u *authUsecase) ListAPIKeys(ctx context.Context) ([]*entity.APIKey, error) {
+	tracer := otel.Tracer("cookiejar-server/usecase")
+	ctx, span := tracer.Start(ctx, "ListAPIKeys", trace.WithSpanKind(trace.SpanKindInternal))
+	defer span.End()
+
+	apiKeys, err := u.apiKeyRepo.FindAll(ctx)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to list API keys")
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.Int("auth.api_key_count", len(apiKeys)))
+	span.SetStatus(codes.Ok, "Successfully listed API keys")
+	return apiKeys, nil
+}
+
+func (u *authUsecase) RevokeAPIKey(ctx context.Context, id int64) error {
+	tracer := otel.Tracer("cookiejar-server/usecase")
+	ctx, span := tracer.Start(ctx, "RevokeAPIKey", trace.WithSpanKind(trace.SpanKindInternal))
+	defer span.End()
+
+	span.SetAttributes(attribute.Int64("auth.api_key_id", id))
+
+	revoked, err := u.apiKeyRepo.Revoke(ctx, id, u.now())
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to revoke API key")
+		return err
+	}
+	if revoked == 0 {
+		span.SetStatus(codes.Error, "API key not found")
+		return fmt.Errorf("%w: id %d", ErrAPIKeyNotFound, id)
+	}
+
+	span.SetStatus(codes.Ok, "Successfully revoked API key")
+	return nil
+}
+
+type apiKeyContextKey struct{}
+
+// ContextWithAPIKey は認証済みの API キーを設定したコンテキストを返します
+func ContextWithAPIKey(ctx context.Context, apiKey *entity.APIKey) context.Context {
+	return context.WithValue(ctx, apiKeyContextKey{}, apiKey)
+}
+
+// APIKeyFromContext はコンテキストから認証済みの API キーを取得します
+func APIKeyFromContext(ctx context.Context) (*entity.APIKey, bool) {
+	apiKey, ok := ctx.Value(apiKeyContextKey{}).(*entity.APIKey)
+	return apiKey, ok
+}
+
+type clientSubjectContextKey struct{}
+
+// ContextWithClientSubject は検証済みのクライアント証明書のサブジェクト（mTLS）を設定したコンテキストを返します
+func ContextWithClientSubject(ctx context.Context, subject string) context.Context {
+	return context.WithValue(ctx, clientSubjectContextKey{}, subject)
+}
+
+// ClientSubjectFromContext はコンテキストからクライアント証明書のサブジェクトを取得します
+func ClientSubjectFromContext(ctx context.Context) (string, bool) {
+	subject, ok := ctx.Value(clientSubjectContextKey{}).(string)
+	return subject, ok
+}
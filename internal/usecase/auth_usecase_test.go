@@ -0,0 +1,197 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/takumi3488/cookiejar-server/internal/domain/entity"
+)
+
+// モックリポジトリ（ハッシュをキーとしたメモリ上の API キー）
+type mockAPIKeyRepository struct {
+	keys   map[string]*entity.APIKey
+	nextID int64
+	err    error
+}
+
+func newMockAPIKeyRepository() *mockAPIKeyRepository {
+	return &mockAPIKeyRepository{keys: make(map[string]*entity.APIKey)}
+}
+
+func (m *mockAPIKeyRepository) Create(ctx context.Context, name, prefix, keyHash string, scopes []entity.Scope, createdAt time.Time) (*entity.APIKey, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.nextID++
+	apiKey := &entity.APIKey{ID: m.nextID, Name: name, Prefix: prefix, CreatedAt: createdAt, Scopes: scopes}
+	m.keys[keyHash] = apiKey
+	return apiKey, nil
+}
+
+func (m *mockAPIKeyRepository) FindByHash(ctx context.Context, keyHash string) (*entity.APIKey, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.keys[keyHash], nil
+}
+
+func (m *mockAPIKeyRepository) FindAll(ctx context.Context) ([]*entity.APIKey, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	result := make([]*entity.APIKey, 0, len(m.keys))
+	for _, apiKey := range m.keys {
+		result = append(result, apiKey)
+	}
+	return result, nil
+}
+
+func (m *mockAPIKeyRepository) Revoke(ctx context.Context, id int64, revokedAt time.Time) (int, error) {
+	if m.err != nil {
+		return 0, m.err
+	}
+	for _, apiKey := range m.keys {
+		if apiKey.ID == id && !apiKey.IsRevoked() {
+			apiKey.RevokedAt = revokedAt
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+func TestAuthUsecase_Authenticate(t *testing.T) {
+	repo := newMockAPIKeyRepository()
+	uc := NewAuthUsecase(repo)
+	ctx := context.Background()
+
+	active, activeKey, err := uc.CreateAPIKey(ctx, "active", entity.FullAccessScopes())
+	if err != nil {
+		t.Fatalf("CreateAPIKey() error = %v", err)
+	}
+	revoked, revokedKey, err := uc.CreateAPIKey(ctx, "revoked", entity.FullAccessScopes())
+	if err != nil {
+		t.Fatalf("CreateAPIKey() error = %v", err)
+	}
+	if err := uc.RevokeAPIKey(ctx, revoked.ID); err != nil {
+		t.Fatalf("RevokeAPIKey() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		key     string
+		repoErr error
+		wantID  int64
+		wantErr error
+	}{
+		{
+			name:   "有効なキー",
+			key:    activeKey,
+			wantID: active.ID,
+		},
+		{
+			name:    "キーが指定されていない",
+			key:     "",
+			wantErr: ErrUnauthenticated,
+		},
+		{
+			name:    "存在しないキー",
+			key:     "cjk_unknown",
+			wantErr: ErrUnauthenticated,
+		},
+		{
+			name:    "無効化されたキー",
+			key:     revokedKey,
+			wantErr: ErrUnauthenticated,
+		},
+		{
+			name:    "リポジトリのエラーは認証エラーとして扱わない",
+			key:     activeKey,
+			repoErr: errors.New("database error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo.err = tt.repoErr
+			defer func() { repo.err = nil }()
+
+			got, err := uc.Authenticate(ctx, tt.key)
+			if tt.repoErr != nil {
+				if err == nil || errors.Is(err, ErrUnauthenticated) {
+					t.Errorf("Authenticate() error = %v, want repository error", err)
+				}
+				return
+			}
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Authenticate() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Authenticate() error = %v", err)
+			}
+			if got.ID != tt.wantID {
+				t.Errorf("Authenticate() ID = %v, want %v", got.ID, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestAuthUsecase_CreateAPIKey(t *testing.T) {
+	repo := newMockAPIKeyRepository()
+	uc := NewAuthUsecase(repo)
+
+	scopes := []entity.Scope{{Permission: entity.PermissionRead, HostPattern: "*.example.com"}}
+	apiKey, key, err := uc.CreateAPIKey(context.Background(), "  ci  ", scopes)
+	if err != nil {
+		t.Fatalf("CreateAPIKey() error = %v", err)
+	}
+	if apiKey.Name != "ci" {
+		t.Errorf("Name = %q, want %q", apiKey.Name, "ci")
+	}
+	if apiKey.Prefix != entity.APIKeyDisplayPrefix(key) {
+		t.Errorf("Prefix = %q, want %q", apiKey.Prefix, entity.APIKeyDisplayPrefix(key))
+	}
+	// キー自体は保存せず、ハッシュのみを保存する
+	if _, ok := repo.keys[entity.HashAPIKey(key)]; !ok {
+		t.Error("CreateAPIKey() did not store the key hash")
+	}
+	if _, ok := repo.keys[key]; ok {
+		t.Error("CreateAPIKey() stored the plain key")
+	}
+
+	if len(apiKey.Scopes) != 1 || apiKey.Scopes[0] != scopes[0] {
+		t.Errorf("Scopes = %v, want %v", apiKey.Scopes, scopes)
+	}
+
+	if _, _, err := uc.CreateAPIKey(context.Background(), " ", scopes); !errors.Is(err, ErrEmptyAPIKeyName) {
+		t.Errorf("CreateAPIKey() error = %v, want %v", err, ErrEmptyAPIKeyName)
+	}
+	if _, _, err := uc.CreateAPIKey(context.Background(), "ci", nil); !errors.Is(err, ErrEmptyScopes) {
+		t.Errorf("CreateAPIKey() error = %v, want %v", err, ErrEmptyScopes)
+	}
+}
+
+func TestAuthUsecase_RevokeAPIKey(t *testing.T) {
+	repo := newMockAPIKeyRepository()
+	uc := NewAuthUsecase(repo)
+	ctx := context.Background()
+
+	apiKey, _, err := uc.CreateAPIKey(ctx, "ci", entity.FullAccessScopes())
+	if err != nil {
+		t.Fatalf("CreateAPIKey() error = %v", err)
+	}
+
+	if err := uc.RevokeAPIKey(ctx, apiKey.ID); err != nil {
+		t.Fatalf("RevokeAPIKey() error = %v", err)
+	}
+	// 無効化済みのキーと存在しないキーは ErrAPIKeyNotFound
+	for _, id := range []int64{apiKey.ID, 999} {
+		if err := uc.RevokeAPIKey(ctx, id); !errors.Is(err, ErrAPIKeyNotFound) {
+			t.Errorf("RevokeAPIKey(%d) error = %v, want %v", id, err, ErrAPIKeyNotFound)
+		}
+	}
+}
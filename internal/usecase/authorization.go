@@ -0,0 +1,138 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/takumi3488/cookiejar-server/internal/domain/entity"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ErrForbidden は API キーやトークンのスコープで許可されていない操作であることを示すエラーです
+var ErrForbidden = errors.New("permission denied")
+
+// principal はスコープで操作を制限される、認証済みのリクエストの主体（*entity.APIKey または *entity.TokenClaims）です
+type principal interface {
+	Allows(permission entity.Permission, host string) bool
+	AllowsJar(jar string) bool
+}
+
+// principalFromContext はコンテキストから認証済みの主体を取得します
+// 設定されていない場合（認証が無効な場合や Purger などの内部からの呼び出し）は ok = false を返します
+func principalFromContext(ctx context.Context) (principal, bool) {
+	if claims, ok := TokenClaimsFromContext(ctx); ok {
+		return claims, true
+	}
+	if apiKey, ok := APIKeyFromContext(ctx); ok {
+		return apiKey, true
+	}
+	return nil, false
+}
+
+// authorize はコンテキストの主体が host に対する permission の操作を許可されているかを検証します
+// 主体が設定されていない場合はすべての操作を許可します
+// 拒否した場合は span に authorization.denied イベントを記録します
+func authorize(ctx context.Context, span trace.Span, permission entity.Permission, host string) error {
+	p, ok := principalFromContext(ctx)
+	if !ok || p.Allows(permission, host) {
+		return nil
+	}
+
+	log.Printf("Denied %s access to host=%q for %s", permission, host, describePrincipal(p))
+	span.AddEvent("authorization.denied", trace.WithAttributes(append(identityAttributes(ctx, p),
+		attribute.String("auth.permission", string(permission)),
+		attribute.String("auth.host", host),
+	)...))
+	return fmt.Errorf("%w: %s cookies for host %q", ErrForbidden, permission, host)
+}
+
+// authorizeJar はコンテキストの主体が jar の操作を許可されているかを検証します
+// 主体が設定されていない場合はすべての jar を許可します
+// 拒否した場合は span に authorization.denied イベントを記録します
+func authorizeJar(ctx context.Context, span trace.Span, jar string) error {
+	p, ok := principalFromContext(ctx)
+	if !ok || p.AllowsJar(jar) {
+		return nil
+	}
+
+	log.Printf("Denied access to jar=%q for %s", jar, describePrincipal(p))
+	span.AddEvent("authorization.denied", trace.WithAttributes(append(identityAttributes(ctx, p),
+		attribute.String("auth.jar", jar),
+	)...))
+	return fmt.Errorf("%w: jar %q", ErrForbidden, jar)
+}
+
+// filterReadable はコンテキストの主体で読み取りが許可されていないドメインの Cookie を除外します
+// 除外した場合は span に authorization.denied イベントを記録します
+func filterReadable(ctx context.Context, span trace.Span, cookies []*entity.Cookie) []*entity.Cookie {
+	p, ok := principalFromContext(ctx)
+	if !ok {
+		return cookies
+	}
+
+	result := make([]*entity.Cookie, 0, len(cookies))
+	for _, cookie := range cookies {
+		if p.Allows(entity.PermissionRead, cookie.CanonicalDomain()) {
+			result = append(result, cookie)
+		}
+	}
+	if denied := len(cookies) - len(result); denied > 0 {
+		span.AddEvent("authorization.denied", trace.WithAttributes(append(identityAttributes(ctx, p),
+			attribute.String("auth.permission", string(entity.PermissionRead)),
+			attribute.Int("auth.denied_cookie_count", denied),
+		)...))
+	}
+	return result
+}
+
+// filterJars はコンテキストの主体で操作が許可されていない jar を除外します
+func filterJars(ctx context.Context, jars []*entity.Jar) []*entity.Jar {
+	p, ok := principalFromContext(ctx)
+	if !ok {
+		return jars
+	}
+
+	result := make([]*entity.Jar, 0, len(jars))
+	for _, jar := range jars {
+		if p.AllowsJar(jar.Name) {
+			result = append(result, jar)
+		}
+	}
+	return result
+}
+
+// identityAttributes はリクエストの識別情報（API キーまたはトークンの主体と、mTLS の場合はクライアント証明書のサブジェクト）を監査用の属性として返します
+func identityAttributes(ctx context.Context, p principal) []attribute.KeyValue {
+	var attrs []attribute.KeyValue
+	switch p := p.(type) {
+	case *entity.APIKey:
+		attrs = append(attrs,
+			attribute.Int64("auth.api_key_id", p.ID),
+			attribute.String("auth.api_key_name", p.Name),
+		)
+	case *entity.TokenClaims:
+		attrs = append(attrs,
+			attribute.String("auth.token_subject", p.Subject),
+			attribute.String("auth.token_issuer", p.Issuer),
+		)
+	}
+	if subject, ok := ClientSubjectFromContext(ctx); ok {
+		attrs = append(attrs, attribute.String("tls.client.subject", subject))
+	}
+	return attrs
+}
+
+// describePrincipal はログに記録する主体の説明を返します
+func describePrincipal(p principal) string {
+	switch p := p.(type) {
+	case *entity.APIKey:
+		return fmt.Sprintf("api key %d", p.ID)
+	case *entity.TokenClaims:
+		return fmt.Sprintf("token subject=%q issuer=%q", p.Subject, p.Issuer)
+	default:
+		return "unknown principal"
+	}
+}
@@ -0,0 +1,300 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/takumi3488/cookiejar-server/internal/domain/entity"
+	"github.com/takumi3488/cookiejar-server/internal/domain/repository"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// billingContext は billing.example.com のサブドメインのみを読み書きできる API キーを設定したコンテキストを返します
+func billingContext(t *testing.T) context.Context {
+	t.Helper()
+	scopes, err := entity.ParseScopes([]string{"read:*.billing.example.com", "write:api.billing.example.com"})
+	if err != nil {
+		t.Fatalf("ParseScopes() error = %v", err)
+	}
+	return ContextWithAPIKey(context.Background(), &entity.APIKey{ID: 1, Name: "billing", Scopes: scopes})
+}
+
+// recordSpans は usecase の span を記録するトレーサーを設定します
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder
+}
+
+// deniedEvents は記録された span の authorization.denied イベントの数を返します
+func deniedEvents(recorder *tracetest.SpanRecorder) int {
+	n := 0
+	for _, span := range recorder.Ended() {
+		for _, event := range span.Events() {
+			if event.Name == "authorization.denied" {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+func TestCookieUsecase_Authorization(t *testing.T) {
+	mockRepo := &mockCookieRepository{
+		findAllFunc: func(ctx context.Context) ([]*entity.Cookie, error) {
+			return []*entity.Cookie{
+				{Name: "sid", Value: "billing", Domain: "api.billing.example.com"},
+				{Name: "sid", Value: "admin", Domain: "console.admin.example.com"},
+			}, nil
+		},
+		deleteByHostFunc: func(ctx context.Context, host string) (int, error) {
+			return 1, nil
+		},
+		deleteByFilterFunc: func(ctx context.Context, filter repository.CookieFilter, now time.Time) (int, error) {
+			return 1, nil
+		},
+	}
+	uc := NewCookieUsecase(mockRepo, nil, CookieLimits{})
+
+	tests := []struct {
+		name string
+		call func(ctx context.Context) error
+		// API キーのスコープで拒否されるか
+		wantDenied bool
+	}{
+		{
+			name: "書き込みが許可されたホストへの保存",
+			call: func(ctx context.Context) error {
+				return uc.StoreCookies(ctx, "", []*entity.Cookie{{Name: "sid", Domain: "api.billing.example.com"}})
+			},
+		},
+		{
+			name: "書き込みが許可されていないホストへの保存",
+			call: func(ctx context.Context) error {
+				return uc.StoreCookies(ctx, "", []*entity.Cookie{
+					{Name: "sid", Domain: "api.billing.example.com"},
+					{Name: "sid", Domain: "console.admin.example.com"},
+				})
+			},
+			wantDenied: true,
+		},
+		{
+			name: "読み取りが許可されたホストの取得",
+			call: func(ctx context.Context) error {
+				_, err := uc.GetCookiesByHost(ctx, "", "api.billing.example.com", "")
+				return err
+			},
+		},
+		{
+			name: "読み取りが許可されていないホストの取得",
+			call: func(ctx context.Context) error {
+				_, err := uc.GetCookiesByHost(ctx, "", "console.admin.example.com", "")
+				return err
+			},
+			wantDenied: true,
+		},
+		{
+			name: "ワイルドカードは親ドメイン自体を許可しない",
+			call: func(ctx context.Context) error {
+				_, err := uc.GetCookiesForURL(ctx, "", &url.URL{Scheme: "https", Host: "billing.example.com", Path: "/"}, "")
+				return err
+			},
+			wantDenied: true,
+		},
+		{
+			name: "削除の権限がない",
+			call: func(ctx context.Context) error {
+				_, err := uc.DeleteCookiesByHost(ctx, "", "api.billing.example.com")
+				return err
+			},
+			wantDenied: true,
+		},
+		{
+			name: "ドメインを指定しない一括削除",
+			call: func(ctx context.Context) error {
+				_, err := uc.DeleteCookies(ctx, "", repository.CookieFilter{ExpiredOnly: true})
+				return err
+			},
+			wantDenied: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := recordSpans(t)
+
+			err := tt.call(billingContext(t))
+			if tt.wantDenied {
+				if !errors.Is(err, ErrForbidden) {
+					t.Errorf("error = %v, want %v", err, ErrForbidden)
+				}
+				if got := deniedEvents(recorder); got != 1 {
+					t.Errorf("authorization.denied events = %v, want 1", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("error = %v, want nil", err)
+			}
+			if got := deniedEvents(recorder); got != 0 {
+				t.Errorf("authorization.denied events = %v, want 0", got)
+			}
+		})
+	}
+
+	t.Run("API キーがない場合はすべて許可する", func(t *testing.T) {
+		if _, err := uc.DeleteCookies(context.Background(), "", repository.CookieFilter{ExpiredOnly: true}); err != nil {
+			t.Errorf("DeleteCookies() error = %v", err)
+		}
+	})
+}
+
+func TestCookieUsecase_GetAllCookies_Authorization(t *testing.T) {
+	recorder := recordSpans(t)
+	uc := NewCookieUsecase(&mockCookieRepository{
+		findAllFunc: func(ctx context.Context) ([]*entity.Cookie, error) {
+			return []*entity.Cookie{
+				{Name: "sid", Value: "billing", Domain: "api.billing.example.com"},
+				{Name: "sid", Value: "admin", Domain: "console.admin.example.com"},
+			}, nil
+		},
+	}, nil, CookieLimits{})
+
+	// 読み取りが許可されていないドメインの Cookie は除外する
+	cookies, err := uc.GetAllCookies(billingContext(t), "")
+	if err != nil {
+		t.Fatalf("GetAllCookies() error = %v", err)
+	}
+	if len(cookies) != 1 || cookies[0].Value != "billing" {
+		t.Errorf("GetAllCookies() = %v, want only billing cookie", cookies)
+	}
+	if got := deniedEvents(recorder); got != 1 {
+		t.Errorf("authorization.denied events = %v, want 1", got)
+	}
+}
+
+func TestCookieUsecase_StoreSetCookies_Authorization(t *testing.T) {
+	uc := NewCookieUsecase(&mockCookieRepository{}, nil, CookieLimits{})
+	requestURL, _ := url.Parse("https://api.billing.example.com/")
+
+	// Domain 属性で親ドメインを指定した Cookie は書き込みが許可されていない
+	result, err := uc.StoreSetCookies(billingContext(t), "", requestURL, []string{
+		"sid=abc",
+		"shared=abc; Domain=example.com",
+	})
+	if err != nil {
+		t.Fatalf("StoreSetCookies() error = %v", err)
+	}
+	if result.Accepted != 1 {
+		t.Errorf("Accepted = %v, want 1", result.Accepted)
+	}
+	if len(result.Rejected) != 1 || result.Rejected[0].Index != 1 || !errors.Is(result.Rejected[0].Err, ErrForbidden) {
+		t.Errorf("Rejected = %+v, want index 1 forbidden", result.Rejected)
+	}
+}
+
+// tokenContext は billing.example.com のサブドメインのみを読み書きでき、jars の jar のみを操作できるトークンを設定したコンテキストを返します
+func tokenContext(t *testing.T, jars ...string) context.Context {
+	t.Helper()
+	return ContextWithTokenClaims(context.Background(), &entity.TokenClaims{
+		Subject: "bot-1",
+		Issuer:  "https://issuer.example.com",
+		Scopes:  entity.ParseTokenScopes([]string{"openid", "read:*.billing.example.com", "write:api.billing.example.com"}),
+		Jars:    jars,
+	})
+}
+
+func TestCookieUsecase_TokenAuthorization(t *testing.T) {
+	mockRepo := &mockCookieRepository{
+		findByDomainMatchFunc: func(ctx context.Context, host string) ([]*entity.Cookie, error) {
+			return []*entity.Cookie{{Name: "sid", Value: "billing", Domain: "api.billing.example.com"}}, nil
+		},
+	}
+	uc := NewCookieUsecase(mockRepo, nil, CookieLimits{})
+
+	tests := []struct {
+		name       string
+		jars       []string
+		jar        string
+		host       string
+		wantDenied bool
+	}{
+		{
+			name: "許可された jar とホスト",
+			jars: []string{"bot-1"},
+			jar:  "bot-1",
+			host: "api.billing.example.com",
+		},
+		{
+			name:       "許可されていない jar",
+			jars:       []string{"bot-1"},
+			jar:        "bot-2",
+			host:       "api.billing.example.com",
+			wantDenied: true,
+		},
+		{
+			name:       "jar を指定しないリクエストは既定の jar",
+			jars:       []string{"bot-1"},
+			host:       "api.billing.example.com",
+			wantDenied: true,
+		},
+		{
+			name: "すべての jar",
+			jars: []string{entity.AllJars},
+			jar:  "bot-2",
+			host: "api.billing.example.com",
+		},
+		{
+			name:       "許可されていないホスト",
+			jars:       []string{entity.AllJars},
+			jar:        "bot-1",
+			host:       "console.admin.example.com",
+			wantDenied: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := recordSpans(t)
+			_, err := uc.GetCookiesByHost(tokenContext(t, tt.jars...), tt.jar, tt.host, "")
+			if got := errors.Is(err, ErrForbidden); got != tt.wantDenied {
+				t.Fatalf("GetCookiesByHost() error = %v, want denied %v", err, tt.wantDenied)
+			}
+			if !tt.wantDenied && err != nil {
+				t.Fatalf("GetCookiesByHost() error = %v", err)
+			}
+
+			// 拒否した場合はトークンの主体を監査用に記録する
+			if !tt.wantDenied {
+				return
+			}
+			if got := deniedEvents(recorder); got != 1 {
+				t.Errorf("authorization.denied events = %v, want 1", got)
+			}
+			for _, span := range recorder.Ended() {
+				for _, event := range span.Events() {
+					if event.Name != "authorization.denied" {
+						continue
+					}
+					found := false
+					for _, attr := range event.Attributes {
+						if attr.Key == "auth.token_subject" && attr.Value.AsString() == "bot-1" {
+							found = true
+						}
+					}
+					if !found {
+						t.Errorf("authorization.denied attributes = %v, want auth.token_subject=bot-1", event.Attributes)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/takumi3488/cookiejar-server/internal/domain/entity"
+	"github.com/takumi3488/cookiejar-server/internal/domain/repository"
+)
+
+// Cookie の保存数・サイズの既定の上限（RFC 6265 §6.1 の最低要件と主要ブラウザの実装に合わせる）
+const (
+	DefaultMaxCookieSize       = 4096
+	DefaultMaxCookiesPerDomain = 180
+	DefaultMaxCookies          = 3000
+)
+
+// ErrCookieTooLarge は Cookie の名前と値の合計サイズが上限を超えていることを示すエラーです
+var ErrCookieTooLarge = errors.New("cookie too large")
+
+// CookieLimits は Cookie の保存数・サイズの上限です（0 の場合は上限なし）
+// 保存数の上限は jar ごとに適用されます
+type CookieLimits struct {
+	// 1つのCookieの名前と値の合計バイト数
+	MaxCookieSize int
+	// 1つの登録可能ドメイン（eTLD+1。サブドメインの Cookie を含む）あたりのCookie数
+	MaxCookiesPerDomain int
+	// jar 全体のCookie数
+	MaxCookies int
+}
+
+// DefaultCookieLimits は既定の上限を返します
+func DefaultCookieLimits() CookieLimits {
+	return CookieLimits{
+		MaxCookieSize:       DefaultMaxCookieSize,
+		MaxCookiesPerDomain: DefaultMaxCookiesPerDomain,
+		MaxCookies:          DefaultMaxCookies,
+	}
+}
+
+// checkSize は Cookie の名前と値の合計サイズが上限以内かを検証します
+func (l CookieLimits) checkSize(cookie *entity.Cookie) error {
+	size := len(cookie.Name) + len(cookie.Value)
+	if l.MaxCookieSize > 0 && size > l.MaxCookieSize {
+		return fmt.Errorf("%w: %d bytes exceeds limit of %d bytes", ErrCookieTooLarge, size, l.MaxCookieSize)
+	}
+	return nil
+}
+
+// evictionCounts は上限を超えたために削除したCookieの件数です
+type evictionCounts struct {
+	// 期限切れのため削除した件数
+	Expired int
+	// 最も長くアクセスされていないため削除した件数
+	LeastRecentlyUsed int
+}
+
+// evict はブラウザと同様に、jar の中で上限を超えた登録可能ドメイン（sites）と全体のCookieを期限切れのもの、最も長くアクセスされていないものの順に削除します
+func (l CookieLimits) evict(ctx context.Context, repo repository.CookieRepository, jar string, sites []string, now time.Time) (evictionCounts, error) {
+	var counts evictionCounts
+	for _, site := range sites {
+		if err := evictOverLimit(ctx, repo, jar, site, l.MaxCookiesPerDomain, now, &counts); err != nil {
+			return counts, err
+		}
+	}
+	if err := evictOverLimit(ctx, repo, jar, "", l.MaxCookies, now, &counts); err != nil {
+		return counts, err
+	}
+	return counts, nil
+}
+
+// evictOverLimit は jar の中の登録可能ドメイン site とそのサブドメイン（空の場合は全体）のCookie数が limit 以下になるまで削除します
+func evictOverLimit(ctx context.Context, repo repository.CookieRepository, jar, site string, limit int, now time.Time, counts *evictionCounts) error {
+	if limit <= 0 {
+		return nil
+	}
+	count, err := repo.Count(ctx, jar, site)
+	if err != nil || count <= limit {
+		return err
+	}
+
+	expired, err := repo.DeleteByFilter(ctx, jar, repository.CookieFilter{Site: site, ExpiredOnly: true}, now)
+	if err != nil {
+		return err
+	}
+	counts.Expired += expired
+
+	evicted := 0
+	if count-expired > limit {
+		evicted, err = repo.EvictLeastRecentlyUsed(ctx, jar, site, count-expired-limit)
+		if err != nil {
+			return err
+		}
+		counts.LeastRecentlyUsed += evicted
+	}
+	log.Printf("Evicted %d expired and %d least recently used cookies for jar=%q site=%q (limit %d)", expired, evicted, jar, site, limit)
+	return nil
+}
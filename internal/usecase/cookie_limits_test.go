@@ -0,0 +1,161 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/takumi3488/cookiejar-server/internal/domain/entity"
+	"github.com/takumi3488/cookiejar-server/internal/domain/repository"
+	"github.com/takumi3488/cookiejar-server/internal/infrastructure/publicsuffix"
+)
+
+func TestCookieUsecase_StoreCookies_Limits(t *testing.T) {
+	limits := CookieLimits{MaxCookieSize: 16, MaxCookiesPerDomain: 3, MaxCookies: 10}
+
+	type eviction struct {
+		site string
+		n    int
+	}
+
+	tests := []struct {
+		name    string
+		cookies []*entity.Cookie
+		// 保存後の登録可能ドメインごとのCookie数（"" は全体）
+		counts map[string]int
+		// 期限切れとして削除される登録可能ドメインごとのCookie数（"" は全体）
+		expired       map[string]int
+		wantErr       error
+		wantExpired   []string
+		wantEvictions []eviction
+	}{
+		{
+			name:    "上限以内であれば削除しない",
+			cookies: []*entity.Cookie{{Name: "sid", Value: "abc", Domain: "example.com"}},
+			counts:  map[string]int{"example.com": 3, "": 10},
+		},
+		{
+			name:    "サイズの上限を超えるCookieは保存しない",
+			cookies: []*entity.Cookie{{Name: "sid", Value: strings.Repeat("a", 14), Domain: "example.com"}},
+			wantErr: ErrCookieTooLarge,
+		},
+		{
+			name:        "ドメインの上限を超えた場合は期限切れのCookieから削除する",
+			cookies:     []*entity.Cookie{{Name: "sid", Value: "abc", Domain: "example.com"}},
+			counts:      map[string]int{"example.com": 5, "": 10},
+			expired:     map[string]int{"example.com": 2},
+			wantExpired: []string{"example.com"},
+		},
+		{
+			name:          "期限切れのCookieだけでは足りない場合は最も長くアクセスされていないCookieを削除する",
+			cookies:       []*entity.Cookie{{Name: "sid", Value: "abc", Domain: "example.com"}},
+			counts:        map[string]int{"example.com": 6, "": 10},
+			expired:       map[string]int{"example.com": 1},
+			wantExpired:   []string{"example.com"},
+			wantEvictions: []eviction{{site: "example.com", n: 2}},
+		},
+		{
+			name: "複数のサブドメインのCookieは登録可能ドメインごとにまとめて上限を確認する",
+			cookies: []*entity.Cookie{
+				{Name: "sid", Value: "abc", Domain: "a.example.com"},
+				{Name: "sid", Value: "abc", Domain: ".B.example.com"},
+				{Name: "sid", Value: "abc", Domain: "www.example.com", HostOnly: true},
+			},
+			counts:        map[string]int{"example.com": 5, "": 10},
+			wantExpired:   []string{"example.com"},
+			wantEvictions: []eviction{{site: "example.com", n: 2}},
+		},
+		{
+			name:          "全体の上限を超えた場合はすべてのドメインから削除する",
+			cookies:       []*entity.Cookie{{Name: "sid", Value: "abc", Domain: "example.com"}},
+			counts:        map[string]int{"example.com": 1, "": 12},
+			wantExpired:   []string{""},
+			wantEvictions: []eviction{{site: "", n: 2}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var upserted bool
+			var gotExpired []string
+			var gotEvictions []eviction
+			mockRepo := &mockCookieRepository{
+				upsertManyFunc: func(ctx context.Context, cookies []*entity.Cookie, updatedAt time.Time) error {
+					upserted = true
+					return nil
+				},
+				countFunc: func(ctx context.Context, site string) (int, error) {
+					return tt.counts[site], nil
+				},
+				deleteByFilterFunc: func(ctx context.Context, filter repository.CookieFilter, now time.Time) (int, error) {
+					if !filter.ExpiredOnly {
+						t.Errorf("DeleteByFilter() filter = %+v, want ExpiredOnly", filter)
+					}
+					gotExpired = append(gotExpired, filter.Site)
+					return tt.expired[filter.Site], nil
+				},
+				evictLeastRecentlyUsedFunc: func(ctx context.Context, site string, n int) (int, error) {
+					gotEvictions = append(gotEvictions, eviction{site: site, n: n})
+					return n, nil
+				},
+			}
+
+			uc := NewCookieUsecase(mockRepo, publicsuffix.Default(), limits)
+			err := uc.StoreCookies(context.Background(), "", tt.cookies)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("StoreCookies() error = %v, want %v", err, tt.wantErr)
+				}
+				if upserted {
+					t.Error("UpsertMany() called, want no cookies stored")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("StoreCookies() error = %v", err)
+			}
+			if len(gotExpired) != len(tt.wantExpired) {
+				t.Fatalf("DeleteByFilter() sites = %q, want %q", gotExpired, tt.wantExpired)
+			}
+			for i := range gotExpired {
+				if gotExpired[i] != tt.wantExpired[i] {
+					t.Errorf("DeleteByFilter() sites[%d] = %q, want %q", i, gotExpired[i], tt.wantExpired[i])
+				}
+			}
+			if len(gotEvictions) != len(tt.wantEvictions) {
+				t.Fatalf("EvictLeastRecentlyUsed() = %+v, want %+v", gotEvictions, tt.wantEvictions)
+			}
+			for i := range gotEvictions {
+				if gotEvictions[i] != tt.wantEvictions[i] {
+					t.Errorf("EvictLeastRecentlyUsed()[%d] = %+v, want %+v", i, gotEvictions[i], tt.wantEvictions[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCookieUsecase_StoreSetCookies_TooLarge(t *testing.T) {
+	uc := NewCookieUsecase(&mockCookieRepository{}, nil, CookieLimits{MaxCookieSize: 16})
+	requestURL, _ := url.Parse("https://example.com/")
+	result, err := uc.StoreSetCookies(context.Background(), "", requestURL, []string{
+		"sid=abc",
+		"large=" + strings.Repeat("a", 16),
+	})
+	if err != nil {
+		t.Fatalf("StoreSetCookies() error = %v", err)
+	}
+
+	if result.Accepted != 1 {
+		t.Errorf("Accepted = %v, want 1", result.Accepted)
+	}
+	if len(result.Rejected) != 1 || result.Rejected[0].Index != 1 {
+		t.Fatalf("Rejected = %+v, want index 1", result.Rejected)
+	}
+	if err := result.Rejected[0].Err; !errors.Is(err, entity.ErrCookieRejected) || !errors.Is(err, ErrCookieTooLarge) {
+		t.Errorf("Rejected[0].Err = %v, want ErrCookieRejected and ErrCookieTooLarge", err)
+	}
+}
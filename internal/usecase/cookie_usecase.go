@@ -2,8 +2,12 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
-	"net/http"
+	"maps"
+	"net/url"
+	"slices"
 	"time"
 
 	"github.com/takumi3488/cookiejar-server/internal/domain/entity"
@@ -14,87 +18,503 @@ import (
 	"go.opentelemetry.io/otel/trace"
 )
 
+// CookieUsecase は jar 引数で指定した jar の Cookie を操作します（空の場合は entity.DefaultJar）
+// jar の名前が不正な場合は entity.ErrInvalidJarName、存在しない jar へ保存しようとした場合は entity.ErrJarNotFound をラップしたエラーを返します
 type CookieUsecase interface {
-	StoreCookies(ctx context.Context, cookies []*http.Cookie) error
-	GetAllCookies(ctx context.Context) ([]*entity.Cookie, error)
+	StoreCookies(ctx context.Context, jar string, cookies []*entity.Cookie) error
+	StoreSetCookies(ctx context.Context, jar string, requestURL *url.URL, headers []string) (*SetCookieResult, error)
+	// ReplaySetCookies は保存せずに、複数のレスポンスの Set-Cookie ヘッダーを適用した最終的な Cookie を返します（StoreCookies で保存する）
+	ReplaySetCookies(ctx context.Context, responses []SetCookieResponse) ([]*entity.Cookie, []*SetCookieResult)
+	GetAllCookies(ctx context.Context, jar string) ([]*entity.Cookie, error)
 
-	GetCookiesByHost(ctx context.Context, host string) ([]*entity.Cookie, error)
+	// GetCookiesByHost・GetCookiesForURL は、パーティションキーのない Cookie と
+	// トップレベルサイト topLevelSite のパーティションの Cookie のみを返します（CHIPS）
+	GetCookiesByHost(ctx context.Context, jar, host, topLevelSite string) ([]*entity.Cookie, error)
+	GetCookiesForURL(ctx context.Context, jar string, requestURL *url.URL, topLevelSite string) ([]*entity.Cookie, error)
+
+	DeleteCookie(ctx context.Context, jar string, key entity.CookieKey) (int, error)
+	DeleteCookiesByHost(ctx context.Context, jar, host string) (int, error)
+	DeleteCookies(ctx context.Context, jar string, filter repository.CookieFilter) (int, error)
+}
+
+// SetCookieResponse は Set-Cookie ヘッダーを受け取った1つのレスポンスです
+type SetCookieResponse struct {
+	URL *url.URL
+	// ReceivedAt は Max-Age の基準となる受信時刻（ゼロ値の場合は現在時刻）
+	ReceivedAt time.Time
+	Headers    []string
 }
 
+// SetCookieResult は Set-Cookie ヘッダーの取り込み結果です
+type SetCookieResult struct {
+	// Accepted は保存対象として受け付けた Cookie の数
+	Accepted int
+	Rejected []RejectedSetCookie
+}
+
+// RejectedSetCookie は保存を拒否した Set-Cookie ヘッダーとその理由です
+type RejectedSetCookie struct {
+	Index  int
+	Header string
+	Err    error
+}
+
+// ErrEmptyFilter は一括削除で条件が1つも指定されていない場合のエラーです
+var ErrEmptyFilter = errors.New("at least one filter condition is required")
+
 type cookieUsecase struct {
 	cookieRepo repository.CookieRepository
+	// 公開サフィックスを Domain 属性とする Cookie の拒否に使う（nil の場合は検証しない）
+	psl    entity.PublicSuffixList
+	limits CookieLimits
+	now    func() time.Time
 }
 
-func NewCookieUsecase(cookieRepo repository.CookieRepository) CookieUsecase {
+func NewCookieUsecase(cookieRepo repository.CookieRepository, psl entity.PublicSuffixList, limits CookieLimits) CookieUsecase {
 	return &cookieUsecase{
 		cookieRepo: cookieRepo,
+		psl:        psl,
+		limits:     limits,
+		now:        time.Now,
 	}
 }
 
-func (u *cookieUsecase) StoreCookies(ctx context.Context, cookies []*http.Cookie) error {
+// StoreCookies はドメインと有効期限が確定済みのCookieを保存します
+// MaxAge は呼び出し側で entity.NewCookieAt などにより Expires へ変換しておく必要があります
+// 公開サフィックスを Domain 属性とする Cookie やサイズの上限を超える Cookie が含まれる場合は何も保存せず、
+// entity.ErrPublicSuffix または ErrCookieTooLarge をラップしたエラーを返します
+// API キーのスコープで書き込みが許可されていないドメインの Cookie が含まれる場合も何も保存せず、ErrForbidden をラップしたエラーを返します
+// 保存後に jar の中で登録可能ドメイン（eTLD+1）ごと・全体の Cookie 数が上限を超えた場合は古い Cookie から削除します
+func (u *cookieUsecase) StoreCookies(ctx context.Context, jar string, cookies []*entity.Cookie) error {
 	tracer := otel.Tracer("cookiejar-server/usecase")
 	ctx, span := tracer.Start(ctx, "StoreCookies", trace.WithSpanKind(trace.SpanKindInternal))
 	defer span.End()
 
+	jar, err := resolveJar(ctx, span, jar)
+	if err != nil {
+		return err
+	}
 	span.SetAttributes(attribute.Int("cookie.count", len(cookies)))
 
-	// ホストごとにCookieをグループ化
-	hostCookies := make(map[string][]*entity.Cookie)
+	for i, cookie := range cookies {
+		if err := authorize(ctx, span, entity.PermissionWrite, cookie.CanonicalDomain()); err != nil {
+			err = fmt.Errorf("cookie at index %d: %w", i, err)
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "Forbidden")
+			return err
+		}
+		if err := cookie.ValidatePublicSuffix(u.psl); err != nil {
+			err = fmt.Errorf("cookie at index %d: %w", i, err)
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "Cookie domain is a public suffix")
+			return err
+		}
+		if err := u.limits.checkSize(cookie); err != nil {
+			err = fmt.Errorf("cookie at index %d: %w", i, err)
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "Cookie too large")
+			return err
+		}
+	}
+
+	if err := u.store(ctx, jar, cookies, u.now()); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to upsert cookies")
+		return err
+	}
+
+	span.SetStatus(codes.Ok, "Successfully stored all cookies")
+	return nil
+}
+
+// StoreSetCookies は requestURL へのレスポンスで受け取った Set-Cookie ヘッダーを解析して保存します
+// ブラウザが拒否する Cookie と API キーのスコープで書き込みが許可されていない Cookie は保存せず、Rejected に理由とともに返します
+func (u *cookieUsecase) StoreSetCookies(ctx context.Context, jar string, requestURL *url.URL, headers []string) (*SetCookieResult, error) {
+	tracer := otel.Tracer("cookiejar-server/usecase")
+	ctx, span := tracer.Start(ctx, "StoreSetCookies", trace.WithSpanKind(trace.SpanKindInternal))
+	defer span.End()
+
+	jar, err := resolveJar(ctx, span, jar)
+	if err != nil {
+		return nil, err
+	}
+	span.SetAttributes(
+		attribute.String("cookie.host", requestURL.Hostname()),
+		attribute.Int("cookie.header_count", len(headers)),
+	)
+
+	now := u.now()
+	cookies, results := u.replaySetCookies(ctx, []SetCookieResponse{{URL: requestURL, Headers: headers}}, now)
+	if err := u.store(ctx, jar, cookies, now); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to upsert cookies")
+		return nil, err
+	}
+	result := results[0]
+
+	span.SetAttributes(
+		attribute.Int("cookie.count", result.Accepted),
+		attribute.Int("cookie.rejected_count", len(result.Rejected)),
+	)
+	span.SetStatus(codes.Ok, "Successfully stored cookies from Set-Cookie headers")
+	return result, nil
+}
+
+// ReplaySetCookies は複数のレスポンスの Set-Cookie ヘッダーを与えられた順に適用し、最終的な状態の Cookie を返します
+// 同じ Cookie を複数のレスポンスが設定した場合は後のレスポンスが優先されます（Max-Age=0 などによる削除は失効した Cookie として返す）
+// 保存は行わないため、呼び出し側は返された Cookie を StoreCookies で保存します
+func (u *cookieUsecase) ReplaySetCookies(ctx context.Context, responses []SetCookieResponse) ([]*entity.Cookie, []*SetCookieResult) {
+	tracer := otel.Tracer("cookiejar-server/usecase")
+	ctx, span := tracer.Start(ctx, "ReplaySetCookies", trace.WithSpanKind(trace.SpanKindInternal))
+	defer span.End()
+
+	span.SetAttributes(attribute.Int("cookie.response_count", len(responses)))
+	cookies, results := u.replaySetCookies(ctx, responses, u.now())
+
+	span.SetAttributes(attribute.Int("cookie.count", len(cookies)))
+	span.SetStatus(codes.Ok, "Successfully replayed Set-Cookie headers")
+	return cookies, results
+}
+
+// replaySetCookies は Set-Cookie ヘッダーを解析し、ブラウザが拒否する Cookie と書き込みが許可されていない Cookie を除いて、
+// 同じ識別キーの Cookie を後勝ちでまとめて返します（受信時刻が未設定のレスポンスは now を基準にする）
+func (u *cookieUsecase) replaySetCookies(ctx context.Context, responses []SetCookieResponse, now time.Time) ([]*entity.Cookie, []*SetCookieResult) {
+	span := trace.SpanFromContext(ctx)
+	results := make([]*SetCookieResult, len(responses))
+	var cookies []*entity.Cookie
+	for i, response := range responses {
+		receivedAt := response.ReceivedAt
+		if receivedAt.IsZero() {
+			receivedAt = now
+		}
+
+		result := &SetCookieResult{}
+		for j, header := range response.Headers {
+			cookie, err := entity.ParseSetCookie(header, response.URL, receivedAt, u.psl)
+			if err == nil {
+				// ブラウザはサイズの上限を超える Cookie を無視する
+				if sizeErr := u.limits.checkSize(cookie); sizeErr != nil {
+					err = fmt.Errorf("%w: %w", entity.ErrCookieRejected, sizeErr)
+				} else {
+					err = authorize(ctx, span, entity.PermissionWrite, cookie.CanonicalDomain())
+				}
+			}
+			if err != nil {
+				result.Rejected = append(result.Rejected, RejectedSetCookie{Index: j, Header: header, Err: err})
+				continue
+			}
+			cookies = append(cookies, cookie)
+			result.Accepted++
+		}
+		results[i] = result
+	}
+
+	// 同じ識別キーの Cookie は後から設定されたものを残す
+	return entity.MergeCookies(nil, cookies), results
+}
+
+// touch は返却する Cookie の最終アクセス時刻を now に更新します（RFC 6265 §5.4 step 3）
+// 更新に失敗しても Cookie の取得は失敗させず、エラーを span に記録します
+func (u *cookieUsecase) touch(ctx context.Context, span trace.Span, jar string, cookies []*entity.Cookie, now time.Time) {
+	if len(cookies) == 0 {
+		return
+	}
+	keys := make([]entity.CookieKey, len(cookies))
+	for i, cookie := range cookies {
+		keys[i] = cookie.Key()
+	}
+	if _, err := u.cookieRepo.Touch(ctx, jar, keys, now); err != nil {
+		log.Printf("Failed to update last access time of %d cookies: %v", len(cookies), err)
+		span.RecordError(err)
+		return
+	}
 	for _, cookie := range cookies {
-		c := entity.NewCookie(cookie)
-		host := c.Domain
-		hostCookies[host] = append(hostCookies[host], c)
+		cookie.LastAccessedAt = now
 	}
+}
 
-	// 各ホストごとに一括保存
-	now := time.Now()
-	for host, cookieList := range hostCookies {
-		if err := u.cookieRepo.UpsertMany(ctx, host, cookieList, now); err != nil {
-			log.Printf("Failed to upsert cookies for host=%s: %v", host, err)
-			span.RecordError(err)
-			span.SetStatus(codes.Error, "Failed to upsert cookies")
+// resolveJar は jar の名前を検証して span に記録し、空の場合は既定の jar の名前を返します
+// コンテキストの主体が jar の操作を許可されていない場合は ErrForbidden を返します
+func resolveJar(ctx context.Context, span trace.Span, jar string) (string, error) {
+	jar, err := entity.ResolveJarName(jar)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Invalid jar name")
+		return "", err
+	}
+	span.SetAttributes(attribute.String("cookie.jar", jar))
+	if err := authorizeJar(ctx, span, jar); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Forbidden")
+		return "", err
+	}
+	return jar, nil
+}
+
+// removeInvalidPrefix はブラウザが送信しない、プレフィックスの要件を満たさない Cookie を除外し、件数を span に記録します
+// 保存時の検証より前に保存された Cookie が対象です
+func removeInvalidPrefix(span trace.Span, cookies []*entity.Cookie) []*entity.Cookie {
+	valid := entity.RemoveInvalidPrefix(cookies)
+	if invalid := len(cookies) - len(valid); invalid > 0 {
+		log.Printf("Excluded %d cookies violating name prefix requirements", invalid)
+		span.SetAttributes(attribute.Int("cookie.invalid_prefix_count", invalid))
+	}
+	return valid
+}
+
+// store はリクエスト全体のCookieを1つのトランザクションで jar に保存します
+// 行ロックの取得順序はリポジトリが識別キー順に揃えるため、ホストをまたいでも1回の UpsertMany で保存します
+// 同じトランザクションで上限を超えたCookieを削除し、削除した件数を ctx の span に記録します
+func (u *cookieUsecase) store(ctx context.Context, jar string, cookies []*entity.Cookie, now time.Time) error {
+	if len(cookies) == 0 {
+		return nil
+	}
+
+	// ブラウザと同様に、ドメインごとの上限はサブドメインを含む登録可能ドメイン（eTLD+1）ごとに1回だけ確認する
+	siteSet := make(map[string]struct{})
+	for _, c := range cookies {
+		siteSet[entity.RegistrableDomain(u.psl, c.CanonicalDomain())] = struct{}{}
+	}
+	sites := slices.Sorted(maps.Keys(siteSet))
+
+	var evicted evictionCounts
+	err := u.cookieRepo.WithinTx(ctx, func(repo repository.CookieRepository) error {
+		if err := repo.UpsertMany(ctx, jar, cookies, now); err != nil {
+			log.Printf("Failed to upsert %d cookies: %v", len(cookies), err)
 			return err
 		}
+
+		var err error
+		evicted, err = u.limits.evict(ctx, repo, jar, sites, now)
+		if err != nil {
+			log.Printf("Failed to evict cookies: %v", err)
+		}
+		return err
+	})
+	if err != nil {
+		return err
 	}
 
-	span.SetStatus(codes.Ok, "Successfully stored all cookies")
+	trace.SpanFromContext(ctx).SetAttributes(
+		attribute.Int("cookie.evicted_expired_count", evicted.Expired),
+		attribute.Int("cookie.evicted_lru_count", evicted.LeastRecentlyUsed),
+	)
 	return nil
 }
 
-func (u *cookieUsecase) GetAllCookies(ctx context.Context) ([]*entity.Cookie, error) {
+func (u *cookieUsecase) GetAllCookies(ctx context.Context, jar string) ([]*entity.Cookie, error) {
 	tracer := otel.Tracer("cookiejar-server/usecase")
 	ctx, span := tracer.Start(ctx, "GetAllCookies", trace.WithSpanKind(trace.SpanKindInternal))
 	defer span.End()
 
-	cookies, err := u.cookieRepo.FindAll(ctx)
+	jar, err := resolveJar(ctx, span, jar)
+	if err != nil {
+		return nil, err
+	}
+
+	cookies, err := u.cookieRepo.FindAll(ctx, jar)
 	if err != nil {
 		log.Printf("Failed to get all cookies: %v", err)
 		span.RecordError(err)
 		span.SetStatus(codes.Error, "Failed to get all cookies")
 		return nil, err
 	}
+	// エクスポートしたCookieをブラウザが拒否しないよう、Reader と同じくプレフィックスの要件を満たさない Cookie を除外する
+	cookies = removeInvalidPrefix(span, filterReadable(ctx, span, entity.RemoveExpired(cookies, u.now())))
 
 	span.SetAttributes(attribute.Int("cookie.count", len(cookies)))
 	span.SetStatus(codes.Ok, "Successfully retrieved all cookies")
 	return cookies, nil
 }
 
-func (u *cookieUsecase) GetCookiesByHost(ctx context.Context, host string) ([]*entity.Cookie, error) {
+func (u *cookieUsecase) GetCookiesByHost(ctx context.Context, jar, host, topLevelSite string) ([]*entity.Cookie, error) {
 	tracer := otel.Tracer("cookiejar-server/usecase")
 	ctx, span := tracer.Start(ctx, "GetCookiesByHost", trace.WithSpanKind(trace.SpanKindInternal))
 	defer span.End()
 
-	span.SetAttributes(attribute.String("cookie.host", host))
+	jar, err := resolveJar(ctx, span, jar)
+	if err != nil {
+		return nil, err
+	}
+	span.SetAttributes(
+		attribute.String("cookie.host", host),
+		attribute.String("cookie.top_level_site", topLevelSite),
+	)
+
+	// 親ドメインの Cookie も含め、ホストへ送信される Cookie の読み取りを許可されているかを検証
+	if err := authorize(ctx, span, entity.PermissionRead, host); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Forbidden")
+		return nil, err
+	}
 
-	cookies, err := u.cookieRepo.FindByHost(ctx, host)
+	// RFC 6265 §5.1.3 に従い、親ドメインの Cookie も含めて取得
+	cookies, err := u.cookieRepo.FindByDomainMatch(ctx, jar, host)
 	if err != nil {
 		log.Printf("Failed to get cookies for host=%s: %v", host, err)
 		span.RecordError(err)
 		span.SetStatus(codes.Error, "Failed to get cookies by host")
 		return nil, err
 	}
+	now := u.now()
+	// 他のトップレベルサイトのパーティションの Cookie は、同じ名前でも送信されないため除外する
+	cookies = removeInvalidPrefix(span, entity.RemoveOtherPartitions(entity.RemoveExpired(cookies, now), topLevelSite))
+	u.touch(ctx, span, jar, cookies, now)
 
 	span.SetAttributes(attribute.Int("cookie.count", len(cookies)))
 	span.SetStatus(codes.Ok, "Successfully retrieved cookies by host")
 	return cookies, nil
 }
+
+func (u *cookieUsecase) GetCookiesForURL(ctx context.Context, jar string, requestURL *url.URL, topLevelSite string) ([]*entity.Cookie, error) {
+	tracer := otel.Tracer("cookiejar-server/usecase")
+	ctx, span := tracer.Start(ctx, "GetCookiesForURL", trace.WithSpanKind(trace.SpanKindInternal))
+	defer span.End()
+
+	jar, err := resolveJar(ctx, span, jar)
+	if err != nil {
+		return nil, err
+	}
+	span.SetAttributes(
+		attribute.String("cookie.host", requestURL.Hostname()),
+		attribute.String("cookie.path", requestURL.EscapedPath()),
+		attribute.String("cookie.scheme", requestURL.Scheme),
+		attribute.String("cookie.top_level_site", topLevelSite),
+	)
+
+	if err := authorize(ctx, span, entity.PermissionRead, requestURL.Hostname()); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Forbidden")
+		return nil, err
+	}
+
+	cookies, err := u.cookieRepo.FindByDomainMatch(ctx, jar, requestURL.Hostname())
+	if err != nil {
+		log.Printf("Failed to get cookies for url=%s: %v", requestURL.Redacted(), err)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to get cookies for URL")
+		return nil, err
+	}
+
+	// 有効期限、path-match、Secure 属性、パーティションでフィルタ
+	now := u.now()
+	result := make([]*entity.Cookie, 0, len(cookies))
+	for _, cookie := range cookies {
+		if !cookie.IsExpired(now) && cookie.MatchesURL(requestURL) && cookie.MatchesPartition(topLevelSite) {
+			result = append(result, cookie)
+		}
+	}
+	result = removeInvalidPrefix(span, result)
+	u.touch(ctx, span, jar, result, now)
+
+	span.SetAttributes(attribute.Int("cookie.count", len(result)))
+	span.SetStatus(codes.Ok, "Successfully retrieved cookies for URL")
+	return result, nil
+}
+
+func (u *cookieUsecase) DeleteCookie(ctx context.Context, jar string, key entity.CookieKey) (int, error) {
+	tracer := otel.Tracer("cookiejar-server/usecase")
+	ctx, span := tracer.Start(ctx, "DeleteCookie", trace.WithSpanKind(trace.SpanKindInternal))
+	defer span.End()
+
+	jar, err := resolveJar(ctx, span, jar)
+	if err != nil {
+		return 0, err
+	}
+	span.SetAttributes(
+		attribute.String("cookie.name", key.Name),
+		attribute.String("cookie.domain", key.Domain),
+		attribute.String("cookie.path", key.Path),
+		attribute.Bool("cookie.host_only", key.HostOnly),
+	)
+
+	if err := authorize(ctx, span, entity.PermissionDelete, key.Domain); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Forbidden")
+		return 0, err
+	}
+
+	deleted, err := u.cookieRepo.Delete(ctx, jar, key)
+	if err != nil {
+		log.Printf("Failed to delete cookie name=%s domain=%s path=%s: %v", key.Name, key.Domain, key.Path, err)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to delete cookie")
+		return 0, err
+	}
+
+	span.SetAttributes(attribute.Int("cookie.deleted", deleted))
+	span.SetStatus(codes.Ok, "Successfully deleted cookie")
+	return deleted, nil
+}
+
+func (u *cookieUsecase) DeleteCookiesByHost(ctx context.Context, jar, host string) (int, error) {
+	tracer := otel.Tracer("cookiejar-server/usecase")
+	ctx, span := tracer.Start(ctx, "DeleteCookiesByHost", trace.WithSpanKind(trace.SpanKindInternal))
+	defer span.End()
+
+	jar, err := resolveJar(ctx, span, jar)
+	if err != nil {
+		return 0, err
+	}
+	span.SetAttributes(attribute.String("cookie.host", host))
+
+	if err := authorize(ctx, span, entity.PermissionDelete, host); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Forbidden")
+		return 0, err
+	}
+
+	deleted, err := u.cookieRepo.DeleteByHost(ctx, jar, host)
+	if err != nil {
+		log.Printf("Failed to delete cookies for host=%s: %v", host, err)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to delete cookies by host")
+		return 0, err
+	}
+
+	span.SetAttributes(attribute.Int("cookie.deleted", deleted))
+	span.SetStatus(codes.Ok, "Successfully deleted cookies by host")
+	return deleted, nil
+}
+
+func (u *cookieUsecase) DeleteCookies(ctx context.Context, jar string, filter repository.CookieFilter) (int, error) {
+	tracer := otel.Tracer("cookiejar-server/usecase")
+	ctx, span := tracer.Start(ctx, "DeleteCookies", trace.WithSpanKind(trace.SpanKindInternal))
+	defer span.End()
+
+	jar, err := resolveJar(ctx, span, jar)
+	if err != nil {
+		return 0, err
+	}
+	span.SetAttributes(
+		attribute.String("cookie.filter.domain", filter.Domain),
+		attribute.String("cookie.filter.name_prefix", filter.NamePrefix),
+		attribute.Bool("cookie.filter.expired_only", filter.ExpiredOnly),
+	)
+
+	// 条件なしの一括削除（全件削除）は受け付けない
+	if filter.IsEmpty() {
+		span.RecordError(ErrEmptyFilter)
+		span.SetStatus(codes.Error, "Empty filter")
+		return 0, ErrEmptyFilter
+	}
+
+	// ドメインを指定しない場合はすべてのホストに対する削除の権限が必要
+	if err := authorize(ctx, span, entity.PermissionDelete, filter.Domain); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Forbidden")
+		return 0, err
+	}
+
+	deleted, err := u.cookieRepo.DeleteByFilter(ctx, jar, filter, u.now())
+	if err != nil {
+		log.Printf("Failed to delete cookies by filter %+v: %v", filter, err)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to delete cookies by filter")
+		return 0, err
+	}
+
+	span.SetAttributes(attribute.Int("cookie.deleted", deleted))
+	span.SetStatus(codes.Ok, "Successfully deleted cookies by filter")
+	return deleted, nil
+}
@@ -3,56 +3,139 @@ package usecase
 import (
 	"context"
 	"errors"
-	"net/http"
+	"net/url"
+	"slices"
 	"testing"
 	"time"
 
 	"github.com/takumi3488/cookiejar-server/internal/domain/entity"
+	"github.com/takumi3488/cookiejar-server/internal/domain/repository"
+	"github.com/takumi3488/cookiejar-server/internal/infrastructure/publicsuffix"
 )
 
 // モックリポジトリ
 type mockCookieRepository struct {
 	upsertFunc     func(ctx context.Context, cookie *entity.Cookie, updatedAt time.Time) error
-	upsertManyFunc func(ctx context.Context, host string, cookies []*entity.Cookie, updatedAt time.Time) error
+	upsertManyFunc func(ctx context.Context, cookies []*entity.Cookie, updatedAt time.Time) error
 	findAllFunc    func(ctx context.Context) ([]*entity.Cookie, error)
 	findByHostFunc func(ctx context.Context, host string) ([]*entity.Cookie, error)
+
+	findByDomainMatchFunc func(ctx context.Context, host string) ([]*entity.Cookie, error)
+	purgeExpiredFunc      func(ctx context.Context, now time.Time, batchSize int) (int, error)
+
+	deleteFunc         func(ctx context.Context, key entity.CookieKey) (int, error)
+	deleteByHostFunc   func(ctx context.Context, host string) (int, error)
+	deleteByFilterFunc func(ctx context.Context, filter repository.CookieFilter, now time.Time) (int, error)
+
+	touchFunc                  func(ctx context.Context, keys []entity.CookieKey, accessedAt time.Time) (int, error)
+	countFunc                  func(ctx context.Context, site string) (int, error)
+	evictLeastRecentlyUsedFunc func(ctx context.Context, site string, n int) (int, error)
+
+	// 最後に呼び出された操作の jar
+	jar string
+	// WithinTx の実行中は true
+	inTx bool
 }
 
-func (m *mockCookieRepository) Upsert(ctx context.Context, cookie *entity.Cookie, updatedAt time.Time) error {
+func (m *mockCookieRepository) Upsert(ctx context.Context, jar string, cookie *entity.Cookie, updatedAt time.Time) error {
+	m.jar = jar
 	if m.upsertFunc != nil {
 		return m.upsertFunc(ctx, cookie, updatedAt)
 	}
 	return nil
 }
 
-func (m *mockCookieRepository) UpsertMany(ctx context.Context, host string, cookies []*entity.Cookie, updatedAt time.Time) error {
+func (m *mockCookieRepository) UpsertMany(ctx context.Context, jar string, cookies []*entity.Cookie, updatedAt time.Time) error {
+	m.jar = jar
 	if m.upsertManyFunc != nil {
-		return m.upsertManyFunc(ctx, host, cookies, updatedAt)
+		return m.upsertManyFunc(ctx, cookies, updatedAt)
 	}
 	return nil
 }
 
-func (m *mockCookieRepository) FindAll(ctx context.Context) ([]*entity.Cookie, error) {
+func (m *mockCookieRepository) FindAll(ctx context.Context, jar string) ([]*entity.Cookie, error) {
+	m.jar = jar
 	return m.findAllFunc(ctx)
 }
 
-func (m *mockCookieRepository) FindByHost(ctx context.Context, host string) ([]*entity.Cookie, error) {
+func (m *mockCookieRepository) FindByHost(ctx context.Context, jar, host string) ([]*entity.Cookie, error) {
+	m.jar = jar
 	if m.findByHostFunc != nil {
 		return m.findByHostFunc(ctx, host)
 	}
 	return nil, nil
 }
 
+func (m *mockCookieRepository) FindByDomainMatch(ctx context.Context, jar, host string) ([]*entity.Cookie, error) {
+	m.jar = jar
+	if m.findByDomainMatchFunc != nil {
+		return m.findByDomainMatchFunc(ctx, host)
+	}
+	return nil, nil
+}
+
+func (m *mockCookieRepository) PurgeExpired(ctx context.Context, now time.Time, batchSize int) (int, error) {
+	if m.purgeExpiredFunc != nil {
+		return m.purgeExpiredFunc(ctx, now, batchSize)
+	}
+	return 0, nil
+}
+
+func (m *mockCookieRepository) Delete(ctx context.Context, jar string, key entity.CookieKey) (int, error) {
+	m.jar = jar
+	return m.deleteFunc(ctx, key)
+}
+
+func (m *mockCookieRepository) DeleteByHost(ctx context.Context, jar, host string) (int, error) {
+	m.jar = jar
+	return m.deleteByHostFunc(ctx, host)
+}
+
+func (m *mockCookieRepository) DeleteByFilter(ctx context.Context, jar string, filter repository.CookieFilter, now time.Time) (int, error) {
+	m.jar = jar
+	return m.deleteByFilterFunc(ctx, filter, now)
+}
+
+func (m *mockCookieRepository) Touch(ctx context.Context, jar string, keys []entity.CookieKey, accessedAt time.Time) (int, error) {
+	m.jar = jar
+	if m.touchFunc != nil {
+		return m.touchFunc(ctx, keys, accessedAt)
+	}
+	return len(keys), nil
+}
+
+func (m *mockCookieRepository) Count(ctx context.Context, jar, site string) (int, error) {
+	m.jar = jar
+	if m.countFunc != nil {
+		return m.countFunc(ctx, site)
+	}
+	return 0, nil
+}
+
+func (m *mockCookieRepository) EvictLeastRecentlyUsed(ctx context.Context, jar, site string, n int) (int, error) {
+	m.jar = jar
+	if m.evictLeastRecentlyUsedFunc != nil {
+		return m.evictLeastRecentlyUsedFunc(ctx, site, n)
+	}
+	return 0, nil
+}
+
+func (m *mockCookieRepository) WithinTx(ctx context.Context, fn func(repo repository.CookieRepository) error) error {
+	m.inTx = true
+	defer func() { m.inTx = false }()
+	return fn(m)
+}
+
 func TestCookieUsecase_StoreCookies(t *testing.T) {
 	tests := []struct {
 		name          string
-		cookies       []*http.Cookie
+		cookies       []*entity.Cookie
 		upsertManyErr error
 		wantErr       bool
 	}{
 		{
 			name: "正常に保存できる",
-			cookies: []*http.Cookie{
+			cookies: []*entity.Cookie{
 				{Name: "cookie1", Value: "value1", Domain: "example.com"},
 				{Name: "cookie2", Value: "value2", Domain: "example.com"},
 			},
@@ -61,7 +144,7 @@ func TestCookieUsecase_StoreCookies(t *testing.T) {
 		},
 		{
 			name: "UpsertManyでエラーが発生",
-			cookies: []*http.Cookie{
+			cookies: []*entity.Cookie{
 				{Name: "cookie1", Value: "value1", Domain: "example.com"},
 			},
 			upsertManyErr: errors.New("upsert error"),
@@ -69,13 +152,13 @@ func TestCookieUsecase_StoreCookies(t *testing.T) {
 		},
 		{
 			name:          "空のCookieリスト",
-			cookies:       []*http.Cookie{},
+			cookies:       []*entity.Cookie{},
 			upsertManyErr: nil,
 			wantErr:       false,
 		},
 		{
 			name: "複数ドメインのCookieを保存できる",
-			cookies: []*http.Cookie{
+			cookies: []*entity.Cookie{
 				{Name: "cookie1", Value: "value1", Domain: "example.com"},
 				{Name: "cookie2", Value: "value2", Domain: "another.com"},
 			},
@@ -87,13 +170,13 @@ func TestCookieUsecase_StoreCookies(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockRepo := &mockCookieRepository{
-				upsertManyFunc: func(ctx context.Context, host string, cookies []*entity.Cookie, updatedAt time.Time) error {
+				upsertManyFunc: func(ctx context.Context, cookies []*entity.Cookie, updatedAt time.Time) error {
 					return tt.upsertManyErr
 				},
 			}
 
-			uc := NewCookieUsecase(mockRepo)
-			err := uc.StoreCookies(context.Background(), tt.cookies)
+			uc := NewCookieUsecase(mockRepo, nil, CookieLimits{})
+			err := uc.StoreCookies(context.Background(), "", tt.cookies)
 
 			if (err != nil) != tt.wantErr {
 				t.Errorf("StoreCookies() error = %v, wantErr %v", err, tt.wantErr)
@@ -134,6 +217,15 @@ func TestCookieUsecase_GetAllCookies(t *testing.T) {
 			wantErr:       false,
 			wantLen:       0,
 		},
+		{
+			name: "プレフィックスの要件を満たさないCookieは返さない",
+			findAllResult: []*entity.Cookie{
+				{Name: "__Host-sid", Value: "v", Domain: "example.com", Path: "/", HostOnly: true, Secure: true},
+				{Name: "__Host-bad", Value: "v", Domain: "example.com", Path: "/", Secure: true},
+				{Name: "__Secure-bad", Value: "v", Domain: "example.com", Path: "/"},
+			},
+			wantLen: 1,
+		},
 	}
 
 	for _, tt := range tests {
@@ -144,8 +236,8 @@ func TestCookieUsecase_GetAllCookies(t *testing.T) {
 				},
 			}
 
-			uc := NewCookieUsecase(mockRepo)
-			result, err := uc.GetAllCookies(context.Background())
+			uc := NewCookieUsecase(mockRepo, nil, CookieLimits{})
+			result, err := uc.GetAllCookies(context.Background(), "")
 
 			if (err != nil) != tt.wantErr {
 				t.Errorf("GetAllCookies() error = %v, wantErr %v", err, tt.wantErr)
@@ -157,3 +249,644 @@ func TestCookieUsecase_GetAllCookies(t *testing.T) {
 		})
 	}
 }
+
+func TestCookieUsecase_GetCookiesByHost(t *testing.T) {
+	tests := []struct {
+		name    string
+		host    string
+		result  []*entity.Cookie
+		findErr error
+		wantErr bool
+		wantLen int
+	}{
+		{
+			name: "親ドメインのCookieも取得できる",
+			host: "api.example.com",
+			result: []*entity.Cookie{
+				{Name: "cookie1", Value: "value1", Domain: ".example.com"},
+				{Name: "cookie2", Value: "value2", Domain: "api.example.com"},
+			},
+			wantLen: 2,
+		},
+		{
+			name:    "FindByDomainMatchでエラーが発生",
+			host:    "example.com",
+			findErr: errors.New("find error"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotHost string
+			mockRepo := &mockCookieRepository{
+				findByDomainMatchFunc: func(ctx context.Context, host string) ([]*entity.Cookie, error) {
+					gotHost = host
+					return tt.result, tt.findErr
+				},
+			}
+
+			uc := NewCookieUsecase(mockRepo, nil, CookieLimits{})
+			result, err := uc.GetCookiesByHost(context.Background(), "", tt.host, "")
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetCookiesByHost() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if gotHost != tt.host {
+				t.Errorf("FindByDomainMatch() host = %v, want %v", gotHost, tt.host)
+			}
+			if !tt.wantErr && len(result) != tt.wantLen {
+				t.Errorf("GetCookiesByHost() len = %v, want %v", len(result), tt.wantLen)
+			}
+		})
+	}
+}
+
+func TestCookieUsecase_GetCookiesForURL(t *testing.T) {
+	stored := []*entity.Cookie{
+		{Name: "root", Value: "v", Domain: ".example.com", Path: "/"},
+		{Name: "api", Value: "v", Domain: ".example.com", Path: "/api"},
+		{Name: "secure", Value: "v", Domain: ".example.com", Path: "/", Secure: true},
+	}
+
+	tests := []struct {
+		name      string
+		url       string
+		findErr   error
+		wantErr   bool
+		wantNames []string
+	}{
+		{
+			name:      "ルートパスではapiスコープのCookieを返さない",
+			url:       "https://example.com/",
+			wantNames: []string{"root", "secure"},
+		},
+		{
+			name:      "apiパスではすべてのCookieを返す",
+			url:       "https://api.example.com/api/users",
+			wantNames: []string{"root", "api", "secure"},
+		},
+		{
+			name:      "httpではSecureなCookieを返さない",
+			url:       "http://example.com/api",
+			wantNames: []string{"root", "api"},
+		},
+		{
+			name:    "FindByDomainMatchでエラーが発生",
+			url:     "https://example.com/",
+			findErr: errors.New("find error"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mockCookieRepository{
+				findByDomainMatchFunc: func(ctx context.Context, host string) ([]*entity.Cookie, error) {
+					return stored, tt.findErr
+				},
+			}
+
+			u, err := url.Parse(tt.url)
+			if err != nil {
+				t.Fatalf("Failed to parse url: %v", err)
+			}
+
+			uc := NewCookieUsecase(mockRepo, nil, CookieLimits{})
+			result, err := uc.GetCookiesForURL(context.Background(), "", u, "")
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetCookiesForURL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(result) != len(tt.wantNames) {
+				t.Fatalf("GetCookiesForURL() len = %v, want %v", len(result), len(tt.wantNames))
+			}
+			for i, cookie := range result {
+				if cookie.Name != tt.wantNames[i] {
+					t.Errorf("GetCookiesForURL()[%d].Name = %v, want %v", i, cookie.Name, tt.wantNames[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCookieUsecase_GetCookiesByHost_Expired(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	mockRepo := &mockCookieRepository{
+		findByDomainMatchFunc: func(ctx context.Context, host string) ([]*entity.Cookie, error) {
+			return []*entity.Cookie{
+				{Name: "session", Value: "v", Domain: "example.com"},
+				{Name: "valid", Value: "v", Domain: "example.com", Expires: now.Add(time.Minute)},
+				{Name: "expired", Value: "v", Domain: "example.com", Expires: now.Add(-time.Minute)},
+			}, nil
+		},
+	}
+
+	uc := &cookieUsecase{cookieRepo: mockRepo, now: func() time.Time { return now }}
+	result, err := uc.GetCookiesByHost(context.Background(), "", "example.com", "")
+	if err != nil {
+		t.Fatalf("GetCookiesByHost() error = %v", err)
+	}
+
+	wantNames := []string{"session", "valid"}
+	if len(result) != len(wantNames) {
+		t.Fatalf("GetCookiesByHost() len = %v, want %v", len(result), len(wantNames))
+	}
+	for i, cookie := range result {
+		if cookie.Name != wantNames[i] {
+			t.Errorf("GetCookiesByHost()[%d].Name = %v, want %v", i, cookie.Name, wantNames[i])
+		}
+	}
+}
+
+func TestCookieUsecase_GetCookiesForURL_InvalidPrefix(t *testing.T) {
+	mockRepo := &mockCookieRepository{
+		findByDomainMatchFunc: func(ctx context.Context, host string) ([]*entity.Cookie, error) {
+			return []*entity.Cookie{
+				{Name: "__Host-valid", Value: "v", Domain: "www.example.com", Path: "/", Secure: true, HostOnly: true},
+				{Name: "__Host-domain", Value: "v", Domain: "example.com", Path: "/", Secure: true},
+				{Name: "__Secure-insecure", Value: "v", Domain: "example.com", Path: "/"},
+				{Name: "plain", Value: "v", Domain: "example.com", Path: "/"},
+			}, nil
+		},
+	}
+
+	uc := NewCookieUsecase(mockRepo, nil, CookieLimits{})
+	requestURL, _ := url.Parse("https://www.example.com/")
+	result, err := uc.GetCookiesForURL(context.Background(), "", requestURL, "")
+	if err != nil {
+		t.Fatalf("GetCookiesForURL() error = %v", err)
+	}
+
+	wantNames := []string{"__Host-valid", "plain"}
+	if len(result) != len(wantNames) {
+		t.Fatalf("GetCookiesForURL() len = %v, want %v", len(result), len(wantNames))
+	}
+	for i, cookie := range result {
+		if cookie.Name != wantNames[i] {
+			t.Errorf("GetCookiesForURL()[%d].Name = %v, want %v", i, cookie.Name, wantNames[i])
+		}
+	}
+}
+
+func TestCookieUsecase_GetCookies_Partition(t *testing.T) {
+	stored := []*entity.Cookie{
+		{Name: "sid", Value: "unpartitioned", Domain: "example.com", Path: "/"},
+		{Name: "chips", Value: "a", Domain: "example.com", Path: "/", Secure: true, Partitioned: true, PartitionKey: "https://a.example"},
+		{Name: "chips", Value: "b", Domain: "example.com", Path: "/", Secure: true, Partitioned: true, PartitionKey: "https://b.example"},
+	}
+
+	tests := []struct {
+		name         string
+		topLevelSite string
+		wantHeader   string
+	}{
+		{
+			name:       "トップレベルサイトの指定がない場合はパーティションキーのないCookieのみを返す",
+			wantHeader: "sid=unpartitioned",
+		},
+		{
+			name:         "指定したトップレベルサイトのパーティションのCookieのみを返す",
+			topLevelSite: "https://a.example",
+			wantHeader:   "sid=unpartitioned; chips=a",
+		},
+		{
+			name:         "別のトップレベルサイトでは同じ名前でもそのパーティションの値を返す",
+			topLevelSite: "https://b.example",
+			wantHeader:   "sid=unpartitioned; chips=b",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mockCookieRepository{
+				findByDomainMatchFunc: func(ctx context.Context, host string) ([]*entity.Cookie, error) {
+					return stored, nil
+				},
+			}
+			uc := NewCookieUsecase(mockRepo, nil, CookieLimits{})
+
+			byHost, err := uc.GetCookiesByHost(context.Background(), "", "example.com", tt.topLevelSite)
+			if err != nil {
+				t.Fatalf("GetCookiesByHost() error = %v", err)
+			}
+			if got := entity.CookieHeader(byHost); got != tt.wantHeader {
+				t.Errorf("GetCookiesByHost() header = %q, want %q", got, tt.wantHeader)
+			}
+
+			forURL, err := uc.GetCookiesForURL(context.Background(), "", &url.URL{Scheme: "https", Host: "example.com", Path: "/"}, tt.topLevelSite)
+			if err != nil {
+				t.Fatalf("GetCookiesForURL() error = %v", err)
+			}
+			if got := entity.CookieHeader(forURL); got != tt.wantHeader {
+				t.Errorf("GetCookiesForURL() header = %q, want %q", got, tt.wantHeader)
+			}
+		})
+	}
+}
+
+func TestCookieUsecase_GetCookiesForURL_Touch(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	accessedBefore := now.Add(-time.Hour)
+
+	tests := []struct {
+		name               string
+		touchErr           error
+		wantLastAccessedAt time.Time
+	}{
+		{
+			name:               "返却するCookieの最終アクセス時刻を更新する",
+			wantLastAccessedAt: now,
+		},
+		{
+			name:               "更新に失敗してもCookieを返す",
+			touchErr:           errors.New("touch error"),
+			wantLastAccessedAt: accessedBefore,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var touched []entity.CookieKey
+			mockRepo := &mockCookieRepository{
+				findByDomainMatchFunc: func(ctx context.Context, host string) ([]*entity.Cookie, error) {
+					return []*entity.Cookie{
+						{Name: "sid", Value: "v", Domain: "example.com", Path: "/", LastAccessedAt: accessedBefore},
+						{Name: "other", Value: "v", Domain: "example.com", Path: "/other", LastAccessedAt: accessedBefore},
+					}, nil
+				},
+				touchFunc: func(ctx context.Context, keys []entity.CookieKey, accessedAt time.Time) (int, error) {
+					if !accessedAt.Equal(now) {
+						t.Errorf("Touch() accessedAt = %v, want %v", accessedAt, now)
+					}
+					touched = keys
+					return len(keys), tt.touchErr
+				},
+			}
+
+			uc := &cookieUsecase{cookieRepo: mockRepo, now: func() time.Time { return now }}
+			requestURL, _ := url.Parse("https://www.example.com/")
+			result, err := uc.GetCookiesForURL(context.Background(), "", requestURL, "")
+			if err != nil {
+				t.Fatalf("GetCookiesForURL() error = %v", err)
+			}
+
+			// path-match しない Cookie は返却されず、最終アクセス時刻も更新しない
+			if len(touched) != 1 || touched[0].Name != "sid" {
+				t.Errorf("Touch() keys = %+v, want only sid", touched)
+			}
+			if len(result) != 1 {
+				t.Fatalf("GetCookiesForURL() len = %v, want 1", len(result))
+			}
+			if !result[0].LastAccessedAt.Equal(tt.wantLastAccessedAt) {
+				t.Errorf("LastAccessedAt = %v, want %v", result[0].LastAccessedAt, tt.wantLastAccessedAt)
+			}
+		})
+	}
+}
+
+func TestCookieUsecase_StoreCookies_SingleTransaction(t *testing.T) {
+	tests := []struct {
+		name          string
+		upsertManyErr error
+		wantErr       bool
+		// 上限の確認（Count）を行うドメインの順序（"" は jar 全体）
+		wantDomains []string
+	}{
+		{
+			name:        "すべてのホストを1回の UpsertMany で保存し、正規化したドメイン順に上限を確認する",
+			wantDomains: []string{"a.example.com", "b.example.com", "c.example.com", ""},
+		},
+		{
+			name:          "保存に失敗した場合はエラーを返し上限を確認しない",
+			upsertManyErr: errors.New("upsert error"),
+			wantErr:       true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int
+			var stored []*entity.Cookie
+			var domains []string
+			mockRepo := &mockCookieRepository{}
+			mockRepo.upsertManyFunc = func(ctx context.Context, cookies []*entity.Cookie, updatedAt time.Time) error {
+				if !mockRepo.inTx {
+					t.Error("UpsertMany() called outside of transaction")
+				}
+				calls++
+				stored = cookies
+				return tt.upsertManyErr
+			}
+			mockRepo.countFunc = func(ctx context.Context, domain string) (int, error) {
+				if !mockRepo.inTx {
+					t.Errorf("Count(%s) called outside of transaction", domain)
+				}
+				domains = append(domains, domain)
+				return 0, nil
+			}
+
+			cookies := []*entity.Cookie{
+				{Name: "cookie3", Value: "value3", Domain: "c.example.com"},
+				{Name: "cookie1", Value: "value1", Domain: ".B.example.com"},
+				{Name: "cookie2", Value: "value2", Domain: "a.example.com"},
+				{Name: "cookie4", Value: "value4", Domain: "b.example.com"},
+				{Name: "cookie5", Value: "value5", Domain: "B.Example.com"},
+			}
+			uc := NewCookieUsecase(mockRepo, nil, CookieLimits{MaxCookiesPerDomain: 180, MaxCookies: 3000})
+			err := uc.StoreCookies(context.Background(), "", cookies)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("StoreCookies() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if calls != 1 || len(stored) != len(cookies) {
+				t.Errorf("UpsertMany() calls = %v with %d cookies, want 1 call with %d cookies", calls, len(stored), len(cookies))
+			}
+			if !slices.Equal(domains, tt.wantDomains) {
+				t.Errorf("Count() domains = %q, want %q", domains, tt.wantDomains)
+			}
+		})
+	}
+}
+
+func TestCookieUsecase_StoreCookies_PublicSuffix(t *testing.T) {
+	tests := []struct {
+		name    string
+		cookie  *entity.Cookie
+		wantErr bool
+	}{
+		{
+			name:   "登録可能なドメインは保存する",
+			cookie: &entity.Cookie{Name: "sid", Value: "abc", Domain: "example.co.uk"},
+		},
+		{
+			name:    "ICANNセクションの公開サフィックスは拒否する",
+			cookie:  &entity.Cookie{Name: "sid", Value: "abc", Domain: "co.uk"},
+			wantErr: true,
+		},
+		{
+			name:    "プライベートセクションの公開サフィックスは拒否する",
+			cookie:  &entity.Cookie{Name: "sid", Value: "abc", Domain: ".github.io"},
+			wantErr: true,
+		},
+		{
+			name:   "公開サフィックスのhost-only Cookieは保存する",
+			cookie: &entity.Cookie{Name: "sid", Value: "abc", Domain: "github.io", HostOnly: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			upserted := false
+			mockRepo := &mockCookieRepository{
+				upsertManyFunc: func(ctx context.Context, cookies []*entity.Cookie, updatedAt time.Time) error {
+					upserted = true
+					return nil
+				},
+			}
+
+			uc := NewCookieUsecase(mockRepo, publicsuffix.Default(), CookieLimits{})
+			err := uc.StoreCookies(context.Background(), "", []*entity.Cookie{
+				{Name: "other", Value: "1", Domain: "example.com"},
+				tt.cookie,
+			})
+
+			if tt.wantErr {
+				if !errors.Is(err, entity.ErrPublicSuffix) {
+					t.Errorf("StoreCookies() error = %v, want ErrPublicSuffix", err)
+				}
+				if upserted {
+					t.Error("UpsertMany() called, want no cookies stored")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("StoreCookies() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestCookieUsecase_StoreSetCookies(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var stored []*entity.Cookie
+	mockRepo := &mockCookieRepository{}
+	mockRepo.upsertManyFunc = func(ctx context.Context, cookies []*entity.Cookie, updatedAt time.Time) error {
+		if !mockRepo.inTx {
+			t.Error("UpsertMany() called outside of transaction")
+		}
+		stored = append(stored, cookies...)
+		return nil
+	}
+
+	uc := &cookieUsecase{cookieRepo: mockRepo, now: func() time.Time { return now }}
+	requestURL, _ := url.Parse("http://www.example.com/docs/index.html")
+	result, err := uc.StoreSetCookies(context.Background(), "", requestURL, []string{
+		"sid=abc; Max-Age=3600",
+		"secure=1; Secure",
+		"lang=ja; Domain=example.com; Path=/",
+		"other=1; Domain=other.com",
+	})
+	if err != nil {
+		t.Fatalf("StoreSetCookies() error = %v", err)
+	}
+
+	if result.Accepted != 2 {
+		t.Errorf("Stored = %v, want 2", result.Accepted)
+	}
+	wantRejected := []int{1, 3}
+	if len(result.Rejected) != len(wantRejected) {
+		t.Fatalf("Rejected = %+v, want indexes %v", result.Rejected, wantRejected)
+	}
+	for i, r := range result.Rejected {
+		if r.Index != wantRejected[i] {
+			t.Errorf("Rejected[%d].Index = %v, want %v", i, r.Index, wantRejected[i])
+		}
+		if !errors.Is(r.Err, entity.ErrCookieRejected) {
+			t.Errorf("Rejected[%d].Err = %v, want ErrCookieRejected", i, r.Err)
+		}
+	}
+
+	if len(stored) != 2 {
+		t.Fatalf("stored len = %v, want 2", len(stored))
+	}
+	// ヘッダーの順に保存される
+	if got := stored[0]; got.Name != "sid" || got.Domain != "www.example.com" || !got.HostOnly || got.Path != "/docs" {
+		t.Errorf("stored[0] = %+v, want host-only cookie sid for www.example.com/docs", got)
+	}
+	if want := now.Add(time.Hour); !stored[0].Expires.Equal(want) {
+		t.Errorf("stored[0].Expires = %v, want %v", stored[0].Expires, want)
+	}
+	if got := stored[1]; got.Name != "lang" || got.Domain != "example.com" || got.HostOnly {
+		t.Errorf("stored[1] = %+v, want domain cookie lang for example.com", got)
+	}
+}
+
+func TestCookieUsecase_ReplaySetCookies(t *testing.T) {
+	now := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+	receivedAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	mockRepo := &mockCookieRepository{
+		upsertManyFunc: func(ctx context.Context, cookies []*entity.Cookie, updatedAt time.Time) error {
+			t.Error("UpsertMany() called, want ReplaySetCookies not to store cookies")
+			return nil
+		},
+	}
+
+	uc := &cookieUsecase{cookieRepo: mockRepo, now: func() time.Time { return now }}
+	login, _ := url.Parse("https://example.com/login")
+	logout, _ := url.Parse("https://example.com/logout")
+	cookies, results := uc.ReplaySetCookies(context.Background(), []SetCookieResponse{
+		{URL: login, ReceivedAt: receivedAt, Headers: []string{"sid=abc; Path=/", "theme=dark; Path=/; Max-Age=60", "bad=1; Domain=other.com"}},
+		{URL: logout, ReceivedAt: receivedAt.Add(time.Second), Headers: []string{"sid=; Path=/; Max-Age=0"}},
+	})
+
+	if len(results) != 2 || results[0].Accepted != 2 || len(results[0].Rejected) != 1 || results[1].Accepted != 1 {
+		t.Fatalf("ReplaySetCookies() results = %+v, %+v", results[0], results[1])
+	}
+
+	// 後のレスポンスで削除された sid は失効した状態で返され、Max-Age は受信時刻を基準にする
+	if len(cookies) != 2 {
+		t.Fatalf("ReplaySetCookies() cookies len = %v, want 2", len(cookies))
+	}
+	for _, cookie := range cookies {
+		switch cookie.Name {
+		case "sid":
+			if !cookie.IsExpired(now) {
+				t.Errorf("sid should be expired, Expires = %v", cookie.Expires)
+			}
+		case "theme":
+			if want := receivedAt.Add(time.Minute); !cookie.Expires.Equal(want) {
+				t.Errorf("theme.Expires = %v, want %v", cookie.Expires, want)
+			}
+		default:
+			t.Errorf("unexpected cookie %v", cookie.Name)
+		}
+	}
+}
+
+func TestCookieUsecase_DeleteCookies(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		filter      repository.CookieFilter
+		deleteErr   error
+		wantErr     error
+		wantDeleted int
+		wantCalled  bool
+	}{
+		{
+			name:        "条件に一致するCookieを削除する",
+			filter:      repository.CookieFilter{NamePrefix: "_ga", ExpiredOnly: true},
+			wantDeleted: 2,
+			wantCalled:  true,
+		},
+		{
+			name:       "条件なしは全件削除せずエラーを返す",
+			filter:     repository.CookieFilter{},
+			wantErr:    ErrEmptyFilter,
+			wantCalled: false,
+		},
+		{
+			name:       "DeleteByFilterでエラーが発生",
+			filter:     repository.CookieFilter{Domain: "example.com"},
+			deleteErr:  errors.New("delete error"),
+			wantErr:    errors.New("delete error"),
+			wantCalled: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			mockRepo := &mockCookieRepository{
+				deleteByFilterFunc: func(ctx context.Context, filter repository.CookieFilter, gotNow time.Time) (int, error) {
+					called = true
+					if filter != tt.filter {
+						t.Errorf("DeleteByFilter() filter = %+v, want %+v", filter, tt.filter)
+					}
+					if !gotNow.Equal(now) {
+						t.Errorf("DeleteByFilter() now = %v, want %v", gotNow, now)
+					}
+					if tt.deleteErr != nil {
+						return 0, tt.deleteErr
+					}
+					return 2, nil
+				},
+			}
+
+			uc := &cookieUsecase{cookieRepo: mockRepo, now: func() time.Time { return now }}
+			deleted, err := uc.DeleteCookies(context.Background(), "", tt.filter)
+
+			if (err != nil) != (tt.wantErr != nil) {
+				t.Fatalf("DeleteCookies() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if errors.Is(tt.wantErr, ErrEmptyFilter) && !errors.Is(err, ErrEmptyFilter) {
+				t.Errorf("DeleteCookies() error = %v, want %v", err, ErrEmptyFilter)
+			}
+			if called != tt.wantCalled {
+				t.Errorf("DeleteByFilter() called = %v, want %v", called, tt.wantCalled)
+			}
+			if deleted != tt.wantDeleted {
+				t.Errorf("DeleteCookies() = %v, want %v", deleted, tt.wantDeleted)
+			}
+		})
+	}
+}
+
+func TestCookieUsecase_Jar(t *testing.T) {
+	tests := []struct {
+		name    string
+		jar     string
+		wantJar string
+		wantErr error
+	}{
+		{
+			name:    "jar を指定しない場合は既定の jar を使う",
+			jar:     "",
+			wantJar: entity.DefaultJar,
+		},
+		{
+			name:    "指定した jar を使う",
+			jar:     "bot-1",
+			wantJar: "bot-1",
+		},
+		{
+			name:    "不正な jar の名前はリポジトリを呼び出さずにエラーを返す",
+			jar:     "../other",
+			wantErr: entity.ErrInvalidJarName,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &mockCookieRepository{
+				findAllFunc: func(ctx context.Context) ([]*entity.Cookie, error) {
+					return nil, nil
+				},
+			}
+			uc := NewCookieUsecase(mockRepo, nil, DefaultCookieLimits())
+
+			if err := uc.StoreCookies(context.Background(), tt.jar, []*entity.Cookie{{Name: "sid", Value: "v", Domain: "example.com"}}); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("StoreCookies() error = %v, want %v", err, tt.wantErr)
+			}
+			if mockRepo.jar != tt.wantJar {
+				t.Errorf("StoreCookies() jar = %q, want %q", mockRepo.jar, tt.wantJar)
+			}
+
+			mockRepo.jar = ""
+			if _, err := uc.GetAllCookies(context.Background(), tt.jar); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetAllCookies() error = %v, want %v", err, tt.wantErr)
+			}
+			if mockRepo.jar != tt.wantJar {
+				t.Errorf("GetAllCookies() jar = %q, want %q", mockRepo.jar, tt.wantJar)
+			}
+		})
+	}
+}
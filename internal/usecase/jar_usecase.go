@@ -0,0 +1,195 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/takumi3488/cookiejar-server/internal/domain/entity"
+	"github.com/takumi3488/cookiejar-server/internal/domain/repository"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ErrDeleteDefaultJar は既定の jar を削除しようとしたことを示すエラーです
+var ErrDeleteDefaultJar = errors.New("default jar cannot be deleted")
+
+// JarUsecase は jar を管理します
+// jar の管理はすべてのホストの Cookie に影響するため、API キーやトークンのスコープで全ホスト（"*"）に対する権限が必要です
+// jar を限定したトークンは、許可された jar のみ管理できます
+type JarUsecase interface {
+	// CreateJar は空の jar を作成します（write 権限が必要）
+	CreateJar(ctx context.Context, name string) (*entity.Jar, error)
+	// ListJars は操作を許可されたすべての jar を Cookie の数とともに返します（read 権限が必要）
+	ListJars(ctx context.Context) ([]*entity.Jar, error)
+	// CloneJar は src の Cookie をすべて複製した jar dst を作成します（read と write 権限が必要）
+	CloneJar(ctx context.Context, src, dst string) (*entity.Jar, error)
+	// DeleteJar は jar とその Cookie をすべて削除します（delete 権限が必要）
+	DeleteJar(ctx context.Context, name string) error
+}
+
+type jarUsecase struct {
+	jarRepo repository.JarRepository
+	now     func() time.Time
+}
+
+func NewJarUsecase(jarRepo repository.JarRepository) JarUsecase {
+	return &jarUsecase{
+		jarRepo: jarRepo,
+		now:     time.Now,
+	}
+}
+
+func (u *jarUsecase) CreateJar(ctx context.Context, name string) (*entity.Jar, error) {
+	tracer := otel.Tracer("cookiejar-server/usecase")
+	ctx, span := tracer.Start(ctx, "CreateJar", trace.WithSpanKind(trace.SpanKindInternal))
+	defer span.End()
+
+	span.SetAttributes(attribute.String("cookie.jar", name))
+
+	if err := entity.ValidateJarName(name); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Invalid jar name")
+		return nil, err
+	}
+	if err := authorizeJar(ctx, span, name); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Forbidden")
+		return nil, err
+	}
+	if err := authorize(ctx, span, entity.PermissionWrite, entity.AllHosts); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Forbidden")
+		return nil, err
+	}
+
+	jar, err := u.jarRepo.Create(ctx, name, u.now())
+	if err != nil {
+		log.Printf("Failed to create jar=%s: %v", name, err)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to create jar")
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "Successfully created jar")
+	return jar, nil
+}
+
+func (u *jarUsecase) ListJars(ctx context.Context) ([]*entity.Jar, error) {
+	tracer := otel.Tracer("cookiejar-server/usecase")
+	ctx, span := tracer.Start(ctx, "ListJars", trace.WithSpanKind(trace.SpanKindInternal))
+	defer span.End()
+
+	if err := authorize(ctx, span, entity.PermissionRead, entity.AllHosts); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Forbidden")
+		return nil, err
+	}
+
+	jars, err := u.jarRepo.FindAll(ctx)
+	if err != nil {
+		log.Printf("Failed to list jars: %v", err)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to list jars")
+		return nil, err
+	}
+	jars = filterJars(ctx, jars)
+
+	span.SetAttributes(attribute.Int("cookie.jar_count", len(jars)))
+	span.SetStatus(codes.Ok, "Successfully listed jars")
+	return jars, nil
+}
+
+func (u *jarUsecase) CloneJar(ctx context.Context, src, dst string) (*entity.Jar, error) {
+	tracer := otel.Tracer("cookiejar-server/usecase")
+	ctx, span := tracer.Start(ctx, "CloneJar", trace.WithSpanKind(trace.SpanKindInternal))
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("cookie.jar.source", src),
+		attribute.String("cookie.jar", dst),
+	)
+
+	for _, name := range []string{src, dst} {
+		if err := entity.ValidateJarName(name); err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "Invalid jar name")
+			return nil, err
+		}
+	}
+	for _, name := range []string{src, dst} {
+		if err := authorizeJar(ctx, span, name); err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "Forbidden")
+			return nil, err
+		}
+	}
+	for _, permission := range []entity.Permission{entity.PermissionRead, entity.PermissionWrite} {
+		if err := authorize(ctx, span, permission, entity.AllHosts); err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "Forbidden")
+			return nil, err
+		}
+	}
+
+	jar, err := u.jarRepo.Clone(ctx, src, dst, u.now())
+	if err != nil {
+		log.Printf("Failed to clone jar=%s into jar=%s: %v", src, dst, err)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to clone jar")
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.Int("cookie.count", jar.CookieCount))
+	span.SetStatus(codes.Ok, "Successfully cloned jar")
+	return jar, nil
+}
+
+func (u *jarUsecase) DeleteJar(ctx context.Context, name string) error {
+	tracer := otel.Tracer("cookiejar-server/usecase")
+	ctx, span := tracer.Start(ctx, "DeleteJar", trace.WithSpanKind(trace.SpanKindInternal))
+	defer span.End()
+
+	span.SetAttributes(attribute.String("cookie.jar", name))
+
+	if err := entity.ValidateJarName(name); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Invalid jar name")
+		return err
+	}
+	// jar を指定しないリクエストの保存先がなくなるため、既定の jar は削除できない
+	if name == entity.DefaultJar {
+		span.RecordError(ErrDeleteDefaultJar)
+		span.SetStatus(codes.Error, "Default jar")
+		return ErrDeleteDefaultJar
+	}
+	if err := authorizeJar(ctx, span, name); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Forbidden")
+		return err
+	}
+	if err := authorize(ctx, span, entity.PermissionDelete, entity.AllHosts); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Forbidden")
+		return err
+	}
+
+	deleted, err := u.jarRepo.Delete(ctx, name)
+	if err != nil {
+		log.Printf("Failed to delete jar=%s: %v", name, err)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to delete jar")
+		return err
+	}
+	if deleted == 0 {
+		span.SetStatus(codes.Error, "Jar not found")
+		return fmt.Errorf("%w: %q", entity.ErrJarNotFound, name)
+	}
+
+	span.SetStatus(codes.Ok, "Successfully deleted jar")
+	return nil
+}
@@ -0,0 +1,290 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/takumi3488/cookiejar-server/internal/domain/entity"
+)
+
+// モックリポジトリ（名前をキーとしたメモリ上の jar）
+type mockJarRepository struct {
+	jars map[string]*entity.Jar
+	err  error
+}
+
+func newMockJarRepository(names ...string) *mockJarRepository {
+	m := &mockJarRepository{jars: make(map[string]*entity.Jar)}
+	for _, name := range names {
+		m.jars[name] = &entity.Jar{Name: name}
+	}
+	return m
+}
+
+func (m *mockJarRepository) Create(ctx context.Context, name string, createdAt time.Time) (*entity.Jar, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	if _, ok := m.jars[name]; ok {
+		return nil, entity.ErrJarExists
+	}
+	jar := &entity.Jar{Name: name, CreatedAt: createdAt}
+	m.jars[name] = jar
+	return jar, nil
+}
+
+func (m *mockJarRepository) Find(ctx context.Context, name string) (*entity.Jar, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.jars[name], nil
+}
+
+func (m *mockJarRepository) FindAll(ctx context.Context) ([]*entity.Jar, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	result := make([]*entity.Jar, 0, len(m.jars))
+	for _, jar := range m.jars {
+		result = append(result, jar)
+	}
+	return result, nil
+}
+
+func (m *mockJarRepository) Delete(ctx context.Context, name string) (int, error) {
+	if m.err != nil {
+		return 0, m.err
+	}
+	if _, ok := m.jars[name]; !ok {
+		return 0, nil
+	}
+	delete(m.jars, name)
+	return 1, nil
+}
+
+func (m *mockJarRepository) Clone(ctx context.Context, src, dst string, createdAt time.Time) (*entity.Jar, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	source, ok := m.jars[src]
+	if !ok {
+		return nil, entity.ErrJarNotFound
+	}
+	jar, err := m.Create(ctx, dst, createdAt)
+	if err != nil {
+		return nil, err
+	}
+	jar.CookieCount = source.CookieCount
+	return jar, nil
+}
+
+// fullAccessTokenContext はすべてのホストに対するすべての操作を許可し、jars の jar のみを操作できるトークンを設定したコンテキストを返します
+func fullAccessTokenContext(jars ...string) func(t *testing.T) context.Context {
+	return func(t *testing.T) context.Context {
+		return ContextWithTokenClaims(context.Background(), &entity.TokenClaims{
+			Subject: "bot",
+			Scopes:  entity.FullAccessScopes(),
+			Jars:    jars,
+		})
+	}
+}
+
+func TestJarUsecase_ListJars(t *testing.T) {
+	repo := newMockJarRepository(entity.DefaultJar, "bot-1", "bot-2")
+	uc := NewJarUsecase(repo)
+
+	jars, err := uc.ListJars(context.Background())
+	if err != nil || len(jars) != 3 {
+		t.Fatalf("ListJars() = %v, %v, want 3 jars", jars, err)
+	}
+
+	// トークンで許可された jar のみを返す
+	jars, err = uc.ListJars(fullAccessTokenContext("bot-1")(t))
+	if err != nil || len(jars) != 1 || jars[0].Name != "bot-1" {
+		t.Errorf("ListJars() = %v, %v, want only bot-1", jars, err)
+	}
+}
+
+func TestJarUsecase_CreateJar(t *testing.T) {
+	tests := []struct {
+		name    string
+		jar     string
+		ctx     func(t *testing.T) context.Context
+		wantErr error
+	}{
+		{
+			name: "jar を作成できる",
+			jar:  "bot-1",
+		},
+		{
+			name:    "同じ名前の jar は作成できない",
+			jar:     entity.DefaultJar,
+			wantErr: entity.ErrJarExists,
+		},
+		{
+			name:    "不正な jar の名前",
+			jar:     "bot/1",
+			wantErr: entity.ErrInvalidJarName,
+		},
+		{
+			name:    "全ホストへの書き込みが許可されていない API キー",
+			jar:     "bot-1",
+			ctx:     billingContext,
+			wantErr: ErrForbidden,
+		},
+		{
+			name: "トークンで許可された jar",
+			jar:  "bot-1",
+			ctx:  fullAccessTokenContext("bot-1"),
+		},
+		{
+			name:    "トークンで許可されていない jar",
+			jar:     "bot-2",
+			ctx:     fullAccessTokenContext("bot-1"),
+			wantErr: ErrForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMockJarRepository(entity.DefaultJar)
+			uc := NewJarUsecase(repo)
+			ctx := context.Background()
+			if tt.ctx != nil {
+				ctx = tt.ctx(t)
+			}
+
+			jar, err := uc.CreateJar(ctx, tt.jar)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CreateJar() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if jar.Name != tt.jar {
+				t.Errorf("CreateJar() name = %v, want %v", jar.Name, tt.jar)
+			}
+			if repo.jars[tt.jar] == nil {
+				t.Errorf("jar %q was not created", tt.jar)
+			}
+		})
+	}
+}
+
+func TestJarUsecase_CloneJar(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     string
+		dst     string
+		ctx     func(t *testing.T) context.Context
+		wantErr error
+	}{
+		{
+			name: "jar を複製できる",
+			src:  entity.DefaultJar,
+			dst:  "bot-2",
+		},
+		{
+			name:    "複製元の jar が存在しない",
+			src:     "missing",
+			dst:     "bot-2",
+			wantErr: entity.ErrJarNotFound,
+		},
+		{
+			name:    "複製先の jar が存在する",
+			src:     entity.DefaultJar,
+			dst:     "bot-1",
+			wantErr: entity.ErrJarExists,
+		},
+		{
+			name:    "不正な複製先の jar の名前",
+			src:     entity.DefaultJar,
+			dst:     "",
+			wantErr: entity.ErrInvalidJarName,
+		},
+		{
+			name:    "全ホストへの読み取りと書き込みが許可されていない API キー",
+			src:     entity.DefaultJar,
+			dst:     "bot-2",
+			ctx:     billingContext,
+			wantErr: ErrForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMockJarRepository(entity.DefaultJar, "bot-1")
+			repo.jars[entity.DefaultJar].CookieCount = 3
+			uc := NewJarUsecase(repo)
+			ctx := context.Background()
+			if tt.ctx != nil {
+				ctx = tt.ctx(t)
+			}
+
+			jar, err := uc.CloneJar(ctx, tt.src, tt.dst)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CloneJar() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if jar.Name != tt.dst || jar.CookieCount != 3 {
+				t.Errorf("CloneJar() = %+v, want name %v with 3 cookies", jar, tt.dst)
+			}
+		})
+	}
+}
+
+func TestJarUsecase_DeleteJar(t *testing.T) {
+	tests := []struct {
+		name    string
+		jar     string
+		ctx     func(t *testing.T) context.Context
+		wantErr error
+	}{
+		{
+			name: "jar を削除できる",
+			jar:  "bot-1",
+		},
+		{
+			name:    "既定の jar は削除できない",
+			jar:     entity.DefaultJar,
+			wantErr: ErrDeleteDefaultJar,
+		},
+		{
+			name:    "存在しない jar",
+			jar:     "missing",
+			wantErr: entity.ErrJarNotFound,
+		},
+		{
+			name:    "全ホストの削除が許可されていない API キー",
+			jar:     "bot-1",
+			ctx:     billingContext,
+			wantErr: ErrForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMockJarRepository(entity.DefaultJar, "bot-1")
+			uc := NewJarUsecase(repo)
+			ctx := context.Background()
+			if tt.ctx != nil {
+				ctx = tt.ctx(t)
+			}
+
+			err := uc.DeleteJar(ctx, tt.jar)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("DeleteJar() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if _, exists := repo.jars[tt.jar]; exists {
+				t.Errorf("jar %q was not deleted", tt.jar)
+			}
+		})
+	}
+}
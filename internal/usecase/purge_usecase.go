@@ -0,0 +1,94 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/takumi3488/cookiejar-server/internal/domain/repository"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// DefaultPurgeBatchSize は1バッチで削除するCookie数の既定値です
+const DefaultPurgeBatchSize = 100
+
+// PurgeReport は期限切れCookieの削除処理全体の結果です
+type PurgeReport struct {
+	Batches       int
+	PurgedCookies int
+}
+
+type PurgeUsecase interface {
+	PurgeExpired(ctx context.Context) (*PurgeReport, error)
+}
+
+type purgeUsecase struct {
+	cookieRepo repository.CookieRepository
+	batchSize  int
+	now        func() time.Time
+}
+
+func NewPurgeUsecase(cookieRepo repository.CookieRepository, batchSize int) PurgeUsecase {
+	if batchSize <= 0 {
+		batchSize = DefaultPurgeBatchSize
+	}
+	return &purgeUsecase{
+		cookieRepo: cookieRepo,
+		batchSize:  batchSize,
+		now:        time.Now,
+	}
+}
+
+func (u *purgeUsecase) PurgeExpired(ctx context.Context) (*PurgeReport, error) {
+	tracer := otel.Tracer("cookiejar-server/usecase")
+	ctx, span := tracer.Start(ctx, "PurgeExpired", trace.WithSpanKind(trace.SpanKindInternal))
+	defer span.End()
+
+	now := u.now()
+	span.SetAttributes(attribute.Int("purge.batch_size", u.batchSize))
+
+	// 削除件数がバッチサイズに満たなくなるまで繰り返す
+	report := &PurgeReport{}
+	for {
+		purged, err := u.purgeBatch(ctx, now)
+		if err != nil {
+			log.Printf("Failed to purge expired cookies: %v", err)
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "Failed to purge expired cookies")
+			return nil, err
+		}
+
+		report.Batches++
+		report.PurgedCookies += purged
+		if purged < u.batchSize {
+			break
+		}
+	}
+
+	span.SetAttributes(
+		attribute.Int("purge.batches", report.Batches),
+		attribute.Int("purge.purged_cookies", report.PurgedCookies),
+	)
+	span.SetStatus(codes.Ok, "Successfully purged expired cookies")
+	return report, nil
+}
+
+func (u *purgeUsecase) purgeBatch(ctx context.Context, now time.Time) (int, error) {
+	tracer := otel.Tracer("cookiejar-server/usecase")
+	ctx, span := tracer.Start(ctx, "PurgeExpiredBatch", trace.WithSpanKind(trace.SpanKindInternal))
+	defer span.End()
+
+	purged, err := u.cookieRepo.PurgeExpired(ctx, now, u.batchSize)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to purge batch")
+		return 0, err
+	}
+
+	span.SetAttributes(attribute.Int("purge.purged_cookies", purged))
+	span.SetStatus(codes.Ok, "Successfully purged batch")
+	return purged, nil
+}
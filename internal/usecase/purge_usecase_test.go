@@ -0,0 +1,90 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPurgeUsecase_PurgeExpired(t *testing.T) {
+	tests := []struct {
+		name        string
+		batches     []int
+		purgeErr    error
+		wantErr     bool
+		wantReport  *PurgeReport
+		wantBatches int
+	}{
+		{
+			name:        "バッチサイズに満たなくなるまで繰り返す",
+			batches:     []int{2, 2, 1},
+			wantReport:  &PurgeReport{Batches: 3, PurgedCookies: 5},
+			wantBatches: 3,
+		},
+		{
+			name:        "削除対象がない場合は1バッチで終了する",
+			batches:     []int{0},
+			wantReport:  &PurgeReport{Batches: 1},
+			wantBatches: 1,
+		},
+		{
+			name:        "最後のバッチがちょうど空になる",
+			batches:     []int{2, 0},
+			wantReport:  &PurgeReport{Batches: 2, PurgedCookies: 2},
+			wantBatches: 2,
+		},
+		{
+			name:        "PurgeExpiredでエラーが発生",
+			purgeErr:    errors.New("purge error"),
+			wantErr:     true,
+			wantBatches: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+			calls := 0
+			mockRepo := &mockCookieRepository{
+				purgeExpiredFunc: func(ctx context.Context, gotNow time.Time, batchSize int) (int, error) {
+					if !gotNow.Equal(now) {
+						t.Errorf("now = %v, want %v", gotNow, now)
+					}
+					if batchSize != 2 {
+						t.Errorf("batchSize = %v, want 2", batchSize)
+					}
+					calls++
+					if tt.purgeErr != nil {
+						return 0, tt.purgeErr
+					}
+					return tt.batches[calls-1], nil
+				},
+			}
+
+			uc := &purgeUsecase{cookieRepo: mockRepo, batchSize: 2, now: func() time.Time { return now }}
+			report, err := uc.PurgeExpired(context.Background())
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PurgeExpired() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if calls != tt.wantBatches {
+				t.Errorf("PurgeExpired() calls = %v, want %v", calls, tt.wantBatches)
+			}
+			if tt.wantErr {
+				return
+			}
+			if *report != *tt.wantReport {
+				t.Errorf("PurgeExpired() = %+v, want %+v", *report, *tt.wantReport)
+			}
+		})
+	}
+}
+
+func TestNewPurgeUsecase_DefaultBatchSize(t *testing.T) {
+	uc := NewPurgeUsecase(&mockCookieRepository{}, 0).(*purgeUsecase)
+	if uc.batchSize != DefaultPurgeBatchSize {
+		t.Errorf("batchSize = %v, want %v", uc.batchSize, DefaultPurgeBatchSize)
+	}
+}
@@ -0,0 +1,89 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/takumi3488/cookiejar-server/internal/domain/entity"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TokenClaimMapping はトークンのクレームと権限の対応です
+type TokenClaimMapping struct {
+	// ScopeClaim は API キーと同じ形式のスコープ（"read:*.example.com" など）を含むクレームの名前
+	ScopeClaim string
+	// JarClaim は操作を許可する jar の名前（"*" はすべての jar）を含むクレームの名前
+	// クレームがないトークンは既定の jar のみ操作できます
+	JarClaim string
+}
+
+type TokenAuthUsecase interface {
+	// AuthenticateToken は JWT（ベアラートークン）を検証し、有効な場合はトークンの主体とクレームから得た権限を返します
+	AuthenticateToken(ctx context.Context, token string) (*entity.TokenClaims, error)
+}
+
+type tokenAuthUsecase struct {
+	verifier entity.TokenVerifier
+	mapping  TokenClaimMapping
+}
+
+func NewTokenAuthUsecase(verifier entity.TokenVerifier, mapping TokenClaimMapping) TokenAuthUsecase {
+	return &tokenAuthUsecase{
+		verifier: verifier,
+		mapping:  mapping,
+	}
+}
+
+func (u *tokenAuthUsecase) AuthenticateToken(ctx context.Context, token string) (*entity.TokenClaims, error) {
+	tracer := otel.Tracer("cookiejar-server/usecase")
+	ctx, span := tracer.Start(ctx, "AuthenticateToken", trace.WithSpanKind(trace.SpanKindInternal))
+	defer span.End()
+
+	raw, err := u.verifier.Verify(ctx, token)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Token is invalid")
+		return nil, fmt.Errorf("%w: %w", ErrUnauthenticated, err)
+	}
+
+	claims := &entity.TokenClaims{
+		Jars: []string{entity.DefaultJar},
+	}
+	claims.Subject, _ = raw["sub"].(string)
+	claims.Issuer, _ = raw["iss"].(string)
+	if exp, ok := raw["exp"].(float64); ok {
+		claims.ExpiresAt = time.Unix(int64(exp), 0)
+	}
+	if scopes, ok := entity.ClaimStrings(raw, u.mapping.ScopeClaim); ok {
+		claims.Scopes = entity.ParseTokenScopes(scopes)
+	}
+	if jars, ok := entity.ClaimStrings(raw, u.mapping.JarClaim); ok {
+		claims.Jars = jars
+	}
+
+	span.SetAttributes(
+		attribute.String("auth.token_subject", claims.Subject),
+		attribute.String("auth.token_issuer", claims.Issuer),
+		attribute.StringSlice("auth.scopes", entity.ScopeStrings(claims.Scopes)),
+		attribute.StringSlice("auth.jars", claims.Jars),
+	)
+	span.SetStatus(codes.Ok, "Successfully authenticated")
+	return claims, nil
+}
+
+type tokenClaimsContextKey struct{}
+
+// ContextWithTokenClaims は検証済みのトークンのクレームを設定したコンテキストを返します
+func ContextWithTokenClaims(ctx context.Context, claims *entity.TokenClaims) context.Context {
+	return context.WithValue(ctx, tokenClaimsContextKey{}, claims)
+}
+
+// TokenClaimsFromContext はコンテキストから検証済みのトークンのクレームを取得します
+func TokenClaimsFromContext(ctx context.Context) (*entity.TokenClaims, bool) {
+	claims, ok := ctx.Value(tokenClaimsContextKey{}).(*entity.TokenClaims)
+	return claims, ok
+}
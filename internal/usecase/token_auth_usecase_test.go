@@ -0,0 +1,112 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/takumi3488/cookiejar-server/internal/domain/entity"
+)
+
+// mockTokenVerifier はトークンをキーとしたクレームを返します（存在しないトークンは無効）
+type mockTokenVerifier struct {
+	claims map[string]map[string]any
+}
+
+func (m *mockTokenVerifier) Verify(ctx context.Context, token string) (map[string]any, error) {
+	claims, ok := m.claims[token]
+	if !ok {
+		return nil, fmt.Errorf("%w: signature verification failed", entity.ErrInvalidToken)
+	}
+	return claims, nil
+}
+
+func TestTokenAuthUsecase_AuthenticateToken(t *testing.T) {
+	verifier := &mockTokenVerifier{claims: map[string]map[string]any{
+		"scope-string": {
+			"sub":   "bot-1",
+			"iss":   "https://issuer.example.com",
+			"exp":   1.7e9,
+			"scope": "openid read:*.example.com write:api.example.com",
+			"jars":  []any{"bot-1", "bot-2"},
+		},
+		"custom-claims": {
+			"sub":       "bot-2",
+			"cookiejar": []any{"delete:*"},
+			"jar":       "*",
+		},
+		"no-jars": {
+			"sub":   "bot-3",
+			"scope": "read:*",
+		},
+	}}
+
+	tests := []struct {
+		name       string
+		token      string
+		mapping    TokenClaimMapping
+		wantErr    error
+		wantSub    string
+		wantScopes []string
+		wantJars   []string
+	}{
+		{
+			name:       "スペース区切りのスコープと jar の配列",
+			token:      "scope-string",
+			mapping:    TokenClaimMapping{ScopeClaim: "scope", JarClaim: "jars"},
+			wantSub:    "bot-1",
+			wantScopes: []string{"read:*.example.com", "write:api.example.com"},
+			wantJars:   []string{"bot-1", "bot-2"},
+		},
+		{
+			name:       "クレームの名前を変更",
+			token:      "custom-claims",
+			mapping:    TokenClaimMapping{ScopeClaim: "cookiejar", JarClaim: "jar"},
+			wantSub:    "bot-2",
+			wantScopes: []string{"delete:*"},
+			wantJars:   []string{entity.AllJars},
+		},
+		{
+			name:       "jar のクレームがない場合は既定の jar のみ",
+			token:      "no-jars",
+			mapping:    TokenClaimMapping{ScopeClaim: "scope", JarClaim: "jars"},
+			wantSub:    "bot-3",
+			wantScopes: []string{"read:*"},
+			wantJars:   []string{entity.DefaultJar},
+		},
+		{
+			name:    "無効なトークン",
+			token:   "invalid",
+			mapping: TokenClaimMapping{ScopeClaim: "scope", JarClaim: "jars"},
+			wantErr: ErrUnauthenticated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := NewTokenAuthUsecase(verifier, tt.mapping)
+
+			got, err := uc.AuthenticateToken(context.Background(), tt.token)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) || !errors.Is(err, entity.ErrInvalidToken) {
+					t.Errorf("AuthenticateToken() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("AuthenticateToken() error = %v", err)
+			}
+			if got.Subject != tt.wantSub {
+				t.Errorf("Subject = %v, want %v", got.Subject, tt.wantSub)
+			}
+			if scopes := entity.ScopeStrings(got.Scopes); !slices.Equal(scopes, tt.wantScopes) {
+				t.Errorf("Scopes = %v, want %v", scopes, tt.wantScopes)
+			}
+			if !slices.Equal(got.Jars, tt.wantJars) {
+				t.Errorf("Jars = %v, want %v", got.Jars, tt.wantJars)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+-- ホストごとにJSONで保存していた cookies テーブルを、1行1Cookieの cookie テーブルへ移行します
+-- 既存の環境で1回だけ実行してください（新規の環境は schema.sql で初期化されるため不要です）
+--   psql -U postgres -d cookiejar -f migrations/0001_normalize_cookie.sql
+-- IS JSON 述語を使用するため PostgreSQL 16 以降が必要です
+BEGIN;
+
+CREATE TABLE cookie (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    value TEXT NOT NULL,
+    -- 小文字化し先頭のドットを取り除いたドメイン
+    domain TEXT NOT NULL,
+    host_only BOOLEAN NOT NULL DEFAULT FALSE,
+    path TEXT NOT NULL DEFAULT '/',
+    -- NULL の場合はセッションCookie
+    expires_at TIMESTAMPTZ,
+    secure BOOLEAN NOT NULL DEFAULT FALSE,
+    http_only BOOLEAN NOT NULL DEFAULT FALSE,
+    same_site TEXT NOT NULL DEFAULT '' CHECK (same_site IN ('', 'Lax', 'Strict', 'None')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (name, domain, host_only, path)
+);
+
+CREATE INDEX cookie_domain_idx ON cookie (domain);
+CREATE INDEX cookie_expires_at_idx ON cookie (expires_at) WHERE expires_at IS NOT NULL;
+
+WITH documents AS (
+    -- JSONとして解釈できない行はスキップする
+    SELECT host, updated_at, cookies::jsonb AS doc
+    FROM cookies
+    WHERE cookies IS JSON
+),
+elements AS (
+    -- 配列形式に加え、旧バージョンの単一オブジェクト形式も展開する
+    SELECT d.host, d.updated_at, e.cookie
+    FROM documents AS d
+    CROSS JOIN LATERAL jsonb_array_elements(
+        CASE jsonb_typeof(d.doc)
+            WHEN 'array' THEN d.doc
+            ELSE jsonb_build_array(d.doc)
+        END
+    ) AS e(cookie)
+),
+normalized AS (
+    -- entity.Cookie のJSON表現（Goのフィールド名）を各カラムに変換する
+    SELECT
+        cookie->>'Name' AS name,
+        COALESCE(cookie->>'Value', '') AS value,
+        lower(trim(BOTH '.' FROM COALESCE(NULLIF(cookie->>'Domain', ''), host))) AS domain,
+        COALESCE((cookie->>'HostOnly')::boolean, FALSE) AS host_only,
+        CASE WHEN left(cookie->>'Path', 1) = '/' THEN cookie->>'Path' ELSE '/' END AS path,
+        -- time.Time のゼロ値（0001-01-01T00:00:00Z）はセッションCookie
+        CASE
+            WHEN cookie->>'Expires' IS NULL OR cookie->>'Expires' LIKE '0001-01-01%' THEN NULL
+            ELSE (cookie->>'Expires')::timestamptz
+        END AS expires_at,
+        COALESCE((cookie->>'Secure')::boolean, FALSE) AS secure,
+        COALESCE((cookie->>'HttpOnly')::boolean, FALSE) AS http_only,
+        -- http.SameSite の数値表現（2: Lax, 3: Strict, 4: None）
+        CASE cookie->>'SameSite'
+            WHEN '2' THEN 'Lax'
+            WHEN '3' THEN 'Strict'
+            WHEN '4' THEN 'None'
+            ELSE ''
+        END AS same_site,
+        updated_at
+    FROM elements
+    WHERE jsonb_typeof(cookie) = 'object' AND COALESCE(cookie->>'Name', '') <> ''
+)
+-- 同じ識別キーのCookieが複数の行に存在する場合は最も新しく更新されたものを残す
+INSERT INTO cookie (name, value, domain, host_only, path, expires_at, secure, http_only, same_site, created_at, updated_at)
+SELECT DISTINCT ON (name, domain, host_only, path)
+    name, value, domain, host_only, path, expires_at, secure, http_only, same_site, updated_at, updated_at
+FROM normalized
+ORDER BY name, domain, host_only, path, updated_at DESC;
+
+-- 移行元のテーブルは確認用に残す（不要になったら DROP TABLE cookies_legacy で削除）
+ALTER TABLE cookies RENAME TO cookies_legacy;
+
+COMMIT;
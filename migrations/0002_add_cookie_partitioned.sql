@@ -0,0 +1,3 @@
+-- Partitioned 属性（CHIPS）を保存するカラムを追加します
+--   psql -U postgres -d cookiejar -f migrations/0002_add_cookie_partitioned.sql
+ALTER TABLE cookie ADD COLUMN partitioned BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,9 @@
+-- Cookie の最終アクセス時刻を保存するカラムを追加します（既存の Cookie は最後に設定された時刻で初期化します）
+--   psql -U postgres -d cookiejar -f migrations/0003_add_cookie_last_accessed_at.sql
+BEGIN;
+
+ALTER TABLE cookie ADD COLUMN last_accessed_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP;
+UPDATE cookie SET last_accessed_at = updated_at;
+CREATE INDEX cookie_last_accessed_at_idx ON cookie (last_accessed_at);
+
+COMMIT;
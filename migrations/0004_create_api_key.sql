@@ -0,0 +1,13 @@
+-- Writer / Reader の認証に使う API キーのテーブルを作成します
+--   psql -U postgres -d cookiejar -f migrations/0004_create_api_key.sql
+CREATE TABLE api_key (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    -- 識別用に表示するキーの先頭部分
+    prefix TEXT NOT NULL,
+    -- キーの SHA-256 ハッシュ（16進数）。キー自体は保存しない
+    key_hash TEXT NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    -- NULL の場合は有効
+    revoked_at TIMESTAMPTZ
+);
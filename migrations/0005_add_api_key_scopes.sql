@@ -0,0 +1,9 @@
+-- API キーの権限（read / write / delete）とホストパターンの組を保存するカラムを追加します
+-- 既存の API キーはすべてのホストに対するすべての権限で初期化します
+--   psql -U postgres -d cookiejar -f migrations/0005_add_api_key_scopes.sql
+BEGIN;
+
+ALTER TABLE api_key ADD COLUMN scopes TEXT[] NOT NULL DEFAULT '{read:*,write:*,delete:*}';
+ALTER TABLE api_key ALTER COLUMN scopes DROP DEFAULT;
+
+COMMIT;
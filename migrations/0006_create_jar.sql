@@ -0,0 +1,21 @@
+-- Cookie を jar ごとに分離するため jar テーブルを作成し、cookie テーブルに jar カラムを追加します
+-- 既存の Cookie は default jar に移動します
+--   psql -U postgres -d cookiejar -f migrations/0006_create_jar.sql
+BEGIN;
+
+CREATE TABLE jar (
+    name TEXT PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+INSERT INTO jar (name) VALUES ('default');
+
+ALTER TABLE cookie ADD COLUMN jar TEXT NOT NULL DEFAULT 'default' REFERENCES jar (name) ON DELETE CASCADE;
+ALTER TABLE cookie ALTER COLUMN jar DROP DEFAULT;
+
+ALTER TABLE cookie DROP CONSTRAINT cookie_name_domain_host_only_path_key;
+ALTER TABLE cookie ADD UNIQUE (jar, name, domain, host_only, path);
+
+DROP INDEX cookie_domain_idx;
+CREATE INDEX cookie_jar_domain_idx ON cookie (jar, domain);
+
+COMMIT;
@@ -0,0 +1,11 @@
+-- Partitioned Cookie（CHIPS）のパーティションキー（トップレベルサイト）を保存するカラムを追加し、識別キーに含めます
+-- 既存の Cookie はパーティションキーなし（空文字列）として扱います
+--   psql -U postgres -d cookiejar -f migrations/0007_add_cookie_partition_key.sql
+BEGIN;
+
+ALTER TABLE cookie ADD COLUMN partition_key TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE cookie DROP CONSTRAINT cookie_jar_name_domain_host_only_path_key;
+ALTER TABLE cookie ADD UNIQUE (jar, name, domain, host_only, path, partition_key);
+
+COMMIT;
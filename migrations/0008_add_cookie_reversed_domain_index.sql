@@ -0,0 +1,7 @@
+-- 登録可能ドメインごとのCookieの件数と削除で、サブドメインのCookieをインデックスで検索できるようにします
+--   psql -U postgres -d cookiejar -f migrations/0008_add_cookie_reversed_domain_index.sql
+BEGIN;
+
+CREATE INDEX cookie_jar_reversed_domain_idx ON cookie (jar, reverse(domain) text_pattern_ops);
+
+COMMIT;
@@ -0,0 +1,13 @@
+-- name: CreateAPIKey :one
+INSERT INTO api_key (name, prefix, key_hash, created_at, scopes)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetAPIKeyByHash :one
+SELECT * FROM api_key WHERE key_hash = $1;
+
+-- name: ListAPIKeys :many
+SELECT * FROM api_key ORDER BY id;
+
+-- name: RevokeAPIKey :execrows
+UPDATE api_key SET revoked_at = sqlc.arg(revoked_at) WHERE id = sqlc.arg(id) AND revoked_at IS NULL;
@@ -1,9 +1,59 @@
--- name: UpsertCookies :exec
-INSERT INTO cookies (host, cookies, updated_at) VALUES ($1, $2, $3)
-ON CONFLICT (host) DO UPDATE SET cookies = $2, updated_at = $3;
+-- name: UpsertCookie :exec
+INSERT INTO cookie (jar, name, value, domain, host_only, path, expires_at, secure, http_only, same_site, partitioned, partition_key, created_at, updated_at, last_accessed_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, sqlc.arg(updated_at), sqlc.arg(updated_at), sqlc.arg(updated_at))
+ON CONFLICT (jar, name, domain, host_only, path, partition_key) DO UPDATE SET
+    value = EXCLUDED.value,
+    expires_at = EXCLUDED.expires_at,
+    secure = EXCLUDED.secure,
+    http_only = EXCLUDED.http_only,
+    same_site = EXCLUDED.same_site,
+    partitioned = EXCLUDED.partitioned,
+    updated_at = EXCLUDED.updated_at,
+    last_accessed_at = EXCLUDED.last_accessed_at;
+
+-- name: DeleteCookie :execrows
+DELETE FROM cookie WHERE jar = $1 AND name = $2 AND domain = $3 AND host_only = $4 AND path = $5 AND partition_key = $6;
 
 -- name: ListCookies :many
-SELECT * FROM cookies;
+SELECT * FROM cookie WHERE jar = $1 ORDER BY domain, path, created_at, id;
+
+-- name: ListCookiesByDomain :many
+SELECT * FROM cookie WHERE jar = $1 AND domain = $2 ORDER BY path, created_at, id;
+
+-- name: ListCookiesByDomains :many
+SELECT * FROM cookie WHERE jar = sqlc.arg(jar) AND domain = ANY(sqlc.arg(domains)::text[]) ORDER BY domain, path, created_at, id;
+
+-- name: DeleteExpiredCookies :execrows
+DELETE FROM cookie WHERE id IN (
+    SELECT id FROM cookie WHERE expires_at <= sqlc.arg(now)::timestamptz ORDER BY expires_at LIMIT sqlc.arg(batch_size)
+);
+
+-- name: DeleteCookiesByDomain :execrows
+DELETE FROM cookie WHERE jar = $1 AND domain = $2;
+
+-- name: DeleteCookiesByFilter :execrows
+DELETE FROM cookie
+WHERE jar = sqlc.arg(jar)
+  AND (sqlc.narg(domain)::text IS NULL OR domain = sqlc.narg(domain))
+  AND (sqlc.narg(site)::text IS NULL OR domain = sqlc.narg(site) OR reverse(domain) LIKE sqlc.narg(subdomain_pattern))
+  AND (sqlc.narg(name_prefix)::text IS NULL OR starts_with(name, sqlc.narg(name_prefix)))
+  AND (NOT sqlc.arg(expired_only)::boolean OR expires_at <= sqlc.arg(now)::timestamptz);
+
+-- name: CountCookies :one
+SELECT COUNT(*) FROM cookie
+WHERE jar = sqlc.arg(jar)
+  AND (sqlc.narg(site)::text IS NULL OR domain = sqlc.narg(site) OR reverse(domain) LIKE sqlc.narg(subdomain_pattern));
+
+-- name: EvictLeastRecentlyUsedCookies :execrows
+DELETE FROM cookie WHERE id IN (
+    SELECT id FROM cookie
+    WHERE jar = sqlc.arg(jar)
+      AND (sqlc.narg(site)::text IS NULL OR domain = sqlc.narg(site) OR reverse(domain) LIKE sqlc.narg(subdomain_pattern))
+    ORDER BY last_accessed_at, id
+    LIMIT sqlc.arg(count)
+);
 
--- name: GetCookiesByHost :one
-SELECT * FROM cookies WHERE host = $1;
\ No newline at end of file
+-- name: TouchCookies :execrows
+UPDATE cookie SET last_accessed_at = sqlc.arg(accessed_at)
+FROM unnest(sqlc.arg(names)::text[], sqlc.arg(domains)::text[], sqlc.arg(host_onlys)::boolean[], sqlc.arg(paths)::text[], sqlc.arg(partition_keys)::text[]) AS k(name, domain, host_only, path, partition_key)
+WHERE cookie.jar = sqlc.arg(jar) AND cookie.name = k.name AND cookie.domain = k.domain AND cookie.host_only = k.host_only AND cookie.path = k.path AND cookie.partition_key = k.partition_key;
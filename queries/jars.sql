@@ -0,0 +1,22 @@
+-- name: CreateJar :one
+INSERT INTO jar (name, created_at) VALUES ($1, $2)
+RETURNING *;
+
+-- name: GetJar :one
+SELECT * FROM jar WHERE name = $1;
+
+-- name: ListJars :many
+SELECT jar.name, jar.created_at, COUNT(cookie.id) AS cookie_count
+FROM jar
+LEFT JOIN cookie ON cookie.jar = jar.name
+GROUP BY jar.name
+ORDER BY jar.name;
+
+-- name: DeleteJar :execrows
+DELETE FROM jar WHERE name = $1;
+
+-- name: CloneJarCookies :execrows
+INSERT INTO cookie (jar, name, value, domain, host_only, path, expires_at, secure, http_only, same_site, partitioned, partition_key, created_at, updated_at, last_accessed_at)
+SELECT sqlc.arg(dst)::text, name, value, domain, host_only, path, expires_at, secure, http_only, same_site, partitioned, partition_key, created_at, updated_at, last_accessed_at
+FROM cookie
+WHERE jar = sqlc.arg(src);
@@ -1,5 +1,52 @@
-CREATE TABLE cookies (
-    host TEXT PRIMARY KEY,
-    cookies TEXT NOT NULL,
-    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+CREATE TABLE jar (
+    -- Cookie を分離する単位（アカウントやボットごと）の名前
+    name TEXT PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- jar を指定しないリクエストで使う jar
+INSERT INTO jar (name) VALUES ('default');
+
+CREATE TABLE cookie (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    value TEXT NOT NULL,
+    -- 小文字化し先頭のドットを取り除いたドメイン
+    domain TEXT NOT NULL,
+    host_only BOOLEAN NOT NULL DEFAULT FALSE,
+    path TEXT NOT NULL DEFAULT '/',
+    -- NULL の場合はセッションCookie
+    expires_at TIMESTAMPTZ,
+    secure BOOLEAN NOT NULL DEFAULT FALSE,
+    http_only BOOLEAN NOT NULL DEFAULT FALSE,
+    same_site TEXT NOT NULL DEFAULT '' CHECK (same_site IN ('', 'Lax', 'Strict', 'None')),
+    partitioned BOOLEAN NOT NULL DEFAULT FALSE,
+    -- Partitioned Cookie のトップレベルサイト（"https://example.com" など）。空文字列の場合はパーティションなし
+    partition_key TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    -- 最後に設定または Reader から返却された時刻
+    last_accessed_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    jar TEXT NOT NULL REFERENCES jar (name) ON DELETE CASCADE,
+    UNIQUE (jar, name, domain, host_only, path, partition_key)
+);
+
+CREATE INDEX cookie_jar_domain_idx ON cookie (jar, domain);
+-- 登録可能ドメインとそのサブドメインのCookieを、逆順にしたドメインの前方一致で検索するためのインデックス
+CREATE INDEX cookie_jar_reversed_domain_idx ON cookie (jar, reverse(domain) text_pattern_ops);
+CREATE INDEX cookie_expires_at_idx ON cookie (expires_at) WHERE expires_at IS NOT NULL;
+CREATE INDEX cookie_last_accessed_at_idx ON cookie (last_accessed_at);
+
+CREATE TABLE api_key (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    -- 識別用に表示するキーの先頭部分
+    prefix TEXT NOT NULL,
+    -- キーの SHA-256 ハッシュ（16進数）。キー自体は保存しない
+    key_hash TEXT NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    -- NULL の場合は有効
+    revoked_at TIMESTAMPTZ,
+    -- 権限とホストパターンの組（"read:*.example.com" など）
+    scopes TEXT[] NOT NULL
 );